@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 
+	"github.com/EntySquare/solana-go-sdk/client"
 	"github.com/EntySquare/solana/common"
 	"github.com/EntySquare/solana/types"
 	"github.com/EntySquare/solana/utils"
@@ -15,7 +16,9 @@ func (c *Client) GetSOLBalance(ctx context.Context, base58Addr string) (uint64,
 		return 0, utils.StackErrors(ErrGetSolBalance, err)
 	}
 
-	balance, err := c.rpcClient.GetBalance(ctx, base58Addr)
+	balance, err := c.rpcClient.GetBalanceWithConfig(ctx, base58Addr, client.GetBalanceConfig{
+		Commitment: c.Commitment(ctx),
+	})
 	if err != nil {
 		return 0, utils.StackErrors(ErrGetSolBalance, err)
 	}
@@ -24,6 +27,7 @@ func (c *Client) GetSOLBalance(ctx context.Context, base58Addr string) (uint64,
 }
 
 // GetTokenBalance returns the SPL token balance of the given base58 encoded account address and SPL token mint address.
+// The associated token account is derived with the token program of the mint, SPL Token or Token-2022.
 // base58Addr is the base58 encoded account address.
 // base58MintAddr is the base58 encoded SPL token mint address.
 // Returns the balance in lamports and token decimals, or an error.
@@ -35,7 +39,16 @@ func (c *Client) GetTokenBalance(ctx context.Context, base58Addr, base58MintAddr
 		return types.TokenAmount{}, utils.StackErrors(ErrGetSplTokenBalance, err)
 	}
 
-	ata, err := common.DeriveTokenAccount(base58Addr, base58MintAddr)
+	tokenProgram, err := c.GetTokenProgramID(ctx, base58MintAddr)
+	if err != nil {
+		return types.TokenAmount{}, utils.StackErrors(ErrGetSplTokenBalance, err)
+	}
+
+	ata, err := common.DeriveTokenAccountPubkeyWithProgram(
+		common.PublicKeyFromBase58(base58Addr),
+		common.PublicKeyFromBase58(base58MintAddr),
+		tokenProgram,
+	)
 	if err != nil {
 		return types.TokenAmount{}, utils.StackErrors(ErrGetSplTokenBalance, ErrFindAssociatedTokenAddress, err)
 	}
@@ -47,7 +60,9 @@ func (c *Client) GetTokenBalance(ctx context.Context, base58Addr, base58MintAddr
 // base58Addr is the base58 encoded associated token account address.
 // Returns the balance in lamports and token decimals, or an error.
 func (c *Client) GetAtaBalance(ctx context.Context, base58Addr string) (types.TokenAmount, error) {
-	balance, err := c.rpcClient.GetTokenAccountBalance(ctx, base58Addr)
+	balance, err := c.rpcClient.GetTokenAccountBalanceWithConfig(ctx, base58Addr, client.GetTokenAccountBalanceConfig{
+		Commitment: c.Commitment(ctx),
+	})
 	if err != nil {
 		return types.TokenAmount{}, utils.StackErrors(ErrGetAtaBalance, ErrGetSplTokenBalance, err)
 	}
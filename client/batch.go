@@ -0,0 +1,234 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/EntySquare/solana-go-sdk/client"
+	"github.com/EntySquare/solana-go-sdk/common"
+	metaplex_token_metadata "github.com/EntySquare/solana-go-sdk/program/metaplex/token_metadata"
+	"github.com/EntySquare/solana-go-sdk/rpc"
+	"github.com/EntySquare/solana/token_metadata"
+	"github.com/EntySquare/solana/utils"
+	"github.com/near/borsh-go"
+)
+
+// maxMultipleAccounts is the max number of accounts of a single getMultipleAccounts request.
+const maxMultipleAccounts = 100
+
+// GetMultipleAccounts returns the accounts by the given base58 encoded addresses in the same order.
+// The addresses are split into the getMultipleAccounts requests of 100 accounts sent in a single JSON-RPC batch.
+// A missing account is returned as an empty account info.
+// Returns the accounts or an error.
+func (c *Client) GetMultipleAccounts(ctx context.Context, base58Addrs []string) ([]client.AccountInfo, error) {
+	var paramsList [][]any
+	for start := 0; start < len(base58Addrs); start += maxMultipleAccounts {
+		end := start + maxMultipleAccounts
+		if end > len(base58Addrs) {
+			end = len(base58Addrs)
+		}
+		paramsList = append(paramsList, []any{base58Addrs[start:end], map[string]any{
+			"encoding":   "base64",
+			"commitment": c.Commitment(ctx),
+		}})
+	}
+
+	pages, err := callBatch[rpc.ValueWithContext[[]*encodedAccount]](ctx, c, "getMultipleAccounts", paramsList)
+	if err != nil {
+		return nil, utils.StackErrors(ErrGetMultipleAccounts, err)
+	}
+
+	result := make([]client.AccountInfo, 0, len(base58Addrs))
+	for _, page := range pages {
+		for _, account := range page.Value {
+			if account == nil {
+				result = append(result, client.AccountInfo{})
+				continue
+			}
+			info, err := account.accountInfo()
+			if err != nil {
+				return nil, utils.StackErrors(ErrGetMultipleAccounts, err)
+			}
+			result = append(result, info)
+		}
+	}
+	if len(result) != len(base58Addrs) {
+		return nil, utils.StackErrors(
+			ErrGetMultipleAccounts,
+			fmt.Errorf("expected %d accounts, got %d", len(base58Addrs), len(result)),
+		)
+	}
+
+	return result, nil
+}
+
+// GetTokenMetadataBatch returns the metadata of the tokens by the given base58 encoded mint addresses in the same order.
+// The metadata, editions and parent master editions are loaded with one batch each.
+// The metadata of a mint without a metadata account is nil.
+// Returns the metadata or an error, including the one of an invalid metadata or edition account like GetTokenMetadata.
+func (c *Client) GetTokenMetadataBatch(ctx context.Context, base58MintAddrs []string) ([]*token_metadata.Metadata, error) {
+	mints := make([]common.PublicKey, len(base58MintAddrs))
+	metadataAddrs := make([]string, len(base58MintAddrs))
+	for i, addr := range base58MintAddrs {
+		mints[i] = common.PublicKeyFromString(addr)
+		metadataAccount, err := token_metadata.DeriveTokenMetadataPubkey(mints[i])
+		if err != nil {
+			return nil, utils.StackErrors(ErrGetTokenMetadata, err)
+		}
+		metadataAddrs[i] = metadataAccount.ToBase58()
+	}
+
+	metadataAccounts, err := c.GetMultipleAccounts(ctx, metadataAddrs)
+	if err != nil {
+		return nil, utils.StackErrors(ErrGetTokenMetadata, err)
+	}
+
+	result := make([]*token_metadata.Metadata, len(base58MintAddrs))
+	var nftIndexes []int
+	var editionAddrs []string
+	for i, account := range metadataAccounts {
+		if len(account.Data) == 0 {
+			continue
+		}
+		metadata, err := token_metadata.DeserializeMetadata(account.Data)
+		if err != nil {
+			return nil, utils.StackErrors(ErrGetTokenMetadata, fmt.Errorf("mint %s", base58MintAddrs[i]), err)
+		}
+		result[i] = metadata
+
+		if metadata.TokenStandard == token_metadata.TokenStandardNonFungible.String() ||
+			metadata.TokenStandard == token_metadata.TokenStandardNonFungibleEdition.String() {
+			editionPubkey, err := token_metadata.DeriveEditionPubkey(mints[i])
+			if err != nil {
+				return nil, utils.StackErrors(ErrGetTokenMetadata, err)
+			}
+			nftIndexes = append(nftIndexes, i)
+			editionAddrs = append(editionAddrs, editionPubkey.ToBase58())
+		}
+	}
+
+	editionAccounts, err := c.GetMultipleAccounts(ctx, editionAddrs)
+	if err != nil {
+		return nil, utils.StackErrors(ErrGetTokenMetadata, err)
+	}
+
+	// the print editions take the supply from the parent master editions
+	parents := make(map[string]client.AccountInfo)
+	var parentAddrs []string
+	for _, account := range editionAccounts {
+		var edition token_metadata.EditionData
+		if len(account.Data) == 0 || borsh.Deserialize(&edition, account.Data) != nil ||
+			edition.Key != metaplex_token_metadata.KeyEditionV1 || edition.Parent == token_metadata.PubNil {
+			continue
+		}
+		if _, ok := parents[edition.Parent.ToBase58()]; !ok {
+			parents[edition.Parent.ToBase58()] = client.AccountInfo{}
+			parentAddrs = append(parentAddrs, edition.Parent.ToBase58())
+		}
+	}
+
+	parentAccounts, err := c.GetMultipleAccounts(ctx, parentAddrs)
+	if err != nil {
+		return nil, utils.StackErrors(ErrGetTokenMetadata, err)
+	}
+	for i, addr := range parentAddrs {
+		parents[addr] = parentAccounts[i]
+	}
+	getParent := func(_ context.Context, base58Addr string) (client.AccountInfo, error) {
+		return parents[base58Addr], nil
+	}
+
+	for i, account := range editionAccounts {
+		if len(account.Data) == 0 {
+			continue
+		}
+		edition, err := token_metadata.DeserializeEdition(account.Data, getParent)
+		if err != nil {
+			return nil, utils.StackErrors(ErrGetTokenMetadata, fmt.Errorf("mint %s", base58MintAddrs[nftIndexes[i]]), err)
+		}
+		result[nftIndexes[i]].Edition = edition
+	}
+
+	return result, nil
+}
+
+// callBatch calls the json rpc method with each of the params in a single JSON-RPC batch request.
+// The calls are sent one by one if the client uses a custom solana client.
+// Returns the decoded results in the params order or an error.
+func callBatch[T any](ctx context.Context, c *Client, method string, paramsList [][]any) ([]T, error) {
+	results := make([]T, len(paramsList))
+	if len(paramsList) == 0 {
+		return results, nil
+	}
+
+	if c.rpcHTTP == nil || len(paramsList) == 1 {
+		for i, params := range paramsList {
+			result, err := callRPC[T](ctx, c, method, params...)
+			if err != nil {
+				return nil, err
+			}
+			results[i] = result
+		}
+		return results, nil
+	}
+
+	requests := make([]rpc.JsonRpcRequest, len(paramsList))
+	for i, params := range paramsList {
+		requests[i] = rpc.JsonRpcRequest{JsonRpc: "2.0", Id: uint64(i), Method: method, Params: params}
+	}
+	payload, err := json.Marshal(requests)
+	if err != nil {
+		return nil, fmt.Errorf("rpc: failed to encode %s batch: %w", method, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("rpc: failed to create %s batch request: %w", method, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.rpcHTTP.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("rpc: call %s batch error: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("rpc: failed to read %s batch response: %w", method, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("rpc: call %s batch error: status code %d, body: %s", method, resp.StatusCode, string(body))
+	}
+
+	var responses []rpc.JsonRpcResponse[T]
+	if err := json.Unmarshal(body, &responses); err != nil {
+		return nil, fmt.Errorf("rpc: failed to decode %s batch response: %w", method, err)
+	}
+	if len(responses) != len(paramsList) {
+		return nil, fmt.Errorf("rpc: expected %d %s batch responses, got %d", len(paramsList), method, len(responses))
+	}
+
+	// the responses of a batch may come in any order;
+	// their number is checked above, so a duplicate id means a missing one
+	seen := make([]bool, len(results))
+	for _, r := range responses {
+		if r.Error != nil {
+			return nil, r.Error
+		}
+		if r.Id >= uint64(len(results)) {
+			return nil, fmt.Errorf("rpc: unexpected %s batch response id %d", method, r.Id)
+		}
+		if seen[r.Id] {
+			return nil, fmt.Errorf("rpc: duplicate %s batch response id %d", method, r.Id)
+		}
+		seen[r.Id] = true
+		results[r.Id] = r.Result
+	}
+
+	return results, nil
+}
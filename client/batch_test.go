@@ -0,0 +1,250 @@
+package client_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	metaplex_token_metadata "github.com/EntySquare/solana-go-sdk/program/metaplex/token_metadata"
+	sdktypes "github.com/EntySquare/solana-go-sdk/types"
+	"github.com/EntySquare/solana/client"
+	"github.com/EntySquare/solana/token_metadata"
+	"github.com/EntySquare/solana/utils"
+	"github.com/near/borsh-go"
+	"github.com/stretchr/testify/require"
+)
+
+// newAccountsServer starts a json rpc server which serves getMultipleAccounts from the given accounts data.
+// It accepts the batch requests and counts the http requests.
+func newAccountsServer(t *testing.T, accounts map[string][]byte, requests *int32) *httptest.Server {
+	type request struct {
+		ID     uint64            `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+
+	handle := func(req request) map[string]any {
+		require.Equal(t, "getMultipleAccounts", req.Method)
+
+		var addrs []string
+		require.NoError(t, json.Unmarshal(req.Params[0], &addrs))
+		require.LessOrEqual(t, len(addrs), 100)
+
+		value := make([]any, 0, len(addrs))
+		for _, addr := range addrs {
+			data, ok := accounts[addr]
+			if !ok {
+				value = append(value, nil)
+				continue
+			}
+			value = append(value, map[string]any{
+				"lamports":   1,
+				"owner":      common.TokenProgramID.ToBase58(),
+				"data":       []string{base64.StdEncoding.EncodeToString(data), "base64"},
+				"executable": false,
+				"rentEpoch":  0,
+			})
+		}
+
+		return map[string]any{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result":  map[string]any{"context": map[string]any{"slot": 1}, "value": value},
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+
+		var raw json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if raw[0] == '[' {
+			var batch []request
+			require.NoError(t, json.Unmarshal(raw, &batch))
+			responses := make([]any, 0, len(batch))
+			// answer in the reverse order, the client must match the ids
+			for i := len(batch) - 1; i >= 0; i-- {
+				responses = append(responses, handle(batch[i]))
+			}
+			json.NewEncoder(w).Encode(responses)
+			return
+		}
+
+		var req request
+		require.NoError(t, json.Unmarshal(raw, &req))
+		json.NewEncoder(w).Encode(handle(req))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestGetMultipleAccounts(t *testing.T) {
+	accounts := make(map[string][]byte)
+	addrs := make([]string, 0, 150)
+	for i := 0; i < 150; i++ {
+		addr := sdktypes.NewAccount().PublicKey.ToBase58()
+		addrs = append(addrs, addr)
+		if i%3 != 0 {
+			accounts[addr] = []byte{byte(i)}
+		}
+	}
+
+	var requests int32
+	server := newAccountsServer(t, accounts, &requests)
+	c := client.New(client.SetSolanaEndpoint(server.URL))
+
+	result, err := c.GetMultipleAccounts(context.Background(), addrs)
+	require.NoError(t, err)
+	require.Len(t, result, 150)
+	require.EqualValues(t, 1, atomic.LoadInt32(&requests))
+
+	for i, account := range result {
+		if i%3 == 0 {
+			require.Empty(t, account.Data)
+			require.Equal(t, common.PublicKey{}, account.Owner)
+			continue
+		}
+		require.Equal(t, []byte{byte(i)}, account.Data)
+	}
+}
+
+func TestGetMultipleAccounts_BatchStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// a valid empty batch body, but not a successful status
+		w.WriteHeader(http.StatusMultipleChoices)
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	addrs := make([]string, 0, 150)
+	for i := 0; i < 150; i++ {
+		addrs = append(addrs, sdktypes.NewAccount().PublicKey.ToBase58())
+	}
+
+	c := client.New(client.SetSolanaEndpoint(server.URL))
+	_, err := c.GetMultipleAccounts(context.Background(), addrs)
+	require.ErrorContains(t, err, "status code 300")
+}
+
+func TestGetMultipleAccounts_BatchDuplicateID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the first response is repeated instead of the second one
+		response := map[string]any{
+			"jsonrpc": "2.0",
+			"id":      0,
+			"result":  map[string]any{"context": map[string]any{"slot": 1}, "value": make([]any, 100)},
+		}
+		json.NewEncoder(w).Encode([]any{response, response})
+	}))
+	defer server.Close()
+
+	addrs := make([]string, 0, 150)
+	for i := 0; i < 150; i++ {
+		addrs = append(addrs, sdktypes.NewAccount().PublicKey.ToBase58())
+	}
+
+	c := client.New(client.SetSolanaEndpoint(server.URL))
+	_, err := c.GetMultipleAccounts(context.Background(), addrs)
+	require.ErrorContains(t, err, "duplicate getMultipleAccounts batch response id 0")
+}
+
+func TestGetTokenMetadataBatch(t *testing.T) {
+	masterMint := sdktypes.NewAccount().PublicKey
+	printMint := sdktypes.NewAccount().PublicKey
+	missingMint := sdktypes.NewAccount().PublicKey
+	parentEdition := sdktypes.NewAccount().PublicKey
+
+	accounts := make(map[string][]byte)
+	addMetadata := func(mint common.PublicKey, standard metaplex_token_metadata.TokenStandard) {
+		data, err := borsh.Serialize(metaplex_token_metadata.Metadata{
+			Key:           metaplex_token_metadata.KeyMetadataV1,
+			Mint:          mint,
+			Data:          metaplex_token_metadata.Data{Name: "NFT", Symbol: "NFT"},
+			TokenStandard: &standard,
+		})
+		require.NoError(t, err)
+		addr, err := token_metadata.DeriveTokenMetadataPubkey(mint)
+		require.NoError(t, err)
+		accounts[addr.ToBase58()] = data
+	}
+	addEdition := func(mint common.PublicKey, data any) {
+		b, err := borsh.Serialize(data)
+		require.NoError(t, err)
+		addr, err := token_metadata.DeriveEditionPubkey(mint)
+		require.NoError(t, err)
+		accounts[addr.ToBase58()] = b
+	}
+
+	addMetadata(masterMint, metaplex_token_metadata.NonFungible)
+	addEdition(masterMint, metaplex_token_metadata.MasterEditionV2{
+		Key:       metaplex_token_metadata.KeyMasterEditionV2,
+		Supply:    1,
+		MaxSupply: utils.Pointer[uint64](10),
+	})
+	addMetadata(printMint, metaplex_token_metadata.NonFungibleEdition)
+	addEdition(printMint, token_metadata.EditionData{
+		Key:     metaplex_token_metadata.KeyEditionV1,
+		Parent:  parentEdition,
+		Edition: 3,
+	})
+	parentData, err := borsh.Serialize(metaplex_token_metadata.MasterEditionV2{
+		Key:       metaplex_token_metadata.KeyMasterEditionV2,
+		Supply:    5,
+		MaxSupply: utils.Pointer[uint64](100),
+	})
+	require.NoError(t, err)
+	accounts[parentEdition.ToBase58()] = parentData
+
+	var requests int32
+	server := newAccountsServer(t, accounts, &requests)
+	c := client.New(client.SetSolanaEndpoint(server.URL))
+
+	result, err := c.GetTokenMetadataBatch(context.Background(), []string{
+		masterMint.ToBase58(),
+		printMint.ToBase58(),
+		missingMint.ToBase58(),
+	})
+	require.NoError(t, err)
+	require.Len(t, result, 3)
+	// metadata, editions and parent master editions
+	require.EqualValues(t, 3, atomic.LoadInt32(&requests))
+
+	require.NotNil(t, result[0])
+	require.Equal(t, masterMint.ToBase58(), result[0].Mint)
+	require.Equal(t, &token_metadata.Edition{
+		Type:      token_metadata.KeyMasterEdition.String(),
+		Supply:    1,
+		MaxSupply: 10,
+	}, result[0].Edition)
+
+	require.NotNil(t, result[1])
+	require.Equal(t, uint64(3), result[1].Edition.Edition)
+	require.Equal(t, uint64(5), result[1].Edition.Supply)
+	require.Equal(t, uint64(100), result[1].Edition.MaxSupply)
+
+	require.Nil(t, result[2])
+}
+
+func TestGetTokenMetadataBatch_InvalidMetadata(t *testing.T) {
+	mint := sdktypes.NewAccount().PublicKey
+	addr, err := token_metadata.DeriveTokenMetadataPubkey(mint)
+	require.NoError(t, err)
+
+	var requests int32
+	server := newAccountsServer(t, map[string][]byte{addr.ToBase58(): {1, 2, 3}}, &requests)
+	c := client.New(client.SetSolanaEndpoint(server.URL))
+
+	_, err = c.GetTokenMetadataBatch(context.Background(), []string{mint.ToBase58()})
+	require.ErrorIs(t, err, client.ErrGetTokenMetadata)
+	require.ErrorContains(t, err, mint.ToBase58())
+}
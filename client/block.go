@@ -0,0 +1,29 @@
+package client
+
+import (
+	"context"
+
+	"github.com/EntySquare/solana/utils"
+)
+
+// GetSlot returns the current slot the node is processing.
+// Returns the slot or an error.
+func (c *Client) GetSlot(ctx context.Context) (uint64, error) {
+	slot, err := c.rpcClient.GetSlot(ctx)
+	if err != nil {
+		return 0, utils.StackErrors(ErrGetSlot, err)
+	}
+
+	return slot, nil
+}
+
+// GetBlockHeight returns the current block height of the node.
+// Returns the block height or an error.
+func (c *Client) GetBlockHeight(ctx context.Context) (uint64, error) {
+	height, err := callRPC[uint64](ctx, c, "getBlockHeight")
+	if err != nil {
+		return 0, utils.StackErrors(ErrGetBlockHeight, err)
+	}
+
+	return height, nil
+}
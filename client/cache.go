@@ -0,0 +1,263 @@
+package client
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/rpc"
+	sdktypes "github.com/EntySquare/solana-go-sdk/types"
+)
+
+// Cache kinds of the values read by the client.
+const (
+	cacheKindMint          = "mint"
+	cacheKindTokenMetadata = "token_metadata"
+	cacheKindMasterEdition = "master_edition"
+	cacheKindTokenList     = "token_list"
+	cacheKindTokenProgram  = "token_program"
+	cacheKindLookupTable   = "lookup_table"
+)
+
+type (
+	// CacheKey identifies the cached value.
+	CacheKey struct {
+		Kind       string         // kind of the value, e.g. mint
+		Account    string         // base58 encoded account the value is read from
+		Commitment rpc.Commitment // commitment the account is read at
+	}
+
+	// Cache is the read-through cache of the values read from the accounts.
+	// The implementation must be safe for concurrent use.
+	Cache interface {
+		// Get returns the value and true if it is cached and not expired.
+		Get(key CacheKey) (any, bool)
+		// Set caches the value for the ttl.
+		// The value is invalidated with its account and with the accounts it depends on.
+		Set(key CacheKey, value any, ttl time.Duration, dependsOn ...string)
+		// Invalidate removes the values read from the base58 encoded accounts or depending on them.
+		Invalidate(accounts ...string)
+	}
+
+	// LRUCache is the in-memory cache which evicts the least recently used values.
+	LRUCache struct {
+		mu       sync.Mutex
+		size     int
+		items    *list.List
+		entries  map[CacheKey]*list.Element
+		accounts map[string]map[CacheKey]struct{} // account -> keys of the values depending on it
+	}
+
+	lruEntry struct {
+		key      CacheKey
+		value    any
+		expires  time.Time
+		accounts []string
+	}
+)
+
+// NewLRUCache creates a new in-memory cache of at most size values.
+func NewLRUCache(size int) *LRUCache {
+	if size < 1 {
+		size = 1
+	}
+
+	return &LRUCache{
+		size:     size,
+		items:    list.New(),
+		entries:  make(map[CacheKey]*list.Element),
+		accounts: make(map[string]map[CacheKey]struct{}),
+	}
+}
+
+// Get returns the value and true if it is cached and not expired.
+func (l *LRUCache) Get(key CacheKey) (any, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	item, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := item.Value.(*lruEntry)
+	if time.Now().After(entry.expires) {
+		l.removeLocked(item)
+		return nil, false
+	}
+	l.items.MoveToFront(item)
+
+	return entry.value, true
+}
+
+// Set caches the value for the ttl.
+// The value is invalidated with its account and with the accounts it depends on.
+func (l *LRUCache) Set(key CacheKey, value any, ttl time.Duration, dependsOn ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if item, ok := l.entries[key]; ok {
+		l.removeLocked(item)
+	}
+
+	entry := &lruEntry{
+		key:      key,
+		value:    value,
+		expires:  time.Now().Add(ttl),
+		accounts: append([]string{key.Account}, dependsOn...),
+	}
+	l.entries[key] = l.items.PushFront(entry)
+	for _, account := range entry.accounts {
+		if l.accounts[account] == nil {
+			l.accounts[account] = make(map[CacheKey]struct{})
+		}
+		l.accounts[account][key] = struct{}{}
+	}
+
+	for l.items.Len() > l.size {
+		l.removeLocked(l.items.Back())
+	}
+}
+
+// Invalidate removes the values read from the base58 encoded accounts or depending on them.
+func (l *LRUCache) Invalidate(accounts ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, account := range accounts {
+		for key := range l.accounts[account] {
+			if item, ok := l.entries[key]; ok {
+				l.removeLocked(item)
+			}
+		}
+	}
+}
+
+// Len returns the number of the cached values, including the expired ones not evicted yet.
+func (l *LRUCache) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.items.Len()
+}
+
+// removeLocked removes the cached value.
+// Must be called with l.mu locked.
+func (l *LRUCache) removeLocked(item *list.Element) {
+	entry := item.Value.(*lruEntry)
+	l.items.Remove(item)
+	delete(l.entries, entry.key)
+	for _, account := range entry.accounts {
+		delete(l.accounts[account], entry.key)
+		if len(l.accounts[account]) == 0 {
+			delete(l.accounts, account)
+		}
+	}
+}
+
+// InvalidateCache removes the cached values read from the base58 encoded accounts or depending on them.
+// The writable accounts of the transactions sent by the client are invalidated automatically.
+func (c *Client) InvalidateCache(base58Addrs ...string) {
+	if c.cache != nil {
+		c.cache.Invalidate(base58Addrs...)
+	}
+}
+
+// cached returns the cached value of the key or loads and caches it.
+// The load function returns the value and the accounts it depends on besides the key account.
+func cached[T any](c *Client, key CacheKey, load func() (T, []string, error)) (T, error) {
+	if c.cache != nil {
+		if v, ok := c.cache.Get(key); ok {
+			if value, ok := v.(T); ok {
+				return value, nil
+			}
+		}
+	}
+
+	value, dependsOn, err := load()
+	if err != nil {
+		return value, err
+	}
+	if c.cache != nil {
+		c.cache.Set(key, value, c.cacheTTL, dependsOn...)
+	}
+
+	return value, nil
+}
+
+// invalidateWritableAccounts invalidates the cached values of the writable accounts of the sent transaction,
+// including the ones loaded from the address lookup tables.
+// Returns the invalidated accounts to invalidate again once the transaction is confirmed; nil if the client has no cache.
+func (c *Client) invalidateWritableAccounts(ctx context.Context, tx sdktypes.Transaction) []string {
+	accounts := c.writableAccounts(ctx, tx)
+	if len(accounts) > 0 {
+		c.cache.Invalidate(accounts...)
+	}
+
+	return accounts
+}
+
+// writableAccounts returns the base58 encoded writable accounts of the transaction; nil if the client has no cache.
+// The accounts of the address lookup tables that can not be loaded are skipped.
+func (c *Client) writableAccounts(ctx context.Context, tx sdktypes.Transaction) []string {
+	if c.cache == nil {
+		return nil
+	}
+
+	header := tx.Message.Header
+	signers := int(header.NumRequireSignatures)
+	accounts := make([]string, 0, len(tx.Message.Accounts))
+	for i, account := range tx.Message.Accounts {
+		writable := i < signers-int(header.NumReadonlySignedAccounts) ||
+			(i >= signers && i < len(tx.Message.Accounts)-int(header.NumReadonlyUnsignedAccounts))
+		if writable {
+			accounts = append(accounts, account.ToBase58())
+		}
+	}
+
+	for _, lookup := range tx.Message.AddressLookupTables {
+		if len(lookup.WritableIndexes) == 0 {
+			continue
+		}
+		addresses, err := c.lookupTableAddresses(ctx, lookup.AccountKey.ToBase58(), lookup.WritableIndexes)
+		if err != nil {
+			continue
+		}
+		for _, idx := range lookup.WritableIndexes {
+			if int(idx) < len(addresses) {
+				accounts = append(accounts, addresses[idx].ToBase58())
+			}
+		}
+	}
+
+	return accounts
+}
+
+// lookupTableAddresses returns the addresses of the lookup table cached by GetLookupTableAddresses
+// if they include the indexes, so the tables resolved by the transaction builder are not fetched again;
+// otherwise fetches and caches them. The addresses are only appended to the tables, so the cached indexes never change.
+// Must be called with the client cache set.
+func (c *Client) lookupTableAddresses(ctx context.Context, base58Addr string, indexes []uint8) ([]common.PublicKey, error) {
+	maxIdx := 0
+	for _, idx := range indexes {
+		if int(idx) > maxIdx {
+			maxIdx = int(idx)
+		}
+	}
+
+	key := CacheKey{Kind: cacheKindLookupTable, Account: base58Addr, Commitment: c.Commitment(ctx)}
+	if v, ok := c.cache.Get(key); ok {
+		if addresses, ok := v.([]common.PublicKey); ok && maxIdx < len(addresses) {
+			return addresses, nil
+		}
+	}
+
+	table, err := c.GetLookupTable(ctx, base58Addr)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.Set(key, table.Addresses, c.cacheTTL)
+
+	return table.Addresses, nil
+}
@@ -0,0 +1,287 @@
+package client_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	metaplex_token_metadata "github.com/EntySquare/solana-go-sdk/program/metaplex/token_metadata"
+	"github.com/EntySquare/solana-go-sdk/program/system"
+	"github.com/EntySquare/solana-go-sdk/program/token"
+	sdktypes "github.com/EntySquare/solana-go-sdk/types"
+	"github.com/EntySquare/solana/client"
+	"github.com/EntySquare/solana/tests/mock"
+	"github.com/EntySquare/solana/token_metadata"
+	"github.com/EntySquare/solana/types"
+	"github.com/EntySquare/solana/utils"
+	"github.com/near/borsh-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUCache(t *testing.T) {
+	cache := client.NewLRUCache(2)
+	key := func(account string) client.CacheKey {
+		return client.CacheKey{Kind: "test", Account: account, Commitment: "finalized"}
+	}
+
+	cache.Set(key("a"), 1, time.Hour)
+	cache.Set(key("b"), 2, time.Hour, "dep")
+	_, ok := cache.Get(key("a"))
+	require.True(t, ok)
+
+	// b is the least recently used value
+	cache.Set(key("c"), 3, time.Hour)
+	require.Equal(t, 2, cache.Len())
+	_, ok = cache.Get(key("b"))
+	require.False(t, ok)
+
+	// the commitment is part of the key
+	_, ok = cache.Get(client.CacheKey{Kind: "test", Account: "a", Commitment: "confirmed"})
+	require.False(t, ok)
+
+	cache.Set(key("d"), 4, time.Hour, "dep")
+	cache.Invalidate("dep")
+	_, ok = cache.Get(key("d"))
+	require.False(t, ok)
+
+	cache.Set(key("e"), 5, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	_, ok = cache.Get(key("e"))
+	require.False(t, ok)
+}
+
+func TestGetMintInfo_Cache(t *testing.T) {
+	mint := sdktypes.NewAccount().PublicKey
+	mintData := make([]byte, token.MintAccountSize)
+	mintData[44] = 6 // decimals
+	mintData[45] = 1 // initialized
+
+	var accountRequests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64 `json:"id"`
+			Method string `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var result any
+		switch req.Method {
+		case "getAccountInfo":
+			atomic.AddInt32(&accountRequests, 1)
+			result = map[string]any{
+				"context": map[string]any{"slot": 1},
+				"value": map[string]any{
+					"lamports":   1,
+					"owner":      common.TokenProgramID.ToBase58(),
+					"data":       []string{base64.StdEncoding.EncodeToString(mintData), "base64"},
+					"executable": false,
+					"rentEpoch":  0,
+				},
+			}
+		case "sendTransaction":
+			result = testTxSignature
+		default:
+			t.Errorf("unexpected method %s", req.Method)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	defer server.Close()
+
+	c := client.New(client.SetSolanaEndpoint(server.URL), client.SetCache(client.NewLRUCache(100), time.Hour))
+
+	for i := 0; i < 2; i++ {
+		mintInfo, err := c.GetMintInfo(context.Background(), mint.ToBase58())
+		require.NoError(t, err)
+		require.EqualValues(t, 6, mintInfo.Decimals)
+	}
+	require.EqualValues(t, 1, atomic.LoadInt32(&accountRequests))
+
+	// sending a transaction which writes the mint invalidates it
+	payer := sdktypes.NewAccount()
+	tx, err := sdktypes.NewTransaction(sdktypes.NewTransactionParam{
+		Message: sdktypes.NewMessage(sdktypes.NewMessageParam{
+			FeePayer:        payer.PublicKey,
+			RecentBlockhash: "FuQhSmAT6kAmmzCMiiYbzFcTQJFuu6raXAdCFibz4YPR",
+			Instructions: []sdktypes.Instruction{
+				system.Transfer(system.TransferParam{From: payer.PublicKey, To: mint, Amount: 1}),
+			},
+		}),
+		Signers: []sdktypes.Account{payer},
+	})
+	require.NoError(t, err)
+	txSource, err := utils.EncodeTransaction(tx)
+	require.NoError(t, err)
+
+	_, err = c.SendTransaction(context.Background(), txSource)
+	require.NoError(t, err)
+
+	_, err = c.GetMintInfo(context.Background(), mint.ToBase58())
+	require.NoError(t, err)
+	require.EqualValues(t, 2, atomic.LoadInt32(&accountRequests))
+}
+
+// accountReads returns the number of the getAccountInfo requests of the address.
+func accountReads(fake *mock.RPCServer, address common.PublicKey) int {
+	reads := 0
+	for _, req := range fake.RequestsOf("getAccountInfo") {
+		if strings.Contains(string(req.Params[0]), address.ToBase58()) {
+			reads++
+		}
+	}
+	return reads
+}
+
+func TestSendTransaction_InvalidatesLookupTableAccounts(t *testing.T) {
+	ctx := context.Background()
+	fake := mock.NewRPCServer()
+	defer fake.Close()
+
+	payer := sdktypes.NewAccount()
+	mint := sdktypes.NewAccount().PublicKey
+	table := sdktypes.NewAccount().PublicKey
+	fake.SetMint(mint.ToBase58(), token.MintAccount{Decimals: 6, IsInitialized: true})
+	fake.SetLookupTable(table.ToBase58(), payer.PublicKey, []common.PublicKey{sdktypes.NewAccount().PublicKey, mint})
+
+	c := client.New(client.SetSolanaEndpoint(fake.URL), client.SetCache(client.NewLRUCache(100), time.Hour))
+	_, err := c.GetMintInfo(ctx, mint.ToBase58())
+	require.NoError(t, err)
+
+	// the mint is loaded from the lookup table
+	tx, err := sdktypes.NewTransaction(sdktypes.NewTransactionParam{
+		Message: sdktypes.NewMessage(sdktypes.NewMessageParam{
+			FeePayer:        payer.PublicKey,
+			RecentBlockhash: mock.DefaultBlockhash,
+			Instructions: []sdktypes.Instruction{
+				system.Transfer(system.TransferParam{From: payer.PublicKey, To: mint, Amount: 1}),
+			},
+			AddressLookupTableAccounts: []sdktypes.AddressLookupTableAccount{
+				{Key: table, Addresses: []common.PublicKey{sdktypes.NewAccount().PublicKey, mint}},
+			},
+		}),
+		Signers: []sdktypes.Account{payer},
+	})
+	require.NoError(t, err)
+	require.NotContains(t, tx.Message.Accounts, mint)
+	txSource, err := utils.EncodeTransaction(tx)
+	require.NoError(t, err)
+
+	_, err = c.SendTransaction(ctx, txSource)
+	require.NoError(t, err)
+
+	_, err = c.GetMintInfo(ctx, mint.ToBase58())
+	require.NoError(t, err)
+	require.Equal(t, 2, accountReads(fake, mint))
+	require.Equal(t, 1, accountReads(fake, table))
+
+	// the table resolved by the builder is not fetched again
+	_, err = c.GetLookupTableAddresses(ctx, table.ToBase58())
+	require.NoError(t, err)
+	_, err = c.SendTransaction(ctx, txSource)
+	require.NoError(t, err)
+	require.Equal(t, 2, accountReads(fake, table))
+	_, err = c.GetMintInfo(ctx, mint.ToBase58())
+	require.NoError(t, err)
+	require.Equal(t, 3, accountReads(fake, mint))
+
+	// the client without the cache does not fetch the table
+	_, err = client.New(client.SetSolanaEndpoint(fake.URL)).SendTransaction(ctx, txSource)
+	require.NoError(t, err)
+	require.Equal(t, 2, accountReads(fake, table))
+
+	require.Panics(t, func() { client.New(client.SetCache(client.NewLRUCache(100), 0)) })
+}
+
+func TestWaitForTransactionConfirmed_InvalidatesCache(t *testing.T) {
+	ctx := context.Background()
+	fake := mock.NewRPCServer()
+	defer fake.Close()
+
+	payer := sdktypes.NewAccount()
+	mint := sdktypes.NewAccount().PublicKey
+	fake.SetMint(mint.ToBase58(), token.MintAccount{Decimals: 6, IsInitialized: true})
+
+	c := client.New(client.SetSolanaEndpoint(fake.URL), client.SetCache(client.NewLRUCache(100), time.Hour))
+
+	tx, err := sdktypes.NewTransaction(sdktypes.NewTransactionParam{
+		Message: sdktypes.NewMessage(sdktypes.NewMessageParam{
+			FeePayer:        payer.PublicKey,
+			RecentBlockhash: mock.DefaultBlockhash,
+			Instructions: []sdktypes.Instruction{
+				system.Transfer(system.TransferParam{From: payer.PublicKey, To: mint, Amount: 1}),
+			},
+		}),
+		Signers: []sdktypes.Account{payer},
+	})
+	require.NoError(t, err)
+	txSource, err := utils.EncodeTransaction(tx)
+	require.NoError(t, err)
+
+	txhash, err := c.SendTransaction(ctx, txSource)
+	require.NoError(t, err)
+
+	// the value read while the transaction is pending is cached
+	for i := 0; i < 2; i++ {
+		_, err = c.GetMintInfo(ctx, mint.ToBase58())
+		require.NoError(t, err)
+	}
+	require.Equal(t, 1, accountReads(fake, mint))
+
+	status, err := c.WaitForTransactionConfirmed(ctx, txhash, time.Second, client.WithPollInterval(10*time.Millisecond))
+	require.NoError(t, err)
+	require.Equal(t, types.TransactionStatusSuccess, status)
+
+	_, err = c.GetMintInfo(ctx, mint.ToBase58())
+	require.NoError(t, err)
+	require.Equal(t, 2, accountReads(fake, mint))
+}
+
+func TestGetTokenMetadata_CacheCopy(t *testing.T) {
+	ctx := context.Background()
+	fake := mock.NewRPCServer()
+	defer fake.Close()
+
+	mint := sdktypes.NewAccount().PublicKey
+	creator := sdktypes.NewAccount().PublicKey
+	standard := metaplex_token_metadata.Fungible
+	data, err := borsh.Serialize(metaplex_token_metadata.Metadata{
+		Key:  metaplex_token_metadata.KeyMetadataV1,
+		Mint: mint,
+		Data: metaplex_token_metadata.Data{
+			Name:     "Token",
+			Symbol:   "TKN",
+			Creators: &[]metaplex_token_metadata.Creator{{Address: creator, Verified: true, Share: 100}},
+		},
+		TokenStandard: &standard,
+		Collection:    &metaplex_token_metadata.Collection{Key: sdktypes.NewAccount().PublicKey},
+	})
+	require.NoError(t, err)
+	metadataAccount, err := token_metadata.DeriveTokenMetadataPubkey(mint)
+	require.NoError(t, err)
+	fake.SetAccount(metadataAccount.ToBase58(), mock.RPCAccount{Lamports: 1, Owner: common.MetaplexTokenMetaProgramID, Data: data})
+
+	c := client.New(client.SetSolanaEndpoint(fake.URL), client.SetCache(client.NewLRUCache(100), time.Hour))
+
+	metadata, err := c.GetTokenMetadata(ctx, mint.ToBase58())
+	require.NoError(t, err)
+	require.Len(t, metadata.Creators, 1)
+	metadata.Creators[0].Address = "mutated"
+	metadata.Collection.Key = "mutated"
+	metadata.Data.Name = "mutated"
+
+	cachedMetadata, err := c.GetTokenMetadata(ctx, mint.ToBase58())
+	require.NoError(t, err)
+	require.Equal(t, 1, accountReads(fake, metadataAccount))
+	require.Equal(t, creator.ToBase58(), cachedMetadata.Creators[0].Address)
+	require.NotEqual(t, "mutated", cachedMetadata.Collection.Key)
+	require.Equal(t, "Token", cachedMetadata.Data.Name)
+}
@@ -1,19 +1,48 @@
 package client
 
 import (
+	"context"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/EntySquare/solana-go-sdk/client"
+	"github.com/EntySquare/solana-go-sdk/rpc"
 	"github.com/EntySquare/solana/types"
 )
 
+// pubsubDialBackoff is the time after a failed PubSub dial during which the dial is not retried.
+const pubsubDialBackoff = 30 * time.Second
+
 type (
 	// Solana client wrapper
 	Client struct {
 		rpcClient       *client.Client
+		rpcHTTP         *http.Client // nil if the client uses a custom solana client
 		http            *http.Client
 		defaultDecimals uint8
 		tokenListPath   string
+		endpoint        string
+		wsEndpoint      string
+		pubsubEnabled   bool // set by SetWebsocketEndpoint
+		pubsubOpts      []PubSubOption
+		pubsub          *PubSub
+		pubsubErr       error     // the error of the last failed dial
+		pubsubRetryAt   time.Time // the failed dial is not retried before
+		pubsubMu        sync.Mutex
+		pool            *RPCPool
+		rateLimiter     *RateLimiter
+		interceptors    []RPCInterceptor
+		transport       http.RoundTripper
+		cache           Cache
+		cacheTTL        time.Duration
+		commitment      rpc.Commitment
+		blockhashes     map[string]uint64 // blockhash -> last valid block height
+		blockhashesMu   sync.Mutex
+
+		sentTransactions   map[string]sentTransaction // signature -> sent transaction, until it is confirmed
+		sentTransactionsMu sync.Mutex
 	}
 
 	ClientOption func(*Client)
@@ -22,7 +51,7 @@ type (
 // WithCustomSolanaClient sets a custom solana client
 func WithCustomSolanaClient(solana *client.Client) ClientOption {
 	return func(c *Client) {
-		if c.rpcClient != nil {
+		if c.rpcClient != nil || c.endpoint != "" {
 			panic("solana client is already set")
 		}
 		c.rpcClient = solana
@@ -39,10 +68,104 @@ func WithCustomDecimals(decimals uint8) ClientOption {
 // SetSolanaEndpoint sets the solana endpoint
 func SetSolanaEndpoint(endpoint string) ClientOption {
 	return func(c *Client) {
-		if c.rpcClient != nil {
+		if c.rpcClient != nil || c.endpoint != "" {
+			panic("solana client is already set")
+		}
+		c.endpoint = endpoint
+	}
+}
+
+// SetSolanaEndpoints sets a pool of the solana endpoints.
+// Reads go to the healthiest endpoint and fail over on 429 and 5xx, transactions are sent to several endpoints.
+// The websocket endpoint set empty by SetWebsocketEndpoint is derived from the first endpoint.
+func SetSolanaEndpoints(endpoints []string, opts ...PoolOption) ClientOption {
+	return func(c *Client) {
+		if c.rpcClient != nil || c.endpoint != "" {
 			panic("solana client is already set")
 		}
-		c.rpcClient = client.NewClient(endpoint)
+		pool, err := NewRPCPool(endpoints, opts...)
+		if err != nil {
+			panic(err)
+		}
+		c.pool = pool
+		c.endpoint = endpoints[0]
+	}
+}
+
+// SetRateLimit sets the client-wide budget of the RPC requests.
+// The throttled requests are retried with the exponential backoff.
+// It requires the solana endpoint, a custom solana client can not be rate limited.
+func SetRateLimit(limit RateLimit, opts ...RateLimiterOption) ClientOption {
+	return func(c *Client) {
+		if c.rateLimiter != nil {
+			panic("rate limit is already set")
+		}
+		c.rateLimiter = NewRateLimiter(limit, opts...)
+	}
+}
+
+// SetRPCInterceptors sets the interceptors of the RPC calls, the first interceptor is the outermost.
+// Use MetricsInterceptor and TracingInterceptor for the metrics and the tracing spans of the calls.
+// It requires the solana endpoint, the calls of a custom solana client can not be intercepted.
+func SetRPCInterceptors(interceptors ...RPCInterceptor) ClientOption {
+	return func(c *Client) {
+		if c.interceptors != nil {
+			panic("rpc interceptors are already set")
+		}
+		c.interceptors = interceptors
+	}
+}
+
+// SetRPCTransport sets the http transport of the RPC requests; default is http.DefaultTransport.
+// The rate limiter and the interceptors wrap the transport. Use WithPoolTransport for the pool of the endpoints.
+// It requires the solana endpoint, a custom solana client has its own transport.
+func SetRPCTransport(transport http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		if c.transport != nil {
+			panic("rpc transport is already set")
+		}
+		c.transport = transport
+	}
+}
+
+// SetCommitment sets the default commitment of the reads and status queries; default is finalized.
+// It can be overridden per call with the WithCommitment context.
+func SetCommitment(commitment rpc.Commitment) ClientOption {
+	return func(c *Client) {
+		if c.commitment != "" {
+			panic("commitment is already set")
+		}
+		c.commitment = commitment
+	}
+}
+
+// SetCache sets the read-through cache of the mints, token metadata, master editions and token list.
+// The values expire after the ttl, which must be positive; the writable accounts of the transactions sent by the client are invalidated.
+// Use NewLRUCache for the in-memory cache.
+func SetCache(cache Cache, ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		if c.cache != nil {
+			panic("cache is already set")
+		}
+		if ttl <= 0 {
+			panic("cache ttl must be positive")
+		}
+		c.cache = cache
+		c.cacheTTL = ttl
+	}
+}
+
+// SetWebsocketEndpoint enables the PubSub websocket client with the solana PubSub websocket endpoint.
+// The empty endpoint is derived from the solana endpoint.
+// Without the websocket endpoint, all the requests go over the http transport and the transactions are confirmed by polling.
+func SetWebsocketEndpoint(endpoint string, opts ...PubSubOption) ClientOption {
+	return func(c *Client) {
+		if c.pubsubEnabled {
+			panic("websocket endpoint is already set")
+		}
+		c.pubsubEnabled = true
+		c.wsEndpoint = endpoint
+		c.pubsubOpts = opts
 	}
 }
 
@@ -76,6 +199,22 @@ func New(opts ...ClientOption) *Client {
 		opt(c)
 	}
 
+	if c.pool != nil && c.transport != nil {
+		c.pool.Close()
+		panic("rpc transport of the endpoints pool is set by WithPoolTransport")
+	}
+
+	if c.rpcClient == nil && c.endpoint != "" {
+		c.rpcHTTP = &http.Client{Transport: c.rpcTransport()}
+		c.rpcClient = client.New(rpc.WithEndpoint(c.endpoint), rpc.WithHTTPClient(c.rpcHTTP))
+	} else if c.rateLimiter != nil {
+		panic("rate limit requires the solana endpoint")
+	} else if c.interceptors != nil {
+		panic("rpc interceptors require the solana endpoint")
+	} else if c.transport != nil {
+		panic("rpc transport requires the solana endpoint")
+	}
+
 	if c.rpcClient == nil {
 		panic("missing solana client")
 	}
@@ -88,6 +227,13 @@ func New(opts ...ClientOption) *Client {
 		c.tokenListPath = types.DeprecatedTokenListPath
 	}
 
+	if c.pubsubEnabled && c.wsEndpoint == "" {
+		if c.endpoint == "" {
+			panic("websocket endpoint can not be derived without the solana endpoint")
+		}
+		c.wsEndpoint = websocketEndpoint(c.endpoint)
+	}
+
 	return c
 }
 
@@ -100,3 +246,89 @@ func (c *Client) Solana() *client.Client {
 func (c *Client) DefaultDecimals() uint8 {
 	return c.defaultDecimals
 }
+
+// RPCPool returns the pool of the solana endpoints or nil if the client uses a single endpoint.
+func (c *Client) RPCPool() *RPCPool {
+	return c.pool
+}
+
+// PubSub returns the PubSub websocket client set by SetWebsocketEndpoint, it connects on the first call.
+// After a failed dial the calls return its error for 30 seconds without dialing again.
+// Returns the PubSub client or an error.
+func (c *Client) PubSub(ctx context.Context) (*PubSub, error) {
+	c.pubsubMu.Lock()
+	defer c.pubsubMu.Unlock()
+
+	if c.pubsub != nil {
+		return c.pubsub, nil
+	}
+	if c.wsEndpoint == "" {
+		return nil, ErrMissingWebsocketEndpoint
+	}
+	if c.pubsubErr != nil && time.Now().Before(c.pubsubRetryAt) {
+		return nil, c.pubsubErr
+	}
+
+	pubsub, err := NewPubSub(ctx, c.wsEndpoint, c.pubsubOpts...)
+	if err != nil {
+		// the dial canceled by the caller is not the failure of the endpoint
+		if ctx.Err() == nil {
+			c.pubsubErr = err
+			c.pubsubRetryAt = time.Now().Add(pubsubDialBackoff)
+		}
+		return nil, err
+	}
+	c.pubsub = pubsub
+	c.pubsubErr = nil
+
+	return pubsub, nil
+}
+
+// Close closes the PubSub websocket client, if it is connected, and stops the RPC pool health checks.
+func (c *Client) Close() error {
+	if c.pool != nil {
+		c.pool.Close()
+	}
+
+	c.pubsubMu.Lock()
+	defer c.pubsubMu.Unlock()
+
+	if c.pubsub == nil {
+		return nil
+	}
+	err := c.pubsub.Close()
+	c.pubsub = nil
+
+	return err
+}
+
+// rpcTransport returns the http transport of the RPC requests.
+func (c *Client) rpcTransport() http.RoundTripper {
+	var transport http.RoundTripper = http.DefaultTransport
+	if c.pool != nil {
+		transport = c.pool
+	} else if c.transport != nil {
+		transport = c.transport
+	}
+	if c.rateLimiter != nil {
+		transport = c.rateLimiter.transport(transport)
+	}
+	if len(c.interceptors) > 0 {
+		transport = &interceptorTransport{interceptors: c.interceptors, next: transport}
+	}
+
+	return transport
+}
+
+// websocketEndpoint derives the PubSub websocket endpoint from the http RPC endpoint.
+// The websocket port of the local validator is the RPC port + 1.
+func websocketEndpoint(endpoint string) string {
+	switch {
+	case strings.HasPrefix(endpoint, "https://"):
+		endpoint = "wss://" + strings.TrimPrefix(endpoint, "https://")
+	case strings.HasPrefix(endpoint, "http://"):
+		endpoint = "ws://" + strings.TrimPrefix(endpoint, "http://")
+	}
+
+	return strings.Replace(endpoint, ":8899", ":8900", 1)
+}
@@ -0,0 +1,206 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/program/system"
+	"github.com/EntySquare/solana-go-sdk/program/token"
+	sdktypes "github.com/EntySquare/solana-go-sdk/types"
+	"github.com/EntySquare/solana/client"
+	commonx "github.com/EntySquare/solana/common"
+	"github.com/EntySquare/solana/tests/mock"
+	"github.com/EntySquare/solana/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Balances(t *testing.T) {
+	fake := mock.NewRPCServer()
+	defer fake.Close()
+
+	wallet := sdktypes.NewAccount()
+	mint := sdktypes.NewAccount().PublicKey
+	ata, err := commonx.DeriveTokenAccount(wallet.PublicKey.ToBase58(), mint.ToBase58())
+	require.NoError(t, err)
+
+	fake.SetBalance(wallet.PublicKey.ToBase58(), 2*types.SOL)
+	fake.SetMint(mint.ToBase58(), token.MintAccount{Supply: 1_000_000, Decimals: 6, IsInitialized: true})
+	fake.SetTokenAccount(ata.ToBase58(), token.TokenAccount{
+		Mint:   mint,
+		Owner:  wallet.PublicKey,
+		Amount: 1_500_000,
+		State:  token.TokenAccountStateInitialized,
+	})
+
+	c := client.New(client.SetSolanaEndpoint(fake.URL))
+
+	balance, err := c.GetSOLBalance(context.Background(), wallet.PublicKey.ToBase58())
+	require.NoError(t, err)
+	require.Equal(t, 2*types.SOL, balance)
+
+	tokenBalance, err := c.GetTokenBalance(context.Background(), wallet.PublicKey.ToBase58(), mint.ToBase58())
+	require.NoError(t, err)
+	require.Equal(t, types.NewTokenAmountFromLamports(1_500_000, 6), tokenBalance)
+
+	tokens, err := c.GetFungibleTokensList(context.Background(), wallet.PublicKey.ToBase58())
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	require.Equal(t, mint, tokens[0].Mint)
+	require.EqualValues(t, 1_500_000, tokens[0].Balance.Amount)
+
+	mintInfo, err := c.GetMintInfo(context.Background(), mint.ToBase58())
+	require.NoError(t, err)
+	require.EqualValues(t, 1_000_000, mintInfo.Supply)
+
+	require.Len(t, fake.RequestsOf("getBalance"), 1)
+	// the token accounts of the SPL Token and the Token-2022 programs
+	require.Len(t, fake.RequestsOf("getTokenAccountsByOwner"), 2)
+}
+
+func TestClient_Token2022(t *testing.T) {
+	ctx := context.Background()
+	fake := mock.NewRPCServer()
+	defer fake.Close()
+
+	wallet := sdktypes.NewAccount()
+	mint := sdktypes.NewAccount().PublicKey
+	splMint := sdktypes.NewAccount().PublicKey
+	ata, err := commonx.DeriveTokenAccountPubkeyWithProgram(wallet.PublicKey, mint, commonx.Token2022ProgramID)
+	require.NoError(t, err)
+	splAta, err := commonx.DeriveTokenAccountPubkey(wallet.PublicKey, splMint)
+	require.NoError(t, err)
+
+	// the extensions follow the SPL Token layout: the account type and a TLV entry
+	extensions := append(make([]byte, token.TokenAccountSize-token.MintAccountSize+1), 1, 0, 2, 0, 7, 7)
+	fake.SetAccount(mint.ToBase58(), mock.RPCAccount{
+		Owner: commonx.Token2022ProgramID,
+		Data:  append(mock.MintAccountData(token.MintAccount{Supply: 5_000, Decimals: 2, IsInitialized: true}), extensions...),
+	})
+	fake.SetAccount(ata.ToBase58(), mock.RPCAccount{
+		Owner: commonx.Token2022ProgramID,
+		Data: mock.TokenAccountData(token.TokenAccount{
+			Mint: mint, Owner: wallet.PublicKey, Amount: 1_250, State: token.TokenAccountStateInitialized,
+		}),
+	})
+	fake.SetMint(splMint.ToBase58(), token.MintAccount{Supply: 1, IsInitialized: true})
+	fake.SetTokenAccount(splAta.ToBase58(), token.TokenAccount{
+		Mint: splMint, Owner: wallet.PublicKey, Amount: 1, State: token.TokenAccountStateInitialized,
+	})
+
+	c := client.New(client.SetSolanaEndpoint(fake.URL))
+
+	programID, err := c.GetTokenProgramID(ctx, mint.ToBase58())
+	require.NoError(t, err)
+	require.Equal(t, commonx.Token2022ProgramID, programID)
+	programID, err = c.GetTokenProgramID(ctx, splAta.ToBase58())
+	require.NoError(t, err)
+	require.Equal(t, common.TokenProgramID, programID)
+	_, err = c.GetTokenProgramID(ctx, wallet.PublicKey.ToBase58())
+	require.ErrorIs(t, err, client.ErrUnknownTokenProgram)
+
+	mintInfo, err := c.GetMintInfo(ctx, mint.ToBase58())
+	require.NoError(t, err)
+	require.EqualValues(t, 5_000, mintInfo.Supply)
+	require.EqualValues(t, 2, mintInfo.Decimals)
+
+	account, err := c.GetTokenAccountInfo(ctx, ata.ToBase58())
+	require.NoError(t, err)
+	require.EqualValues(t, 1_250, account.Amount)
+
+	balance, err := c.GetTokenBalance(ctx, wallet.PublicKey.ToBase58(), mint.ToBase58())
+	require.NoError(t, err)
+	require.Equal(t, types.NewTokenAmountFromLamports(1_250, 2), balance)
+
+	fungible, err := c.GetFungibleTokensList(ctx, wallet.PublicKey.ToBase58())
+	require.NoError(t, err)
+	require.Len(t, fungible, 1)
+	require.Equal(t, mint, fungible[0].Mint)
+
+	nonFungible, err := c.GetNonFungibleTokensList(ctx, wallet.PublicKey.ToBase58())
+	require.NoError(t, err)
+	require.Len(t, nonFungible, 1)
+	require.Equal(t, splMint, nonFungible[0].Mint)
+}
+
+func TestClient_SendTransaction(t *testing.T) {
+	fake := mock.NewRPCServer()
+	defer fake.Close()
+
+	payer := sdktypes.NewAccount()
+	c := client.New(client.SetSolanaEndpoint(fake.URL))
+
+	txSource, err := c.NewTransaction(context.Background(), client.NewTransactionParams{
+		FeePayer: payer.PublicKey,
+		Instructions: []sdktypes.Instruction{system.Transfer(system.TransferParam{
+			From:   payer.PublicKey,
+			To:     common.PublicKeyFromString("11111111111111111111111111111112"),
+			Amount: 1000,
+		})},
+		Signers: []sdktypes.Account{payer},
+	})
+	require.NoError(t, err)
+
+	txhash, err := c.SendTransaction(context.Background(), txSource)
+	require.NoError(t, err)
+
+	sent := fake.SentTransactions()
+	require.Len(t, sent, 1)
+	require.Equal(t, mock.DefaultBlockhash, sent[0].Message.RecentBlockHash)
+
+	status, err := c.GetTransactionStatus(context.Background(), txhash)
+	require.NoError(t, err)
+	require.Equal(t, types.TransactionStatusSuccess, status)
+
+	fake.SetSignatureStatus(txhash, mock.SignatureStatus{ConfirmationStatus: "processed"})
+	status, err = c.GetTransactionStatus(context.Background(), txhash)
+	require.NoError(t, err)
+	require.Equal(t, types.TransactionStatusInProgress, status)
+}
+
+func TestClient_SendTransaction_Errors(t *testing.T) {
+	fake := mock.NewRPCServer()
+	defer fake.Close()
+
+	payer := sdktypes.NewAccount()
+	c := client.New(client.SetSolanaEndpoint(fake.URL))
+	txSource, err := c.NewTransaction(context.Background(), client.NewTransactionParams{
+		FeePayer: payer.PublicKey,
+		Instructions: []sdktypes.Instruction{system.Transfer(system.TransferParam{
+			From:   payer.PublicKey,
+			To:     common.PublicKeyFromString("11111111111111111111111111111112"),
+			Amount: 1000,
+		})},
+		Signers: []sdktypes.Account{payer},
+	})
+	require.NoError(t, err)
+
+	// the JSON-RPC error without data
+	fake.SetError("sendTransaction", -32005, "Node is behind")
+	_, err = c.SendTransaction(context.Background(), txSource)
+	require.ErrorIs(t, err, client.ErrSendTransaction)
+	require.ErrorContains(t, err, "Node is behind")
+	require.NotErrorIs(t, err, client.ErrTransactionFailed)
+
+	// the transport error
+	unreachable := client.New(client.SetSolanaEndpoint("http://127.0.0.1:1"))
+	_, err = unreachable.SendTransaction(context.Background(), txSource)
+	require.ErrorIs(t, err, client.ErrSendTransaction)
+	require.NotErrorIs(t, err, client.ErrTransactionFailed)
+}
+
+func TestClient_ScriptedError(t *testing.T) {
+	fake := mock.NewRPCServer()
+	defer fake.Close()
+
+	fake.SetError("getBlockHeight", -32005, "Node is behind")
+	c := client.New(client.SetSolanaEndpoint(fake.URL))
+
+	_, err := c.GetBlockHeight(context.Background())
+	require.ErrorContains(t, err, "Node is behind")
+
+	fake.SetResult("getBlockHeight", 123)
+	height, err := c.GetBlockHeight(context.Background())
+	require.NoError(t, err)
+	require.EqualValues(t, 123, height)
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+
+	"github.com/EntySquare/solana-go-sdk/client"
+	"github.com/EntySquare/solana-go-sdk/rpc"
+	"github.com/EntySquare/solana/types"
+)
+
+// commitmentKey is the context key of the commitment override.
+type commitmentKey struct{}
+
+// WithCommitment returns the context which overrides the client commitment for the calls made with it,
+// e.g. processed balances for the UI and finalized balances for the accounting.
+func WithCommitment(ctx context.Context, commitment rpc.Commitment) context.Context {
+	return context.WithValue(ctx, commitmentKey{}, commitment)
+}
+
+// CommitmentFromContext returns the commitment override of the context and true if it is set.
+func CommitmentFromContext(ctx context.Context) (rpc.Commitment, bool) {
+	commitment, ok := ctx.Value(commitmentKey{}).(rpc.Commitment)
+	return commitment, ok && commitment != ""
+}
+
+// Commitment returns the commitment of the calls made with the context:
+// the context override, the client default or finalized.
+func (c *Client) Commitment(ctx context.Context) rpc.Commitment {
+	if commitment, ok := CommitmentFromContext(ctx); ok {
+		return commitment
+	}
+	if c.commitment != "" {
+		return c.commitment
+	}
+
+	return rpc.CommitmentFinalized
+}
+
+// historyCommitment returns the commitment of the transaction history calls,
+// which do not support the processed commitment.
+func (c *Client) historyCommitment(ctx context.Context) rpc.Commitment {
+	if commitment := c.Commitment(ctx); commitment != rpc.CommitmentProcessed {
+		return commitment
+	}
+
+	return rpc.CommitmentConfirmed
+}
+
+// getAccountInfo returns the account info at the commitment of the context.
+func (c *Client) getAccountInfo(ctx context.Context, base58Addr string) (client.AccountInfo, error) {
+	return c.rpcClient.GetAccountInfoWithConfig(ctx, base58Addr, client.GetAccountInfoConfig{
+		Commitment: c.Commitment(ctx),
+	})
+}
+
+// transactionStatus returns success if the confirmation status reached the commitment and in progress otherwise.
+func transactionStatus(confirmationStatus, commitment rpc.Commitment) types.TransactionStatus {
+	if commitmentReached(confirmationStatus, commitment) {
+		return types.TransactionStatusSuccess
+	}
+
+	return types.TransactionStatusInProgress
+}
@@ -0,0 +1,77 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/EntySquare/solana-go-sdk/rpc"
+	"github.com/EntySquare/solana/client"
+	"github.com/EntySquare/solana/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Commitment(t *testing.T) {
+	var commitments []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64            `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var result any
+		switch req.Method {
+		case "getBalance":
+			var cfg struct {
+				Commitment string `json:"commitment"`
+			}
+			require.NoError(t, json.Unmarshal(req.Params[1], &cfg))
+			commitments = append(commitments, cfg.Commitment)
+			result = map[string]any{"context": map[string]any{"slot": 1}, "value": 100}
+		case "getSignatureStatuses":
+			status := map[string]any{"slot": 1, "confirmations": 1, "err": nil, "confirmationStatus": "confirmed"}
+			result = map[string]any{"context": map[string]any{"slot": 1}, "value": []any{status}}
+		default:
+			t.Errorf("unexpected method %s", req.Method)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	defer server.Close()
+
+	wallet := "FuQhSmAT6kAmmzCMiiYbzFcTQJFuu6raXAdCFibz4YPR"
+	ctx := context.Background()
+
+	c := client.New(client.SetSolanaEndpoint(server.URL))
+	require.Equal(t, rpc.CommitmentFinalized, c.Commitment(ctx))
+	_, err := c.GetSOLBalance(ctx, wallet)
+	require.NoError(t, err)
+
+	// the confirmed transaction is not finalized yet
+	status, err := c.GetTransactionStatus(ctx, testTxSignature)
+	require.NoError(t, err)
+	require.Equal(t, types.TransactionStatusInProgress, status)
+
+	c = client.New(client.SetSolanaEndpoint(server.URL), client.SetCommitment(rpc.CommitmentConfirmed))
+	_, err = c.GetSOLBalance(ctx, wallet)
+	require.NoError(t, err)
+	status, err = c.GetTransactionStatus(ctx, testTxSignature)
+	require.NoError(t, err)
+	require.Equal(t, types.TransactionStatusSuccess, status)
+
+	// the context overrides the client commitment
+	processed := client.WithCommitment(ctx, rpc.CommitmentProcessed)
+	_, err = c.GetSOLBalance(processed, wallet)
+	require.NoError(t, err)
+	status, err = c.GetTransactionStatus(client.WithCommitment(ctx, rpc.CommitmentFinalized), testTxSignature)
+	require.NoError(t, err)
+	require.Equal(t, types.TransactionStatusInProgress, status)
+
+	require.Equal(t, []string{"finalized", "confirmed", "processed"}, commitments)
+}
@@ -0,0 +1,200 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/EntySquare/solana-go-sdk/client"
+	"github.com/EntySquare/solana-go-sdk/rpc"
+	"github.com/EntySquare/solana/types"
+	"github.com/EntySquare/solana/utils"
+)
+
+type (
+	// WaitOption is the option for WaitForTransactionConfirmed.
+	WaitOption func(*waitConfig)
+
+	waitConfig struct {
+		commitment           rpc.Commitment
+		lastValidBlockHeight uint64
+		blockhash            string
+		pollInterval         time.Duration
+	}
+)
+
+// WithWaitCommitment sets the commitment to wait for; default is the client commitment.
+func WithWaitCommitment(commitment rpc.Commitment) WaitOption {
+	return func(c *waitConfig) {
+		c.commitment = commitment
+	}
+}
+
+// WithLastValidBlockHeight sets the last block height at which the transaction blockhash is valid.
+// The wait stops with ErrBlockhashExpired once the block height passes it.
+// By default it is derived from the blockhash of the transaction sent by the client, if known.
+func WithLastValidBlockHeight(height uint64) WaitOption {
+	return func(c *waitConfig) {
+		c.lastValidBlockHeight = height
+	}
+}
+
+// WithRecentBlockhash sets the transaction blockhash.
+// The wait stops with ErrBlockhashExpired once the blockhash is no longer valid.
+// Ignored if the last valid block height is set; default is the blockhash of the transaction sent by the client.
+func WithRecentBlockhash(blockhash string) WaitOption {
+	return func(c *waitConfig) {
+		c.blockhash = blockhash
+	}
+}
+
+// WithPollInterval sets the interval of the status polling and the blockhash expiry checks; default is 5 seconds.
+func WithPollInterval(interval time.Duration) WaitOption {
+	return func(c *waitConfig) {
+		c.pollInterval = interval
+	}
+}
+
+// WaitForTransactionConfirmed waits for a transaction to be confirmed.
+// It waits on the signature subscription if the PubSub websocket is set by SetWebsocketEndpoint and available, and polls the status otherwise.
+// The failed status polls are retried on the next interval.
+// Returns the transaction status or an error.
+// The status is success once the transaction reached the commitment.
+// The cached values of the writable accounts of the transaction sent by the client are invalidated once it landed.
+func (c *Client) WaitForTransactionConfirmed(ctx context.Context, txhash string, maxDuration time.Duration, opts ...WaitOption) (types.TransactionStatus, error) {
+	cfg := waitConfig{
+		commitment:   c.Commitment(ctx),
+		pollInterval: 5 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if sent, ok := c.sentTransaction(txhash); ok && cfg.lastValidBlockHeight == 0 && cfg.blockhash == "" {
+		if height, ok := c.LastValidBlockHeight(sent.blockhash); ok {
+			cfg.lastValidBlockHeight = height
+		} else {
+			cfg.blockhash = sent.blockhash
+		}
+	}
+
+	if maxDuration == 0 {
+		maxDuration = 5 * time.Minute
+	}
+	ctx, cancel := context.WithTimeout(ctx, maxDuration)
+	defer cancel()
+
+	status, err := c.waitForSignature(ctx, txhash, cfg)
+	if status == types.TransactionStatusSuccess || status == types.TransactionStatusFailure {
+		c.confirmSentTransaction(txhash)
+	}
+	if err != nil {
+		return status, utils.StackErrors(ErrWaitForTransaction, err)
+	}
+
+	return status, nil
+}
+
+// waitForSignature waits for the signature notification or polls the signature status.
+func (c *Client) waitForSignature(ctx context.Context, txhash string, cfg waitConfig) (types.TransactionStatus, error) {
+	var notifications <-chan SignatureNotification
+	if c.wsEndpoint != "" {
+		if pubsub, err := c.PubSub(ctx); err == nil {
+			if sub, err := pubsub.SignatureSubscribe(ctx, txhash, cfg.commitment); err == nil {
+				defer sub.Unsubscribe()
+				notifications = sub.Notifications()
+			}
+		}
+	}
+
+	// the transaction may have landed before the subscription
+	status, done, lastErr := c.checkSignature(ctx, txhash, cfg)
+	if done {
+		return status, lastErr
+	}
+
+	tick := time.NewTicker(cfg.pollInterval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return types.TransactionStatusUnknown, utils.StackErrors(ErrContextDone, lastErr)
+			}
+			return types.TransactionStatusUnknown, ErrContextDone
+		case n, ok := <-notifications:
+			if !ok {
+				// the subscription is closed, fall back to polling
+				notifications = nil
+				continue
+			}
+			if n.Err != nil {
+				return types.TransactionStatusFailure, c.decodeStatusError(ctx, txhash, n.Err.Raw)
+			}
+			return types.TransactionStatusSuccess, nil
+		case <-tick.C:
+			if notifications == nil {
+				if status, done, lastErr = c.checkSignature(ctx, txhash, cfg); done {
+					return status, lastErr
+				}
+			}
+			if c.blockhashExpired(ctx, cfg) {
+				// the transaction could still land in the last valid block
+				if status, done, lastErr = c.checkSignature(ctx, txhash, cfg); done {
+					return status, lastErr
+				}
+				if lastErr == nil {
+					return types.TransactionStatusUnknown, ErrBlockhashExpired
+				}
+			}
+		}
+	}
+}
+
+// checkSignature checks if the transaction reached the commitment or failed.
+// Returns the transaction status and true if the wait is done;
+// the error of the status request does not end the wait, the status is checked again later.
+func (c *Client) checkSignature(ctx context.Context, txhash string, cfg waitConfig) (types.TransactionStatus, bool, error) {
+	status, err := c.rpcClient.GetSignatureStatus(ctx, txhash)
+	if err != nil {
+		return types.TransactionStatusUnknown, false, utils.StackErrors(ErrGetTransactionStatus, err)
+	}
+	if status == nil {
+		return types.TransactionStatusUnknown, false, nil
+	}
+	if status.Err != nil {
+		return types.TransactionStatusFailure, true, c.decodeStatusError(ctx, txhash, status.Err)
+	}
+	if status.ConfirmationStatus == nil || !commitmentReached(*status.ConfirmationStatus, cfg.commitment) {
+		return types.TransactionStatusInProgress, false, nil
+	}
+
+	return types.TransactionStatusSuccess, true, nil
+}
+
+// blockhashExpired returns true if the transaction blockhash is known to be expired.
+// The errors are ignored, the check is repeated on the next tick.
+func (c *Client) blockhashExpired(ctx context.Context, cfg waitConfig) bool {
+	switch {
+	case cfg.lastValidBlockHeight > 0:
+		height, err := c.GetBlockHeight(ctx)
+		return err == nil && height > cfg.lastValidBlockHeight
+	case cfg.blockhash != "":
+		valid, err := c.rpcClient.IsBlockhashValidWithConfig(ctx, cfg.blockhash, client.IsBlockhashValidConfig{
+			Commitment: rpc.CommitmentProcessed,
+		})
+		return err == nil && !valid
+	default:
+		return false
+	}
+}
+
+// commitmentReached returns true if the status commitment is at least the target commitment.
+func commitmentReached(status, target rpc.Commitment) bool {
+	levels := map[rpc.Commitment]int{
+		rpc.CommitmentProcessed: 1,
+		rpc.CommitmentConfirmed: 2,
+		rpc.CommitmentFinalized: 3,
+	}
+
+	return levels[status] >= levels[target]
+}
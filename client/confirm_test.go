@@ -0,0 +1,188 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/EntySquare/solana-go-sdk/rpc"
+	"github.com/EntySquare/solana/client"
+	"github.com/EntySquare/solana/tests/mock"
+	"github.com/EntySquare/solana/types"
+	"github.com/stretchr/testify/require"
+)
+
+const testTxSignature = "5j7s6NiJS3JAkvgkoc18WVAsiSaci2pxB2A6ueCJP4tprA2TFg9wSyTLeYouxPBJEMzJinENTkpA52YStRW5Dia7"
+
+// newStatusServer starts a json rpc server which does not know any signature and reports the given block height.
+func newStatusServer(t *testing.T, blockHeight uint64) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64 `json:"id"`
+			Method string `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			// e.g. the websocket upgrade request of the derived websocket endpoint
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var result any
+		switch req.Method {
+		case "getSignatureStatuses":
+			result = map[string]any{"context": map[string]any{"slot": 1}, "value": []any{nil}}
+		case "getBlockHeight":
+			result = blockHeight
+		default:
+			t.Errorf("unexpected method %s", req.Method)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestWaitForTransactionConfirmed_Subscription(t *testing.T) {
+	rpcServer := newStatusServer(t, 1)
+	wsServer := mock.NewPubSubServer()
+	defer wsServer.Close()
+
+	c := client.New(client.SetSolanaEndpoint(rpcServer.URL), client.SetWebsocketEndpoint(wsServer.URL()))
+	defer c.Close()
+
+	go func() {
+		sub, ok := wsServer.WaitForSubscription("signatureSubscribe", 5*time.Second)
+		if ok {
+			wsServer.Notify(sub.ID, map[string]any{
+				"context": map[string]any{"slot": 5},
+				"value":   map[string]any{"err": nil},
+			})
+		}
+	}()
+
+	status, err := c.WaitForTransactionConfirmed(context.Background(), testTxSignature, 10*time.Second,
+		client.WithWaitCommitment(rpc.CommitmentConfirmed),
+		client.WithPollInterval(time.Hour),
+	)
+	require.NoError(t, err)
+	require.Equal(t, types.TransactionStatusSuccess, status)
+}
+
+func TestWaitForTransactionConfirmed_BlockhashExpired(t *testing.T) {
+	rpcServer := newStatusServer(t, 200)
+
+	c := client.New(client.SetSolanaEndpoint(rpcServer.URL))
+
+	start := time.Now()
+	status, err := c.WaitForTransactionConfirmed(context.Background(), testTxSignature, 10*time.Second,
+		client.WithLastValidBlockHeight(100),
+		client.WithPollInterval(10*time.Millisecond),
+	)
+	require.ErrorIs(t, err, client.ErrBlockhashExpired)
+	require.Equal(t, types.TransactionStatusUnknown, status)
+	require.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestWaitForTransactionConfirmed_SentTransactionExpired(t *testing.T) {
+	ctx := context.Background()
+	fake := mock.NewRPCServer()
+	defer fake.Close()
+
+	// the sent transaction never lands
+	fake.Handle("getSignatureStatuses", func([]json.RawMessage) (any, error) {
+		return map[string]any{"context": map[string]any{"slot": 1}, "value": []any{nil}}, nil
+	})
+	fake.SetLatestBlockhash(mock.DefaultBlockhash, 100)
+
+	c := client.New(client.SetSolanaEndpoint(fake.URL))
+	txhash, err := c.SendTransaction(ctx, newTestTransaction(t, c))
+	require.NoError(t, err)
+
+	// the last valid block height is recorded for the blockhash of the transaction
+	fake.SetBlockHeight(200, 200)
+	status, err := c.WaitForTransactionConfirmed(ctx, txhash, 10*time.Second, client.WithPollInterval(10*time.Millisecond))
+	require.ErrorIs(t, err, client.ErrBlockhashExpired)
+	require.Equal(t, types.TransactionStatusUnknown, status)
+}
+
+func TestWaitForTransactionConfirmed_StatusErrors(t *testing.T) {
+	fake := mock.NewRPCServer()
+	defer fake.Close()
+
+	var calls int32
+	getSignatureStatuses := fake.Builtin("getSignatureStatuses")
+	fake.Handle("getSignatureStatuses", func(params []json.RawMessage) (any, error) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			return nil, &mock.RPCError{Code: -32603, Message: "Node is unhealthy"}
+		}
+		return getSignatureStatuses(params)
+	})
+	fake.SetSignatureStatus(testTxSignature, mock.SignatureStatus{ConfirmationStatus: "finalized"})
+
+	c := client.New(client.SetSolanaEndpoint(fake.URL))
+	status, err := c.WaitForTransactionConfirmed(context.Background(), testTxSignature, 10*time.Second,
+		client.WithPollInterval(10*time.Millisecond),
+	)
+	require.NoError(t, err)
+	require.Equal(t, types.TransactionStatusSuccess, status)
+	require.EqualValues(t, 3, atomic.LoadInt32(&calls))
+
+	// the wait times out with the last error
+	fake.SetError("getSignatureStatuses", -32603, "Node is unhealthy")
+	_, err = c.WaitForTransactionConfirmed(context.Background(), testTxSignature, 50*time.Millisecond,
+		client.WithPollInterval(10*time.Millisecond),
+	)
+	require.ErrorIs(t, err, client.ErrContextDone)
+	require.ErrorIs(t, err, client.ErrGetTransactionStatus)
+}
+
+func TestClient_PubSubDialBackoff(t *testing.T) {
+	var dials int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&dials, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c := client.New(
+		client.SetSolanaEndpoint(server.URL),
+		client.SetWebsocketEndpoint("ws"+strings.TrimPrefix(server.URL, "http")),
+	)
+	for i := 0; i < 3; i++ {
+		_, err := c.PubSub(context.Background())
+		require.Error(t, err)
+	}
+	require.EqualValues(t, 1, atomic.LoadInt32(&dials))
+}
+
+func TestClient_PubSubOptIn(t *testing.T) {
+	var dials int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&dials, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	// the websocket endpoint is not dialed unless it is set
+	c := client.New(client.SetSolanaEndpoint(server.URL))
+	_, err := c.PubSub(context.Background())
+	require.ErrorIs(t, err, client.ErrMissingWebsocketEndpoint)
+	require.Zero(t, atomic.LoadInt32(&dials))
+
+	// the empty endpoint is derived from the solana endpoint
+	c = client.New(client.SetSolanaEndpoint(server.URL), client.SetWebsocketEndpoint(""))
+	_, err = c.PubSub(context.Background())
+	require.Error(t, err)
+	require.NotErrorIs(t, err, client.ErrMissingWebsocketEndpoint)
+	require.EqualValues(t, 1, atomic.LoadInt32(&dials))
+
+	require.Panics(t, func() {
+		client.New(client.SetSolanaEndpoint(server.URL), client.SetWebsocketEndpoint(""), client.SetWebsocketEndpoint("ws://localhost"))
+	})
+}
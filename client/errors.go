@@ -53,4 +53,44 @@ var (
 	ErrNoTransactionsFound                 = errors.New("no transactions found")
 	ErrTransactionNotFound                 = errors.New("transaction not found")
 	ErrTransactionNotConfirmed             = errors.New("transaction not confirmed yet")
+	ErrInvalidLookupTable                  = errors.New("invalid address lookup table")
+	ErrTransactionTooLarge                 = errors.New("transaction exceeds the maximum transaction size")
+	ErrGetSlot                             = errors.New("failed to get slot")
+	ErrGetLookupTable                      = errors.New("failed to get address lookup table")
+	ErrLookupTableNotFound                 = errors.New("address lookup table not found")
+	ErrLookupTableDeactivated              = errors.New("address lookup table is deactivated")
+	ErrGetRecentPrioritizationFees         = errors.New("failed to get recent prioritization fees")
+	ErrEstimateComputeUnitPrice            = errors.New("failed to estimate compute unit price")
+	ErrSimulateTransaction                 = errors.New("failed to simulate transaction")
+	ErrSimulationFailed                    = errors.New("transaction simulation failed")
+	ErrConnectPubSub                       = errors.New("failed to connect to pubsub websocket")
+	ErrPubSubClosed                        = errors.New("pubsub client is closed")
+	ErrPubSubDisconnected                  = errors.New("pubsub client is disconnected")
+	ErrSubscribe                           = errors.New("failed to subscribe")
+	ErrResubscribe                         = errors.New("failed to resubscribe after reconnect")
+	ErrMissingWebsocketEndpoint            = errors.New("missing websocket endpoint")
+	ErrGetBlockHeight                      = errors.New("failed to get block height")
+	ErrBlockhashExpired                    = errors.New("transaction blockhash expired")
+	ErrSendAndConfirmTransaction           = errors.New("failed to send and confirm transaction")
+	ErrRebuildTransaction                  = errors.New("failed to rebuild transaction")
+	ErrGetMultipleAccounts                 = errors.New("failed to get multiple accounts")
+	ErrGetWalletHistory                    = errors.New("failed to get wallet history")
+	ErrInvalidHistoryLimit                 = errors.New("invalid wallet history limit")
+	ErrValidatePayment                     = errors.New("failed to validate payment")
+	ErrInvalidPaymentRequest               = errors.New("invalid payment request")
+	ErrPaymentFailed                       = errors.New("payment transaction failed")
+	ErrPaymentReferenceMissing             = errors.New("payment transaction does not include the reference")
+	ErrPaymentTransferNotFound             = errors.New("payment transaction has no transfer to the recipient")
+	ErrPaymentAmountMismatch               = errors.New("payment amount does not match the requested amount")
+	ErrPaymentMemoMismatch                 = errors.New("payment memo does not match the requested memo")
+	ErrPaymentDuplicate                    = errors.New("payment is a duplicate of an earlier payment")
+	ErrWatchPayment                        = errors.New("failed to watch payment")
+	ErrPaymentAlreadyWatched               = errors.New("payment reference is already watched or matched")
+	ErrGetTokenProgram                     = errors.New("failed to get token program")
+	ErrUnknownTokenProgram                 = errors.New("account is not owned by the SPL Token or the Token-2022 program")
 )
+
+// ErrTooManyPaymentCandidates is not returned anymore.
+//
+// Deprecated: ValidatePayment pages through the transactions of the payment reference instead.
+var ErrTooManyPaymentCandidates = errors.New("too many transactions for the payment reference")
@@ -0,0 +1,296 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/EntySquare/solana-go-sdk/client"
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/rpc"
+	"github.com/EntySquare/solana/parser"
+	"github.com/EntySquare/solana/types"
+	"github.com/EntySquare/solana/utils"
+)
+
+// historyFetchConcurrency is the number of the transactions of a history page fetched at once.
+const historyFetchConcurrency = 8
+
+// tokenChange is the token balance of an owner before and after the transaction.
+type tokenChange struct {
+	owner     string
+	pre, post uint64
+}
+
+// GetWalletHistory returns a page of the wallet transactions from the newest one, classified from the wallet point of view.
+// The cursor pages back with Before and fetches incrementally the new transactions with Until;
+// the limit is the page size, 0 means types.DefaultHistoryLimit, max is types.MaxHistoryLimit.
+// The failed transactions are included with the fee type.
+// Returns the page or an error.
+func (c *Client) GetWalletHistory(ctx context.Context, base58Addr string, cursor types.HistoryCursor, limit int) (types.WalletHistory, error) {
+	if limit == 0 {
+		limit = types.DefaultHistoryLimit
+	}
+	if limit < 0 || limit > types.MaxHistoryLimit {
+		return types.WalletHistory{}, utils.StackErrors(
+			ErrGetWalletHistory,
+			ErrInvalidHistoryLimit,
+			fmt.Errorf("limit %d, max is %d", limit, types.MaxHistoryLimit),
+		)
+	}
+
+	signatures, err := c.rpcClient.GetSignaturesForAddressWithConfig(ctx, base58Addr, client.GetSignaturesForAddressConfig{
+		Limit:      limit,
+		Before:     cursor.Before,
+		Until:      cursor.Until,
+		Commitment: c.historyCommitment(ctx),
+	})
+	if err != nil {
+		return types.WalletHistory{}, utils.StackErrors(ErrGetWalletHistory, err)
+	}
+
+	txs, err := c.getTransactions(ctx, signatures)
+	if err != nil {
+		return types.WalletHistory{}, utils.StackErrors(ErrGetWalletHistory, err)
+	}
+
+	wallet := common.PublicKeyFromString(base58Addr)
+	history := types.WalletHistory{Transactions: make([]types.WalletTransaction, len(txs))}
+	for i, tx := range txs {
+		history.Transactions[i] = ClassifyWalletTransaction(wallet, signatures[i].Signature, tx)
+	}
+	if len(signatures) == limit {
+		history.HasMore = true
+		history.Next = types.HistoryCursor{Before: signatures[len(signatures)-1].Signature, Until: cursor.Until}
+	}
+
+	return history, nil
+}
+
+// getTransactions fetches the transactions of the signatures in the same order, including the failed ones.
+// Returns an error if any transaction can not be fetched.
+func (c *Client) getTransactions(ctx context.Context, signatures rpc.GetSignaturesForAddress) ([]*client.Transaction, error) {
+	txs, errs := c.fetchTransactions(ctx, signatures)
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return txs, nil
+}
+
+// fetchTransactions fetches the transactions of the signatures in the same order, including the failed ones.
+// Returns the transactions and the errors by the signature; the transaction is nil if its error is set.
+func (c *Client) fetchTransactions(ctx context.Context, signatures rpc.GetSignaturesForAddress) ([]*client.Transaction, []error) {
+	txs := make([]*client.Transaction, len(signatures))
+	errs := make([]error, len(signatures))
+	limiter := make(chan struct{}, historyFetchConcurrency)
+
+	var wg sync.WaitGroup
+	for i, signature := range signatures {
+		wg.Add(1)
+		go func(i int, signature string) {
+			defer wg.Done()
+			limiter <- struct{}{}
+			defer func() { <-limiter }()
+
+			tx, err := c.rpcClient.GetTransactionWithConfig(ctx, signature, client.GetTransactionConfig{
+				Commitment: c.historyCommitment(ctx),
+			})
+			switch {
+			case err != nil:
+				errs[i] = fmt.Errorf("failed to get transaction %s: %w", signature, err)
+			case tx == nil || tx.Meta == nil:
+				errs[i] = utils.StackErrors(ErrTransactionNotFound, fmt.Errorf("signature %s", signature))
+			default:
+				txs[i] = tx
+			}
+		}(i, signature.Signature)
+	}
+	wg.Wait()
+
+	return txs, errs
+}
+
+// ClassifyWalletTransaction classifies the fetched transaction from the point of view of the wallet.
+// The token balance changes of the wallet take precedence over the SOL ones, since the token
+// transfers often pay the rent of the created accounts. A token with 0 decimals moved by a single unit
+// is considered an NFT. The counterparty is the owner with the opposite change of the same token,
+// or the other side of the System transfer.
+func ClassifyWalletTransaction(wallet common.PublicKey, signature string, tx *client.Transaction) types.WalletTransaction {
+	result := types.WalletTransaction{
+		Signature: signature,
+		Slot:      tx.Slot,
+		BlockTime: tx.BlockTime,
+		Type:      types.HistoryTypeOther,
+		Failed:    tx.Meta.Err != nil,
+	}
+	if len(tx.AccountKeys) > 0 && tx.AccountKeys[0] == wallet {
+		result.Fee = tx.Meta.Fee
+	}
+
+	// the classification falls back to the balances if the instructions can not be parsed
+	instructions, _ := parser.ParseTransaction(tx)
+	instructions = parser.Flatten(instructions)
+	for _, ix := range instructions {
+		if memo, ok := ix.Parsed.(parser.Memo); ok {
+			result.Memo = memo.Text
+			break
+		}
+	}
+
+	if result.Failed {
+		if result.Fee > 0 {
+			result.Type = types.HistoryTypeFee
+		}
+		return result
+	}
+
+	if classifyTokenChange(&result, wallet, tx, instructions) {
+		return result
+	}
+
+	index := -1
+	for i, key := range tx.AccountKeys {
+		if key == wallet {
+			index = i
+			break
+		}
+	}
+	var delta int64
+	if index >= 0 && index < len(tx.Meta.PreBalances) && index < len(tx.Meta.PostBalances) {
+		delta = tx.Meta.PostBalances[index] - tx.Meta.PreBalances[index] + int64(result.Fee)
+	}
+
+	switch {
+	case delta > 0:
+		result.Type = types.HistoryTypeSOLIn
+		result.Amount = types.NewTokenAmountFromLamports(uint64(delta), types.SPLTokenDefaultDecimals)
+		for _, ix := range instructions {
+			if transfer, ok := ix.Parsed.(parser.SystemTransfer); ok && transfer.To == wallet && transfer.From != wallet {
+				result.Counterparty = &transfer.From
+				break
+			}
+		}
+	case delta < 0:
+		result.Type = types.HistoryTypeSOLOut
+		result.Amount = types.NewTokenAmountFromLamports(uint64(-delta), types.SPLTokenDefaultDecimals)
+		for _, ix := range instructions {
+			if transfer, ok := ix.Parsed.(parser.SystemTransfer); ok && transfer.From == wallet && transfer.To != wallet {
+				result.Counterparty = &transfer.To
+				break
+			}
+		}
+	case result.Fee > 0:
+		result.Type = types.HistoryTypeFee
+	}
+
+	return result
+}
+
+// classifyTokenChange classifies the first token balance change of the wallet.
+// Returns false if the token balances of the wallet did not change.
+func classifyTokenChange(result *types.WalletTransaction, wallet common.PublicKey, tx *client.Transaction, instructions []parser.Instruction) bool {
+	var mints []string
+	decimals := make(map[string]uint8)
+	changes := make(map[string]map[string]*tokenChange) // mint -> owner -> change
+	collect := func(balances []rpc.TransactionMetaTokenBalance, post bool) {
+		for _, balance := range balances {
+			if _, ok := changes[balance.Mint]; !ok {
+				mints = append(mints, balance.Mint)
+				changes[balance.Mint] = make(map[string]*tokenChange)
+			}
+			change, ok := changes[balance.Mint][balance.Owner]
+			if !ok {
+				change = &tokenChange{owner: balance.Owner}
+				changes[balance.Mint][balance.Owner] = change
+			}
+			amount, _ := strconv.ParseUint(balance.UITokenAmount.Amount, 10, 64)
+			if post {
+				change.post += amount
+			} else {
+				change.pre += amount
+			}
+			decimals[balance.Mint] = balance.UITokenAmount.Decimals
+		}
+	}
+	collect(tx.Meta.PreTokenBalances, false)
+	collect(tx.Meta.PostTokenBalances, true)
+
+	for _, mint := range mints {
+		own, ok := changes[mint][wallet.ToBase58()]
+		if !ok || own.pre == own.post {
+			continue
+		}
+
+		incoming := own.post > own.pre
+		amount := own.post - own.pre
+		if !incoming {
+			amount = own.pre - own.post
+		}
+		mintKey := common.PublicKeyFromString(mint)
+		result.Mint = &mintKey
+		result.Amount = types.NewTokenAmountFromLamports(amount, decimals[mint])
+
+		// the counterparty has the largest opposite change
+		var counterparty *tokenChange
+		var counterpartyAmount uint64
+		for _, change := range changes[mint] {
+			if change == own || change.owner == "" {
+				continue
+			}
+			if incoming && change.pre > change.post && change.pre-change.post > counterpartyAmount {
+				counterparty, counterpartyAmount = change, change.pre-change.post
+			}
+			if !incoming && change.post > change.pre && change.post-change.pre > counterpartyAmount {
+				counterparty, counterpartyAmount = change, change.post-change.pre
+			}
+		}
+		if counterparty != nil {
+			key := common.PublicKeyFromString(counterparty.owner)
+			result.Counterparty = &key
+		}
+
+		isNFT := decimals[mint] == 0 && amount == 1
+		switch {
+		case isNFT && incoming && mintedTo(instructions, mintKey):
+			result.Type = types.HistoryTypeNFTMint
+		case isNFT && !incoming && burned(instructions, mintKey):
+			result.Type = types.HistoryTypeNFTBurn
+		case isNFT && incoming:
+			result.Type = types.HistoryTypeNFTTransferIn
+		case isNFT:
+			result.Type = types.HistoryTypeNFTTransferOut
+		case incoming:
+			result.Type = types.HistoryTypeTokenIn
+		default:
+			result.Type = types.HistoryTypeTokenOut
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// mintedTo reports whether the instructions mint the token.
+func mintedTo(instructions []parser.Instruction, mint common.PublicKey) bool {
+	for _, ix := range instructions {
+		if mintTo, ok := ix.Parsed.(parser.TokenMintTo); ok && mintTo.Mint == mint {
+			return true
+		}
+	}
+	return false
+}
+
+// burned reports whether the instructions burn the token.
+func burned(instructions []parser.Instruction, mint common.PublicKey) bool {
+	for _, ix := range instructions {
+		if burn, ok := ix.Parsed.(parser.TokenBurn); ok && burn.Mint == mint {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package client_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/program/memo"
+	"github.com/EntySquare/solana-go-sdk/program/system"
+	"github.com/EntySquare/solana-go-sdk/program/token"
+	"github.com/EntySquare/solana-go-sdk/rpc"
+	sdktypes "github.com/EntySquare/solana-go-sdk/types"
+	"github.com/EntySquare/solana/client"
+	"github.com/EntySquare/solana/tests/mock"
+	"github.com/EntySquare/solana/types"
+	"github.com/stretchr/testify/require"
+)
+
+// historyTransaction builds the stored transaction with the lamports of the accounts before and after it.
+func historyTransaction(payer common.PublicKey, pre, post map[common.PublicKey]int64, instructions ...sdktypes.Instruction) mock.RPCTransaction {
+	message := sdktypes.NewMessage(sdktypes.NewMessageParam{
+		FeePayer:        payer,
+		RecentBlockhash: mock.DefaultBlockhash,
+		Instructions:    instructions,
+	})
+	tx := mock.RPCTransaction{
+		Transaction:  sdktypes.Transaction{Message: message},
+		PreBalances:  make([]int64, len(message.Accounts)),
+		PostBalances: make([]int64, len(message.Accounts)),
+	}
+	for i, account := range message.Accounts {
+		tx.PreBalances[i] = pre[account]
+		tx.PostBalances[i] = post[account]
+	}
+	return tx
+}
+
+func tokenBalance(message sdktypes.Message, account, mint, owner common.PublicKey, amount uint64, decimals uint8) rpc.TransactionMetaTokenBalance {
+	index := 0
+	for i, key := range message.Accounts {
+		if key == account {
+			index = i
+		}
+	}
+	return rpc.TransactionMetaTokenBalance{
+		AccountIndex:  uint64(index),
+		Mint:          mint.ToBase58(),
+		Owner:         owner.ToBase58(),
+		UITokenAmount: rpc.TokenAccountBalance{Amount: strconv.FormatUint(amount, 10), Decimals: decimals},
+	}
+}
+
+func TestClient_GetWalletHistory(t *testing.T) {
+	ctx := context.Background()
+	fake := mock.NewRPCServer()
+	defer fake.Close()
+
+	wallet := sdktypes.NewAccount().PublicKey
+	other := sdktypes.NewAccount().PublicKey
+	mint := sdktypes.NewAccount().PublicKey
+	nft := sdktypes.NewAccount().PublicKey
+	walletAta, _, _ := common.FindAssociatedTokenAddress(wallet, mint)
+	otherAta, _, _ := common.FindAssociatedTokenAddress(other, mint)
+	nftAta, _, _ := common.FindAssociatedTokenAddress(wallet, nft)
+
+	// SOL in with a memo
+	solIn := fake.AddTransaction(historyTransaction(other,
+		map[common.PublicKey]int64{other: 10_000_000, wallet: 1_000_000},
+		map[common.PublicKey]int64{other: 7_995_000, wallet: 3_000_000},
+		system.Transfer(system.TransferParam{From: other, To: wallet, Amount: 2_000_000}),
+		memo.BuildMemo(memo.BuildMemoParam{Memo: []byte("rent")}),
+	))
+
+	// token out, the wallet pays the fee
+	tokenOut := historyTransaction(wallet,
+		map[common.PublicKey]int64{wallet: 3_000_000},
+		map[common.PublicKey]int64{wallet: 2_995_000},
+		token.TransferChecked(token.TransferCheckedParam{
+			From: walletAta, To: otherAta, Mint: mint, Auth: wallet, Amount: 250, Decimals: 2,
+		}),
+	)
+	message := tokenOut.Transaction.Message
+	tokenOut.PreTokenBalances = []rpc.TransactionMetaTokenBalance{
+		tokenBalance(message, walletAta, mint, wallet, 1000, 2),
+		tokenBalance(message, otherAta, mint, other, 0, 2),
+	}
+	tokenOut.PostTokenBalances = []rpc.TransactionMetaTokenBalance{
+		tokenBalance(message, walletAta, mint, wallet, 750, 2),
+		tokenBalance(message, otherAta, mint, other, 250, 2),
+	}
+	tokenOutSig := fake.AddTransaction(tokenOut)
+
+	// NFT mint
+	nftMint := historyTransaction(wallet, nil, nil, token.MintTo(token.MintToParam{
+		Mint: nft, To: nftAta, Auth: wallet, Amount: 1,
+	}))
+	nftMint.PostTokenBalances = []rpc.TransactionMetaTokenBalance{
+		tokenBalance(nftMint.Transaction.Message, nftAta, nft, wallet, 1, 0),
+	}
+	nftMintSig := fake.AddTransaction(nftMint)
+
+	// failed transaction
+	failed := historyTransaction(wallet, nil, nil, system.Transfer(system.TransferParam{From: wallet, To: other, Amount: 1}))
+	failed.Err = map[string]any{"InstructionError": []any{0, map[string]any{"Custom": 1}}}
+	failedSig := fake.AddTransaction(failed)
+
+	c := client.New(client.SetSolanaEndpoint(fake.URL))
+
+	page, err := c.GetWalletHistory(ctx, wallet.ToBase58(), types.HistoryCursor{}, 2)
+	require.NoError(t, err)
+	require.True(t, page.HasMore)
+	require.Equal(t, types.HistoryCursor{Before: nftMintSig}, page.Next)
+	require.Len(t, page.Transactions, 2)
+
+	require.Equal(t, failedSig, page.Transactions[0].Signature)
+	require.Equal(t, types.HistoryTypeFee, page.Transactions[0].Type)
+	require.True(t, page.Transactions[0].Failed)
+	require.EqualValues(t, mock.DefaultLamportsPerSignature, page.Transactions[0].Fee)
+
+	require.Equal(t, types.HistoryTypeNFTMint, page.Transactions[1].Type)
+	require.Equal(t, &nft, page.Transactions[1].Mint)
+	require.Nil(t, page.Transactions[1].Counterparty)
+
+	page, err = c.GetWalletHistory(ctx, wallet.ToBase58(), page.Next, 2)
+	require.NoError(t, err)
+	require.Len(t, page.Transactions, 2)
+
+	require.Equal(t, tokenOutSig, page.Transactions[0].Signature)
+	require.Equal(t, types.HistoryTypeTokenOut, page.Transactions[0].Type)
+	require.Equal(t, types.NewTokenAmountFromLamports(250, 2), page.Transactions[0].Amount)
+	require.Equal(t, &mint, page.Transactions[0].Mint)
+	require.Equal(t, &other, page.Transactions[0].Counterparty)
+
+	require.Equal(t, solIn, page.Transactions[1].Signature)
+	require.Equal(t, types.HistoryTypeSOLIn, page.Transactions[1].Type)
+	require.Equal(t, types.NewTokenAmountFromLamports(2_000_000, types.SPLTokenDefaultDecimals), page.Transactions[1].Amount)
+	require.Equal(t, &other, page.Transactions[1].Counterparty)
+	require.Nil(t, page.Transactions[1].Mint)
+	require.Zero(t, page.Transactions[1].Fee)
+	require.Equal(t, "rent", page.Transactions[1].Memo)
+
+	// the incremental fetch returns only the new transactions
+	solOut := fake.AddTransaction(historyTransaction(wallet,
+		map[common.PublicKey]int64{wallet: 2_995_000},
+		map[common.PublicKey]int64{wallet: 1_990_000},
+		system.Transfer(system.TransferParam{From: wallet, To: other, Amount: 1_000_000}),
+	))
+	page, err = c.GetWalletHistory(ctx, wallet.ToBase58(), types.HistoryCursor{Until: failedSig}, 0)
+	require.NoError(t, err)
+	require.False(t, page.HasMore)
+	require.Len(t, page.Transactions, 1)
+	require.Equal(t, solOut, page.Transactions[0].Signature)
+	require.Equal(t, types.HistoryTypeSOLOut, page.Transactions[0].Type)
+	require.EqualValues(t, 1_000_000, page.Transactions[0].Amount.Amount)
+	require.Equal(t, &other, page.Transactions[0].Counterparty)
+
+	_, err = c.GetWalletHistory(ctx, wallet.ToBase58(), types.HistoryCursor{}, types.MaxHistoryLimit+1)
+	require.ErrorIs(t, err, client.ErrInvalidHistoryLimit)
+}
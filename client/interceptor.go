@@ -0,0 +1,280 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// RPC error classes of the failed calls.
+const (
+	RPCErrorNetwork   RPCErrorClass = "network"   // the endpoint is unreachable
+	RPCErrorTimeout   RPCErrorClass = "timeout"   // the deadline of the context or the connection exceeded
+	RPCErrorCanceled  RPCErrorClass = "canceled"  // the caller cancelled the context
+	RPCErrorThrottled RPCErrorClass = "throttled" // http status 429
+	RPCErrorServer    RPCErrorClass = "server"    // http status 5xx
+	RPCErrorHTTP      RPCErrorClass = "http"      // other non-2xx http status
+	RPCErrorRPC       RPCErrorClass = "rpc"       // json rpc error response
+)
+
+type (
+	// RPCErrorClass is the class of the error of the failed json rpc call.
+	RPCErrorClass string
+
+	// RPCCall describes a json rpc call of the client.
+	// The method is known before the call, the other fields are filled by the invoker.
+	RPCCall struct {
+		Method     string        // json rpc method, the first method of a batch
+		BatchSize  int           // number of the requests, more than 1 for a batch
+		Endpoint   string        // scheme and host of the endpoint which answered the call, without the API keys
+		StatusCode int           // http status code, 0 if there is no response
+		Latency    time.Duration // duration of the call including the retries
+		Retries    int           // retries of the throttled requests and failovers to other endpoints
+		ErrorClass RPCErrorClass // empty if the call succeeded
+	}
+
+	// RPCInvoker makes the json rpc call.
+	// Returns an error if the call failed, it is *RPCCallError unless the endpoint is unreachable.
+	RPCInvoker func(ctx context.Context, call *RPCCall) error
+
+	// RPCInterceptor intercepts every json rpc call of the client.
+	// It must call the invoker to make the call and may pass it a derived context.
+	// The call fails with ErrRPCCallNotInvoked if the interceptor neither calls the invoker nor returns an error.
+	RPCInterceptor func(ctx context.Context, call *RPCCall, invoke RPCInvoker) error
+
+	// RPCCallError is the error of the json rpc call which got a response.
+	RPCCallError struct {
+		Class      RPCErrorClass
+		StatusCode int
+		Message    string
+	}
+
+	// interceptorTransport is the http transport which runs the json rpc calls through the interceptors.
+	interceptorTransport struct {
+		interceptors []RPCInterceptor
+		next         http.RoundTripper
+	}
+
+	// rpcCallTracker collects the endpoint and the retries of the call from the inner transports.
+	rpcCallTracker struct {
+		mu       sync.Mutex
+		endpoint string
+		retries  int
+	}
+
+	rpcCallTrackerKey struct{}
+)
+
+// ErrRPCCallNotInvoked is returned if the interceptors neither invoked the call nor returned an error.
+var ErrRPCCallNotInvoked = errors.New("rpc interceptor did not invoke the call")
+
+// Error returns the error message.
+func (e *RPCCallError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("rpc call failed: %s: %s", e.Class, e.Message)
+	}
+	return fmt.Sprintf("rpc call failed: %s: status code %d", e.Class, e.StatusCode)
+}
+
+// RoundTrip runs the json rpc request through the interceptors.
+// The error returned by the interceptors after the invoke fails the call, unless it is the invoke error.
+func (t *interceptorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	call := &RPCCall{Endpoint: endpointAddress(req.URL.String())}
+	call.Method, call.BatchSize = rpcMethod(body)
+
+	var resp *http.Response
+	var transportErr, invokeErr error
+	invoked := false
+	invoke := func(ctx context.Context, call *RPCCall) error {
+		invoked = true
+
+		tracker := &rpcCallTracker{}
+		r := req.Clone(context.WithValue(ctx, rpcCallTrackerKey{}, tracker))
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+
+		start := time.Now()
+		resp, transportErr = t.next.RoundTrip(r)
+		if transportErr == nil {
+			resp, transportErr = bufferResponse(resp)
+		}
+		call.Latency = time.Since(start)
+
+		tracker.mu.Lock()
+		if tracker.endpoint != "" {
+			call.Endpoint = endpointAddress(tracker.endpoint)
+		}
+		call.Retries = tracker.retries
+		tracker.mu.Unlock()
+
+		if transportErr != nil {
+			call.ErrorClass = networkErrorClass(ctx, transportErr)
+			invokeErr = transportErr
+			return invokeErr
+		}
+
+		call.StatusCode = resp.StatusCode
+		if err := responseError(resp); err != nil {
+			call.ErrorClass = err.Class
+			invokeErr = err
+			return invokeErr
+		}
+
+		invokeErr = nil
+		return nil
+	}
+
+	err := chainInterceptors(t.interceptors, invoke)(req.Context(), call)
+	if !invoked {
+		// the interceptor rejected the call
+		if err == nil {
+			err = ErrRPCCallNotInvoked
+		}
+		return nil, err
+	}
+	if err != nil && err != invokeErr {
+		// the interceptor failed the call after the invoke
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return nil, err
+	}
+
+	return resp, transportErr
+}
+
+// endpointAddress returns the scheme and the host of the endpoint, the API keys in the path,
+// the query or the userinfo are not exposed to the metrics and the traces.
+func endpointAddress(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// chainInterceptors wraps the invoker with the interceptors, the first interceptor is the outermost.
+func chainInterceptors(interceptors []RPCInterceptor, invoke RPCInvoker) RPCInvoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoke
+		invoke = func(ctx context.Context, call *RPCCall) error {
+			return interceptor(ctx, call, next)
+		}
+	}
+
+	return invoke
+}
+
+// rpcMethod returns the method of the json rpc request and the number of the requests.
+func rpcMethod(body []byte) (string, int) {
+	var req struct {
+		Method string `json:"method"`
+	}
+	if err := json.Unmarshal(body, &req); err == nil {
+		return req.Method, 1
+	}
+
+	var batch []struct {
+		Method string `json:"method"`
+	}
+	if err := json.Unmarshal(body, &batch); err == nil && len(batch) > 0 {
+		return batch[0].Method, len(batch)
+	}
+
+	return "", 1
+}
+
+// responseError returns the error of the buffered response or nil if the call succeeded.
+func responseError(resp *http.Response) *RPCCallError {
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return &RPCCallError{Class: RPCErrorThrottled, StatusCode: resp.StatusCode}
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return &RPCCallError{Class: RPCErrorServer, StatusCode: resp.StatusCode}
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return &RPCCallError{Class: RPCErrorHTTP, StatusCode: resp.StatusCode}
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	type rpcError struct {
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	var single rpcError
+	if err := json.Unmarshal(body, &single); err == nil {
+		if single.Error != nil {
+			return &RPCCallError{Class: RPCErrorRPC, StatusCode: resp.StatusCode, Message: single.Error.Message}
+		}
+		return nil
+	}
+	var batch []rpcError
+	if err := json.Unmarshal(body, &batch); err == nil {
+		for _, r := range batch {
+			if r.Error != nil {
+				return &RPCCallError{Class: RPCErrorRPC, StatusCode: resp.StatusCode, Message: r.Error.Message}
+			}
+		}
+	}
+
+	return nil
+}
+
+// networkErrorClass returns the class of the error of the call without a response.
+func networkErrorClass(ctx context.Context, err error) RPCErrorClass {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.Canceled) && ctx.Err() != nil:
+		return RPCErrorCanceled
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return RPCErrorTimeout
+	default:
+		return RPCErrorNetwork
+	}
+}
+
+// rpcCallTrackerFromContext returns the call tracker of the request or nil.
+func rpcCallTrackerFromContext(ctx context.Context) *rpcCallTracker {
+	tracker, _ := ctx.Value(rpcCallTrackerKey{}).(*rpcCallTracker)
+	return tracker
+}
+
+// setEndpoint records the endpoint which answered the call.
+func (t *rpcCallTracker) setEndpoint(endpoint string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.endpoint = endpoint
+}
+
+// addRetry records a retry of the call.
+func (t *rpcCallTracker) addRetry() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.retries++
+}
@@ -0,0 +1,222 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/EntySquare/solana/client"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	testTracer struct {
+		mu    sync.Mutex
+		spans []*testSpan
+	}
+
+	testSpan struct {
+		name       string
+		parent     *testSpan
+		attributes map[string]any
+		err        error
+		ended      bool
+	}
+
+	testSpanKey struct{}
+)
+
+func (t *testTracer) Start(ctx context.Context, spanName string) (context.Context, client.Span) {
+	parent, _ := ctx.Value(testSpanKey{}).(*testSpan)
+	span := &testSpan{name: spanName, parent: parent, attributes: make(map[string]any)}
+
+	t.mu.Lock()
+	t.spans = append(t.spans, span)
+	t.mu.Unlock()
+
+	return context.WithValue(ctx, testSpanKey{}, span), span
+}
+
+func (s *testSpan) SetAttribute(key string, value any) { s.attributes[key] = value }
+func (s *testSpan) RecordError(err error)              { s.err = err }
+func (s *testSpan) End()                               { s.ended = true }
+
+func TestInterceptor_Call(t *testing.T) {
+	var calls int32
+	server := newThrottlingServer(t, 1, &calls)
+
+	var recorded []client.RPCCall
+	c := client.New(
+		client.SetSolanaEndpoint(server.URL),
+		client.SetRateLimit(client.RateLimit{}, client.WithRetryBackoff(3, time.Millisecond, time.Millisecond)),
+		client.SetRPCInterceptors(func(ctx context.Context, call *client.RPCCall, invoke client.RPCInvoker) error {
+			err := invoke(ctx, call)
+			recorded = append(recorded, *call)
+			return err
+		}),
+	)
+
+	height, err := c.GetBlockHeight(context.Background())
+	require.NoError(t, err)
+	require.EqualValues(t, 42, height)
+
+	require.Len(t, recorded, 1)
+	require.Equal(t, "getBlockHeight", recorded[0].Method)
+	require.Equal(t, 1, recorded[0].BatchSize)
+	require.Equal(t, server.URL, recorded[0].Endpoint)
+	require.Equal(t, http.StatusOK, recorded[0].StatusCode)
+	require.Equal(t, 1, recorded[0].Retries)
+	require.Empty(t, recorded[0].ErrorClass)
+	require.Greater(t, recorded[0].Latency, time.Duration(0))
+}
+
+func TestInterceptor_ErrorClass(t *testing.T) {
+	var calls int32
+	server := newThrottlingServer(t, 10, &calls)
+
+	var recorded client.RPCCall
+	c := client.New(
+		client.SetSolanaEndpoint(server.URL),
+		client.SetRPCInterceptors(func(ctx context.Context, call *client.RPCCall, invoke client.RPCInvoker) error {
+			err := invoke(ctx, call)
+			recorded = *call
+
+			var callErr *client.RPCCallError
+			require.True(t, errors.As(err, &callErr))
+			require.Equal(t, client.RPCErrorThrottled, callErr.Class)
+			return err
+		}),
+	)
+
+	_, err := c.GetBlockHeight(context.Background())
+	require.Error(t, err)
+	require.Equal(t, client.RPCErrorThrottled, recorded.ErrorClass)
+	require.Equal(t, http.StatusTooManyRequests, recorded.StatusCode)
+}
+
+func TestInterceptor_Reject(t *testing.T) {
+	var calls int32
+	server := newThrottlingServer(t, 0, &calls)
+
+	errRejected := errors.New("rejected")
+	c := client.New(
+		client.SetSolanaEndpoint(server.URL),
+		client.SetRPCInterceptors(func(ctx context.Context, call *client.RPCCall, invoke client.RPCInvoker) error {
+			return errRejected
+		}),
+	)
+
+	_, err := c.GetBlockHeight(context.Background())
+	require.ErrorContains(t, err, errRejected.Error())
+	require.Zero(t, atomic.LoadInt32(&calls))
+}
+
+func TestInterceptor_NotInvoked(t *testing.T) {
+	var calls int32
+	server := newThrottlingServer(t, 0, &calls)
+
+	c := client.New(
+		client.SetSolanaEndpoint(server.URL),
+		client.SetRPCInterceptors(func(ctx context.Context, call *client.RPCCall, invoke client.RPCInvoker) error {
+			return nil
+		}),
+	)
+
+	_, err := c.GetBlockHeight(context.Background())
+	require.ErrorContains(t, err, client.ErrRPCCallNotInvoked.Error())
+	require.Zero(t, atomic.LoadInt32(&calls))
+}
+
+func TestTracingInterceptor(t *testing.T) {
+	var calls int32
+	server := newThrottlingServer(t, 0, &calls)
+
+	tracer := &testTracer{}
+	c := client.New(
+		client.SetSolanaEndpoint(server.URL),
+		client.SetRPCInterceptors(client.TracingInterceptor(tracer)),
+	)
+
+	ctx, parent := tracer.Start(context.Background(), "caller")
+	_, err := c.GetBlockHeight(ctx)
+	require.NoError(t, err)
+
+	require.Len(t, tracer.spans, 2)
+	span := tracer.spans[1]
+	require.Equal(t, "solana.rpc/getBlockHeight", span.name)
+	require.Same(t, parent, span.parent)
+	require.True(t, span.ended)
+	require.NoError(t, span.err)
+	require.Equal(t, "getBlockHeight", span.attributes["rpc.method"])
+	require.Equal(t, server.URL, span.attributes["server.address"])
+}
+
+func TestPrometheusMetrics(t *testing.T) {
+	var calls int32
+	server := newThrottlingServer(t, 0, &calls)
+
+	metrics := client.NewPrometheusMetrics("solana", 0.5, 1)
+	c := client.New(
+		client.SetSolanaEndpoint(server.URL),
+		client.SetRPCInterceptors(client.MetricsInterceptor(metrics)),
+	)
+
+	for i := 0; i < 2; i++ {
+		_, err := c.GetBlockHeight(context.Background())
+		require.NoError(t, err)
+	}
+
+	var buf bytes.Buffer
+	_, err := metrics.WriteTo(&buf)
+	require.NoError(t, err)
+
+	labels := `method="getBlockHeight",endpoint="` + server.URL + `"`
+	require.Contains(t, buf.String(), `solana_rpc_requests_total{`+labels+`,error_class=""} 2`)
+	require.Contains(t, buf.String(), `solana_rpc_retries_total{`+labels+`} 0`)
+	require.Contains(t, buf.String(), `solana_rpc_request_duration_seconds_bucket{`+labels+`,le="0.5"} 2`)
+	require.Contains(t, buf.String(), `solana_rpc_request_duration_seconds_count{`+labels+`} 2`)
+}
+
+func TestInterceptor_EndpointRedacted(t *testing.T) {
+	var calls int32
+	server := newThrottlingServer(t, 0, &calls)
+
+	var recorded client.RPCCall
+	c := client.New(
+		client.SetSolanaEndpoint(server.URL+"/v1/secret-path?api-key=secret"),
+		client.SetRPCInterceptors(func(ctx context.Context, call *client.RPCCall, invoke client.RPCInvoker) error {
+			err := invoke(ctx, call)
+			recorded = *call
+			return err
+		}),
+	)
+
+	_, err := c.GetBlockHeight(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, server.URL, recorded.Endpoint)
+}
+
+func TestInterceptor_ErrorAfterInvoke(t *testing.T) {
+	var calls int32
+	server := newThrottlingServer(t, 0, &calls)
+
+	errRejected := errors.New("rejected after the call")
+	c := client.New(
+		client.SetSolanaEndpoint(server.URL),
+		client.SetRPCInterceptors(func(ctx context.Context, call *client.RPCCall, invoke client.RPCInvoker) error {
+			if err := invoke(ctx, call); err != nil {
+				return err
+			}
+			return errRejected
+		}),
+	)
+
+	_, err := c.GetBlockHeight(context.Background())
+	require.ErrorContains(t, err, errRejected.Error())
+	require.EqualValues(t, 1, atomic.LoadInt32(&calls))
+}
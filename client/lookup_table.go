@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"math"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/program/address_lookup_table"
+	sdktypes "github.com/EntySquare/solana-go-sdk/types"
+	commonx "github.com/EntySquare/solana/common"
+	"github.com/EntySquare/solana/types"
+	"github.com/EntySquare/solana/utils"
+)
+
+// GetLookupTable returns the state of the address lookup table with the given base58 encoded address.
+// Returns the lookup table state or an error.
+func (c *Client) GetLookupTable(ctx context.Context, base58Addr string) (address_lookup_table.AddressLookupTable, error) {
+	if err := commonx.ValidateSolanaWalletAddr(base58Addr); err != nil {
+		return address_lookup_table.AddressLookupTable{}, utils.StackErrors(ErrGetLookupTable, err)
+	}
+
+	accInfo, err := c.getAccountInfo(ctx, base58Addr)
+	if err != nil {
+		return address_lookup_table.AddressLookupTable{}, utils.StackErrors(ErrGetLookupTable, err)
+	}
+	if accInfo.Owner == (common.PublicKey{}) {
+		return address_lookup_table.AddressLookupTable{}, utils.StackErrors(ErrGetLookupTable, ErrLookupTableNotFound)
+	}
+	if uint64(len(accInfo.Data)) < types.LookupTableMetaSize {
+		return address_lookup_table.AddressLookupTable{}, utils.StackErrors(ErrGetLookupTable, ErrInvalidLookupTable)
+	}
+
+	table, err := address_lookup_table.DeserializeLookupTable(accInfo.Data, accInfo.Owner)
+	if err != nil {
+		return address_lookup_table.AddressLookupTable{}, utils.StackErrors(ErrGetLookupTable, ErrInvalidLookupTable, err)
+	}
+	if table.ProgramState != address_lookup_table.ProgramStateLookupTable {
+		return address_lookup_table.AddressLookupTable{}, utils.StackErrors(ErrGetLookupTable, ErrLookupTableNotFound)
+	}
+	if uint(len(table.Addresses)) > types.LookupTableMaxAddresses {
+		return address_lookup_table.AddressLookupTable{}, utils.StackErrors(ErrGetLookupTable, ErrInvalidLookupTable)
+	}
+
+	return table, nil
+}
+
+// GetLookupTableAddresses returns the addresses stored in the active address lookup table.
+// The table is always fetched, the addresses are cached to find the writable accounts of the sent transactions using the table.
+// Returns the list of addresses or an error if the table does not exist or is deactivated.
+func (c *Client) GetLookupTableAddresses(ctx context.Context, base58Addr string) ([]common.PublicKey, error) {
+	table, err := c.GetLookupTable(ctx, base58Addr)
+	if err != nil {
+		return nil, err
+	}
+	if table.DeactivationSlot != math.MaxUint64 {
+		return nil, utils.StackErrors(ErrGetLookupTable, ErrLookupTableDeactivated)
+	}
+	if c.cache != nil {
+		c.cache.Set(CacheKey{Kind: cacheKindLookupTable, Account: base58Addr, Commitment: c.Commitment(ctx)}, append([]common.PublicKey(nil), table.Addresses...), c.cacheTTL)
+	}
+
+	return table.Addresses, nil
+}
+
+// GetLookupTableAccount returns the address lookup table account ready to be used in a v0 transaction.
+// Returns the lookup table account or an error.
+func (c *Client) GetLookupTableAccount(ctx context.Context, base58Addr string) (sdktypes.AddressLookupTableAccount, error) {
+	addresses, err := c.GetLookupTableAddresses(ctx, base58Addr)
+	if err != nil {
+		return sdktypes.AddressLookupTableAccount{}, err
+	}
+
+	return sdktypes.AddressLookupTableAccount{
+		Key:       common.PublicKeyFromString(base58Addr),
+		Addresses: addresses,
+	}, nil
+}
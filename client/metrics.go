@@ -0,0 +1,206 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// defaultLatencyBuckets are the default upper bounds of the latency histogram in seconds.
+var defaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type (
+	// RPCMetrics records the finished RPC calls, e.g. to export them to a metrics system.
+	RPCMetrics interface {
+		ObserveRPC(call RPCCall)
+	}
+
+	// PrometheusMetrics is the RPC metrics exporter in the Prometheus text format.
+	// It serves the metrics over http, so it can be mounted as the scrape target.
+	PrometheusMetrics struct {
+		namespace string
+		buckets   []float64
+
+		mu        sync.Mutex
+		requests  map[prometheusCallLabels]uint64
+		retries   map[prometheusEndpointLabels]uint64
+		latencies map[prometheusEndpointLabels]*prometheusHistogram
+	}
+
+	prometheusEndpointLabels struct {
+		method   string
+		endpoint string
+	}
+
+	prometheusCallLabels struct {
+		prometheusEndpointLabels
+		errorClass RPCErrorClass
+	}
+
+	prometheusHistogram struct {
+		counts []uint64 // cumulative counts of the buckets
+		sum    float64
+		count  uint64
+	}
+)
+
+// MetricsInterceptor returns the interceptor which records every RPC call to the metrics.
+func MetricsInterceptor(metrics RPCMetrics) RPCInterceptor {
+	return func(ctx context.Context, call *RPCCall, invoke RPCInvoker) error {
+		err := invoke(ctx, call)
+		metrics.ObserveRPC(*call)
+		return err
+	}
+}
+
+// NewPrometheusMetrics creates a new Prometheus exporter of the RPC metrics.
+// The metric names are prefixed with the namespace, e.g. "solana".
+// The latency buckets are in seconds; default is from 5ms to 10s.
+func NewPrometheusMetrics(namespace string, latencyBuckets ...float64) *PrometheusMetrics {
+	if len(latencyBuckets) == 0 {
+		latencyBuckets = defaultLatencyBuckets
+	}
+	buckets := append([]float64(nil), latencyBuckets...)
+	sort.Float64s(buckets)
+
+	return &PrometheusMetrics{
+		namespace: namespace,
+		buckets:   buckets,
+		requests:  make(map[prometheusCallLabels]uint64),
+		retries:   make(map[prometheusEndpointLabels]uint64),
+		latencies: make(map[prometheusEndpointLabels]*prometheusHistogram),
+	}
+}
+
+// ObserveRPC records the RPC call.
+func (m *PrometheusMetrics) ObserveRPC(call RPCCall) {
+	endpointLabels := prometheusEndpointLabels{method: call.Method, endpoint: call.Endpoint}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[prometheusCallLabels{prometheusEndpointLabels: endpointLabels, errorClass: call.ErrorClass}]++
+	m.retries[endpointLabels] += uint64(call.Retries)
+
+	histogram, ok := m.latencies[endpointLabels]
+	if !ok {
+		histogram = &prometheusHistogram{counts: make([]uint64, len(m.buckets))}
+		m.latencies[endpointLabels] = histogram
+	}
+	seconds := call.Latency.Seconds()
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			histogram.counts[i]++
+		}
+	}
+	histogram.sum += seconds
+	histogram.count++
+}
+
+// WriteTo writes the metrics in the Prometheus text format.
+// Returns the number of bytes written or an error.
+func (m *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+
+	name := m.metricName("rpc_requests_total")
+	fmt.Fprintf(bw, "# HELP %s Number of the RPC calls.\n# TYPE %s counter\n", name, name)
+	callLabels := make([]prometheusCallLabels, 0, len(m.requests))
+	for labels := range m.requests {
+		callLabels = append(callLabels, labels)
+	}
+	sort.Slice(callLabels, func(i, j int) bool {
+		if callLabels[i].prometheusEndpointLabels != callLabels[j].prometheusEndpointLabels {
+			return callLabels[i].less(callLabels[j].prometheusEndpointLabels)
+		}
+		return callLabels[i].errorClass < callLabels[j].errorClass
+	})
+	for _, labels := range callLabels {
+		fmt.Fprintf(bw, "%s{%s,error_class=%s} %d\n",
+			name, labels.format(), prometheusLabelValue(string(labels.errorClass)), m.requests[labels])
+	}
+
+	endpointLabels := make([]prometheusEndpointLabels, 0, len(m.retries))
+	for labels := range m.retries {
+		endpointLabels = append(endpointLabels, labels)
+	}
+	sort.Slice(endpointLabels, func(i, j int) bool { return endpointLabels[i].less(endpointLabels[j]) })
+
+	name = m.metricName("rpc_retries_total")
+	fmt.Fprintf(bw, "# HELP %s Number of the retries of the throttled RPC calls and failovers to other endpoints.\n", name)
+	fmt.Fprintf(bw, "# TYPE %s counter\n", name)
+	for _, labels := range endpointLabels {
+		fmt.Fprintf(bw, "%s{%s} %d\n", name, labels.format(), m.retries[labels])
+	}
+
+	name = m.metricName("rpc_request_duration_seconds")
+	fmt.Fprintf(bw, "# HELP %s Latency of the RPC calls including the retries.\n# TYPE %s histogram\n", name, name)
+	for _, labels := range endpointLabels {
+		histogram := m.latencies[labels]
+		for i, bound := range m.buckets {
+			fmt.Fprintf(bw, "%s_bucket{%s,le=\"%s\"} %d\n",
+				name, labels.format(), strconv.FormatFloat(bound, 'g', -1, 64), histogram.counts[i])
+		}
+		fmt.Fprintf(bw, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels.format(), histogram.count)
+		fmt.Fprintf(bw, "%s_sum{%s} %s\n", name, labels.format(), strconv.FormatFloat(histogram.sum, 'g', -1, 64))
+		fmt.Fprintf(bw, "%s_count{%s} %d\n", name, labels.format(), histogram.count)
+	}
+
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// ServeHTTP serves the metrics in the Prometheus text format.
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// metricName returns the metric name prefixed with the namespace.
+func (m *PrometheusMetrics) metricName(name string) string {
+	if m.namespace == "" {
+		return name
+	}
+	return m.namespace + "_" + name
+}
+
+// less orders the labels by the method and the endpoint.
+func (l prometheusEndpointLabels) less(other prometheusEndpointLabels) bool {
+	if l.method != other.method {
+		return l.method < other.method
+	}
+	return l.endpoint < other.endpoint
+}
+
+// format returns the method and the endpoint labels.
+func (l prometheusEndpointLabels) format() string {
+	return "method=" + prometheusLabelValue(l.method) + ",endpoint=" + prometheusLabelValue(l.endpoint)
+}
+
+// prometheusLabelValue returns the quoted and escaped label value.
+func prometheusLabelValue(value string) string {
+	value = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+	return `"` + value + `"`
+}
+
+// countingWriter counts the bytes written to the underlying writer.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write writes the bytes to the underlying writer.
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,230 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/EntySquare/solana-go-sdk/client"
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/rpc"
+	commonx "github.com/EntySquare/solana/common"
+	"github.com/EntySquare/solana/parser"
+	"github.com/EntySquare/solana/types"
+	"github.com/EntySquare/solana/utils"
+)
+
+// ValidatePayment validates the transactions of the payment reference against the request.
+// Every transaction of the reference is checked by its instructions, from the oldest one:
+// the oldest valid transaction is the payment, the later valid ones are rejected as duplicates.
+// The reference is public, so its history is paged through and validated in chunks of types.MaxPaymentCandidates
+// transactions from the oldest one; the chunks after the one with the payment are not validated,
+// so the dust transactions attached to the reference can not hide the payment.
+// Returns the verdict or an error if the transactions can not be fetched.
+func (c *Client) ValidatePayment(ctx context.Context, req types.PaymentRequest) (types.PaymentVerdict, error) {
+	if err := validatePaymentRequest(req); err != nil {
+		return types.PaymentVerdict{}, utils.StackErrors(ErrValidatePayment, err)
+	}
+
+	signatures, err := c.referenceSignatures(ctx, req.Reference)
+	if err != nil {
+		return types.PaymentVerdict{}, utils.StackErrors(ErrValidatePayment, err)
+	}
+	if len(signatures) == 0 {
+		return types.PaymentVerdict{Status: types.PaymentStatusNotFound, Candidates: []types.PaymentCandidate{}}, nil
+	}
+
+	verdict := types.PaymentVerdict{
+		Status:     types.PaymentStatusInvalid,
+		Candidates: make([]types.PaymentCandidate, 0, len(signatures)),
+	}
+	for start := 0; start < len(signatures) && verdict.Signature == ""; start += types.MaxPaymentCandidates {
+		end := start + types.MaxPaymentCandidates
+		if end > len(signatures) {
+			end = len(signatures)
+		}
+		chunk := signatures[start:end]
+
+		txs, err := c.getTransactions(ctx, chunk)
+		if err != nil {
+			return types.PaymentVerdict{}, utils.StackErrors(ErrValidatePayment, err)
+		}
+
+		for i, tx := range txs {
+			candidate := CheckPaymentTransaction(req, chunk[i].Signature, tx)
+			if candidate.Valid() {
+				if verdict.Signature == "" {
+					verdict.Signature = candidate.Signature
+					verdict.Status = types.PaymentStatusValid
+				} else {
+					verdict.Duplicates = append(verdict.Duplicates, candidate.Signature)
+					verdict.Status = types.PaymentStatusDuplicate
+					candidate.Err = utils.StackErrors(ErrPaymentDuplicate, fmt.Errorf("payment %s", verdict.Signature))
+					candidate.Reason = candidate.Err.Error()
+				}
+			}
+			verdict.Candidates = append(verdict.Candidates, candidate)
+		}
+	}
+
+	return verdict, nil
+}
+
+// referenceSignatures pages through the signatures of the reference.
+// Returns the signatures from the oldest one or an error.
+func (c *Client) referenceSignatures(ctx context.Context, reference common.PublicKey) (rpc.GetSignaturesForAddress, error) {
+	var signatures rpc.GetSignaturesForAddress
+	before := ""
+	for {
+		page, err := c.rpcClient.GetSignaturesForAddressWithConfig(ctx, reference.ToBase58(), client.GetSignaturesForAddressConfig{
+			Limit:      types.MaxPaymentCandidates,
+			Before:     before,
+			Commitment: c.historyCommitment(ctx),
+		})
+		if err != nil {
+			return nil, err
+		}
+		signatures = append(signatures, page...)
+		if len(page) < types.MaxPaymentCandidates {
+			break
+		}
+		before = page[len(page)-1].Signature
+	}
+
+	// the signatures are ordered from the newest one
+	for i, j := 0, len(signatures)-1; i < j; i, j = i+1, j-1 {
+		signatures[i], signatures[j] = signatures[j], signatures[i]
+	}
+
+	return signatures, nil
+}
+
+// CheckPaymentTransaction checks the fetched transaction against the payment request by its instructions, including the inner ones.
+// The transaction must succeed, include the reference and transfer exactly the amount to the recipient:
+// the System transfers to the recipient wallet for SOL, or the token transfers to the recipient
+// associated token account of the splToken mint. The token account is derived for the program of the transfer,
+// the SPL Token or the Token-2022 program. If the memo is requested, a memo instruction must match it.
+// Returns the candidate with the error why the transaction is not a valid payment, if any.
+func CheckPaymentTransaction(req types.PaymentRequest, signature string, tx *client.Transaction) types.PaymentCandidate {
+	candidate := types.PaymentCandidate{
+		Signature: signature,
+		Slot:      tx.Slot,
+		BlockTime: tx.BlockTime,
+	}
+	if len(tx.AccountKeys) > 0 {
+		candidate.Payer = tx.AccountKeys[0]
+	}
+	reject := func(err error) types.PaymentCandidate {
+		candidate.Err = err
+		candidate.Reason = err.Error()
+		return candidate
+	}
+
+	if tx.Meta == nil {
+		return reject(ErrTransactionNotFound)
+	}
+	if tx.Meta.Err != nil {
+		return reject(utils.StackErrors(ErrPaymentFailed, ParseTransactionError(tx.Meta.Err)))
+	}
+	if !hasAccount(tx.AccountKeys, req.Reference) {
+		return reject(utils.StackErrors(ErrPaymentReferenceMissing, fmt.Errorf("reference %s", req.Reference.ToBase58())))
+	}
+
+	// the destination by the program of the transfer
+	destinations := map[common.PublicKey]common.PublicKey{common.SystemProgramID: req.Recipient}
+	if req.SPLToken != nil {
+		destinations = make(map[common.PublicKey]common.PublicKey, 2)
+		for _, program := range []common.PublicKey{common.TokenProgramID, commonx.Token2022ProgramID} {
+			ata, err := commonx.DeriveTokenAccountPubkeyWithProgram(req.Recipient, *req.SPLToken, program)
+			if err != nil {
+				return reject(err)
+			}
+			destinations[program] = ata
+		}
+	}
+
+	instructions, err := parser.ParseTransaction(tx)
+	if err != nil {
+		return reject(utils.StackErrors(ErrPaymentTransferNotFound, err))
+	}
+
+	var found, overflow bool
+	var memos []string
+	credit := func(payer common.PublicKey, amount uint64) {
+		if !found {
+			candidate.Payer = payer
+			found = true
+		}
+		if candidate.Amount+amount < candidate.Amount {
+			overflow = true
+		}
+		candidate.Amount += amount
+	}
+	for _, ix := range parser.Flatten(instructions) {
+		destination, ok := destinations[ix.ProgramID]
+		switch p := ix.Parsed.(type) {
+		case parser.SystemTransfer:
+			if ok && p.To == destination && p.From != destination {
+				credit(p.From, p.Lamports)
+			}
+		case parser.TokenTransferChecked:
+			// the token program rejects the transfer of another mint to the associated token account
+			if ok && p.Destination == destination && p.Source != destination && p.Mint == *req.SPLToken {
+				credit(p.Authority, p.Amount)
+			}
+		case parser.TokenTransfer:
+			if ok && p.Destination == destination && p.Source != destination {
+				credit(p.Authority, p.Amount)
+			}
+		case parser.Memo:
+			memos = append(memos, p.Text)
+		}
+	}
+
+	if !found {
+		return reject(utils.StackErrors(ErrPaymentTransferNotFound, fmt.Errorf("recipient %s", req.Recipient.ToBase58())))
+	}
+	if overflow || candidate.Amount != req.Amount {
+		return reject(utils.StackErrors(ErrPaymentAmountMismatch, fmt.Errorf("%d != %d", candidate.Amount, req.Amount)))
+	}
+	if req.Memo != nil && !contains(memos, *req.Memo) {
+		return reject(utils.StackErrors(ErrPaymentMemoMismatch, fmt.Errorf("memos %q, want %q", strings.Join(memos, ", "), *req.Memo)))
+	}
+
+	return candidate
+}
+
+// validatePaymentRequest checks the required fields of the payment request.
+func validatePaymentRequest(req types.PaymentRequest) error {
+	switch {
+	case req.Recipient == (common.PublicKey{}):
+		return utils.StackErrors(ErrInvalidPaymentRequest, fmt.Errorf("missing recipient"))
+	case req.Reference == (common.PublicKey{}):
+		return utils.StackErrors(ErrInvalidPaymentRequest, fmt.Errorf("missing reference"))
+	case req.Amount == 0:
+		return utils.StackErrors(ErrInvalidPaymentRequest, fmt.Errorf("zero amount"))
+	case req.SPLToken != nil && *req.SPLToken == (common.PublicKey{}):
+		return utils.StackErrors(ErrInvalidPaymentRequest, fmt.Errorf("empty splToken mint"))
+	}
+	return nil
+}
+
+// hasAccount reports whether the key is one of the accounts.
+func hasAccount(accounts []common.PublicKey, key common.PublicKey) bool {
+	for _, account := range accounts {
+		if account == key {
+			return true
+		}
+	}
+	return false
+}
+
+// contains reports whether the value is one of the values.
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
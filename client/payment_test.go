@@ -0,0 +1,227 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/program/memo"
+	"github.com/EntySquare/solana-go-sdk/program/system"
+	"github.com/EntySquare/solana-go-sdk/program/token"
+	sdktypes "github.com/EntySquare/solana-go-sdk/types"
+	"github.com/EntySquare/solana/client"
+	commonx "github.com/EntySquare/solana/common"
+	"github.com/EntySquare/solana/tests/mock"
+	"github.com/EntySquare/solana/types"
+	"github.com/stretchr/testify/require"
+)
+
+// withReference adds the reference to the accounts of the instruction like the Solana Pay wallets.
+func withReference(ix sdktypes.Instruction, reference common.PublicKey) sdktypes.Instruction {
+	ix.Accounts = append(ix.Accounts, sdktypes.AccountMeta{PubKey: reference})
+	return ix
+}
+
+func TestClient_ValidatePayment_SOL(t *testing.T) {
+	ctx := context.Background()
+	fake := mock.NewRPCServer()
+	defer fake.Close()
+
+	payer := sdktypes.NewAccount().PublicKey
+	recipient := sdktypes.NewAccount().PublicKey
+	reference := sdktypes.NewAccount().PublicKey
+	req := types.PaymentRequest{Recipient: recipient, Amount: 1_000_000, Reference: reference}
+	transfer := func(amount uint64) sdktypes.Instruction {
+		return withReference(system.Transfer(system.TransferParam{From: payer, To: recipient, Amount: amount}), reference)
+	}
+
+	c := client.New(client.SetSolanaEndpoint(fake.URL))
+
+	verdict, err := c.ValidatePayment(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, types.PaymentStatusNotFound, verdict.Status)
+
+	// the underpayment is not the oldest payment anymore
+	underpaid := fake.AddTransaction(historyTransaction(payer, nil, nil, transfer(999_999)))
+	paid := fake.AddTransaction(historyTransaction(payer, nil, nil, transfer(1_000_000)))
+
+	verdict, err = c.ValidatePayment(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, types.PaymentStatusValid, verdict.Status)
+	require.Equal(t, paid, verdict.Signature)
+	require.Len(t, verdict.Candidates, 2)
+	require.Equal(t, underpaid, verdict.Candidates[0].Signature)
+	require.ErrorIs(t, verdict.Candidates[0].Err, client.ErrPaymentAmountMismatch)
+	require.NotEmpty(t, verdict.Candidates[0].Reason)
+	require.True(t, verdict.Candidates[1].Valid())
+	require.Equal(t, payer, verdict.Candidates[1].Payer)
+	require.EqualValues(t, 1_000_000, verdict.Candidates[1].Amount)
+
+	signature, err := c.ValidateTransactionByReference(ctx, reference.ToBase58(), recipient.ToBase58(), 1_000_000, "SOL")
+	require.NoError(t, err)
+	require.Equal(t, paid, signature)
+
+	// the second payment is rejected as a duplicate
+	duplicate := fake.AddTransaction(historyTransaction(payer, nil, nil, transfer(1_000_000)))
+	verdict, err = c.ValidatePayment(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, types.PaymentStatusDuplicate, verdict.Status)
+	require.Equal(t, paid, verdict.Signature)
+	require.Equal(t, []string{duplicate}, verdict.Duplicates)
+	require.ErrorIs(t, verdict.Candidates[2].Err, client.ErrPaymentDuplicate)
+
+	_, err = c.ValidateTransactionByReference(ctx, reference.ToBase58(), recipient.ToBase58(), 1_000_000, "")
+	require.ErrorIs(t, err, client.ErrPaymentDuplicate)
+
+	_, err = c.ValidatePayment(ctx, types.PaymentRequest{Recipient: recipient, Reference: reference})
+	require.ErrorIs(t, err, client.ErrInvalidPaymentRequest)
+}
+
+func TestClient_ValidatePayment_Dust(t *testing.T) {
+	ctx := context.Background()
+	fake := mock.NewRPCServer()
+	defer fake.Close()
+
+	payer := sdktypes.NewAccount().PublicKey
+	recipient := sdktypes.NewAccount().PublicKey
+	reference := sdktypes.NewAccount().PublicKey
+	req := types.PaymentRequest{Recipient: recipient, Amount: 1_000_000, Reference: reference}
+	transfer := func(amount uint64) sdktypes.Instruction {
+		return withReference(system.Transfer(system.TransferParam{From: payer, To: recipient, Amount: amount}), reference)
+	}
+
+	c := client.New(client.SetSolanaEndpoint(fake.URL))
+
+	// the dust attached to the public reference does not hide the payment
+	dust := types.MaxPaymentCandidates + 50
+	for i := 0; i < dust; i++ {
+		fake.AddTransaction(historyTransaction(payer, nil, nil, transfer(1)))
+	}
+	paid := fake.AddTransaction(historyTransaction(payer, nil, nil, transfer(1_000_000)))
+	for i := 0; i < types.MaxPaymentCandidates; i++ {
+		fake.AddTransaction(historyTransaction(payer, nil, nil, transfer(1)))
+	}
+
+	verdict, err := c.ValidatePayment(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, types.PaymentStatusValid, verdict.Status)
+	require.Equal(t, paid, verdict.Signature)
+	// the chunk after the one with the payment is not validated
+	require.Len(t, verdict.Candidates, 2*types.MaxPaymentCandidates)
+	require.ErrorIs(t, verdict.Candidates[0].Err, client.ErrPaymentAmountMismatch)
+	require.True(t, verdict.Candidates[dust].Valid())
+	require.Len(t, fake.RequestsOf("getSignaturesForAddress"), 3)
+}
+
+func TestClient_ValidatePayment_SPLToken(t *testing.T) {
+	ctx := context.Background()
+	fake := mock.NewRPCServer()
+	defer fake.Close()
+
+	payer := sdktypes.NewAccount().PublicKey
+	recipient := sdktypes.NewAccount().PublicKey
+	reference := sdktypes.NewAccount().PublicKey
+	mint := sdktypes.NewAccount().PublicKey
+	otherMint := sdktypes.NewAccount().PublicKey
+	source, _, _ := common.FindAssociatedTokenAddress(payer, mint)
+	recipientAta, _, _ := common.FindAssociatedTokenAddress(recipient, mint)
+	auxiliary := sdktypes.NewAccount().PublicKey // another token account of the recipient
+	orderMemo := "order 42"
+	req := types.PaymentRequest{Recipient: recipient, Amount: 250, SPLToken: &mint, Reference: reference, Memo: &orderMemo}
+	transfer := func(to, mint common.PublicKey) sdktypes.Instruction {
+		return withReference(token.TransferChecked(token.TransferCheckedParam{
+			From: source, To: to, Mint: mint, Auth: payer, Amount: 250, Decimals: 2,
+		}), reference)
+	}
+	withMemo := func(text string) sdktypes.Instruction {
+		return memo.BuildMemo(memo.BuildMemoParam{Memo: []byte(text)})
+	}
+
+	c := client.New(client.SetSolanaEndpoint(fake.URL))
+
+	wrongAccount := fake.AddTransaction(historyTransaction(payer, nil, nil, transfer(auxiliary, mint), withMemo(orderMemo)))
+	wrongMint := fake.AddTransaction(historyTransaction(payer, nil, nil, transfer(recipientAta, otherMint), withMemo(orderMemo)))
+	wrongMemo := fake.AddTransaction(historyTransaction(payer, nil, nil, transfer(recipientAta, mint), withMemo("order 43")))
+	failed := historyTransaction(payer, nil, nil, transfer(recipientAta, mint), withMemo(orderMemo))
+	failed.Err = map[string]any{"InstructionError": []any{0, map[string]any{"Custom": 1}}}
+	failedSig := fake.AddTransaction(failed)
+
+	verdict, err := c.ValidatePayment(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, types.PaymentStatusInvalid, verdict.Status)
+	require.Empty(t, verdict.Signature)
+	require.Len(t, verdict.Candidates, 4)
+	for i, expected := range []struct {
+		signature string
+		err       error
+	}{
+		{wrongAccount, client.ErrPaymentTransferNotFound},
+		{wrongMint, client.ErrPaymentTransferNotFound},
+		{wrongMemo, client.ErrPaymentMemoMismatch},
+		{failedSig, client.ErrPaymentFailed},
+	} {
+		require.Equal(t, expected.signature, verdict.Candidates[i].Signature)
+		require.ErrorIs(t, verdict.Candidates[i].Err, expected.err)
+	}
+
+	// the legacy validation does not check the memo
+	signature, err := c.ValidateTransactionByReference(ctx, reference.ToBase58(), recipient.ToBase58(), 250, mint.ToBase58())
+	require.NoError(t, err)
+	require.Equal(t, wrongMemo, signature)
+
+	// the latest rejection is reported
+	_, err = c.ValidateTransactionByReference(ctx, reference.ToBase58(), recipient.ToBase58(), 251, mint.ToBase58())
+	require.ErrorIs(t, err, client.ErrPaymentFailed)
+
+	paid := fake.AddTransaction(historyTransaction(payer, nil, nil, transfer(recipientAta, mint), withMemo(orderMemo)))
+	verdict, err = c.ValidatePayment(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, types.PaymentStatusValid, verdict.Status)
+	require.Equal(t, paid, verdict.Signature)
+	require.EqualValues(t, 250, verdict.Candidates[4].Amount)
+
+	// the transaction without the reference is never a candidate, but is rejected if checked directly
+	tx, err := c.GetTransaction(ctx, paid)
+	require.NoError(t, err)
+	candidate := client.CheckPaymentTransaction(types.PaymentRequest{
+		Recipient: recipient, Amount: 250, SPLToken: &mint, Reference: sdktypes.NewAccount().PublicKey,
+	}, paid, tx)
+	require.ErrorIs(t, candidate.Err, client.ErrPaymentReferenceMissing)
+}
+
+func TestClient_ValidatePayment_Token2022(t *testing.T) {
+	ctx := context.Background()
+	fake := mock.NewRPCServer()
+	defer fake.Close()
+
+	payer := sdktypes.NewAccount().PublicKey
+	recipient := sdktypes.NewAccount().PublicKey
+	reference := sdktypes.NewAccount().PublicKey
+	mint := sdktypes.NewAccount().PublicKey
+	source, _ := commonx.DeriveTokenAccountPubkeyWithProgram(payer, mint, commonx.Token2022ProgramID)
+	tokenAta, _ := commonx.DeriveTokenAccountPubkeyWithProgram(recipient, mint, common.TokenProgramID)
+	token2022Ata, _ := commonx.DeriveTokenAccountPubkeyWithProgram(recipient, mint, commonx.Token2022ProgramID)
+	req := types.PaymentRequest{Recipient: recipient, Amount: 250, SPLToken: &mint, Reference: reference}
+	transfer := func(to common.PublicKey) sdktypes.Instruction {
+		ix := token.TransferChecked(token.TransferCheckedParam{
+			From: source, To: to, Mint: mint, Auth: payer, Amount: 250, Decimals: 2,
+		})
+		ix.ProgramID = commonx.Token2022ProgramID
+		return withReference(ix, reference)
+	}
+
+	c := client.New(client.SetSolanaEndpoint(fake.URL))
+
+	// the SPL Token associated token account is not the destination of the Token-2022 transfer
+	wrongAccount := fake.AddTransaction(historyTransaction(payer, nil, nil, transfer(tokenAta)))
+	paid := fake.AddTransaction(historyTransaction(payer, nil, nil, transfer(token2022Ata)))
+
+	verdict, err := c.ValidatePayment(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, types.PaymentStatusValid, verdict.Status)
+	require.Equal(t, paid, verdict.Signature)
+	require.Equal(t, wrongAccount, verdict.Candidates[0].Signature)
+	require.ErrorIs(t, verdict.Candidates[0].Err, client.ErrPaymentTransferNotFound)
+	require.Equal(t, payer, verdict.Candidates[1].Payer)
+	require.EqualValues(t, 250, verdict.Candidates[1].Amount)
+}
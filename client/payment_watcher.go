@@ -0,0 +1,364 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/rpc"
+	"github.com/EntySquare/solana/types"
+	"github.com/EntySquare/solana/utils"
+)
+
+const (
+	// defaultPaymentPollInterval is the default interval between the polls of the watched payments.
+	defaultPaymentPollInterval = 2 * time.Second
+	// defaultPaymentEventsBuffer is the default capacity of the events channel.
+	defaultPaymentEventsBuffer = 64
+	// defaultPaymentMatchedRetention is the default period the references of the finished payments are kept.
+	defaultPaymentMatchedRetention = 24 * time.Hour
+)
+
+type (
+	// PaymentWatcher watches the expected payments and emits their events.
+	// Every poll looks up the signatures of all the watched references with a single JSON-RPC batch,
+	// then validates the new transactions by their instructions, see CheckPaymentTransaction.
+	// A payment is matched exactly once: the confirmed, overpaid and expired events end its watch,
+	// and its reference can not be watched again by the same watcher for the retention period.
+	// The watch ends only after its event is delivered. The transfers that pay the payment
+	// in the same poll after its end are reported as duplicates to refund.
+	PaymentWatcher struct {
+		client    *Client
+		interval  time.Duration
+		retention time.Duration
+		events    chan types.PaymentEvent
+		onError   func(error)
+
+		pollMu sync.Mutex // serializes the polls; guards the signatures of the watched payments
+
+		mu       sync.Mutex
+		seq      uint64
+		payments map[common.PublicKey]*watchedPayment // by reference
+		matched  map[common.PublicKey]time.Time       // references of the finished payments -> end time
+	}
+
+	// PaymentWatcherOption is a function that configures the PaymentWatcher.
+	PaymentWatcherOption func(*PaymentWatcher)
+
+	// watchedPayment is the state of the watched payment.
+	watchedPayment struct {
+		payment   types.ExpectedPayment
+		seq       uint64          // keeps the events in the order of Watch calls
+		seen      map[string]bool // signatures already validated
+		pending   map[string]bool // signatures already reported as pending
+		signature string          // the transaction that ended the watch
+		done      bool            // the final event is delivered, the watch ends after the other events of the poll
+	}
+
+	// referenceSignature is the getSignaturesForAddress item with the confirmation status.
+	referenceSignature struct {
+		Signature          string         `json:"signature"`
+		Err                any            `json:"err"`
+		ConfirmationStatus rpc.Commitment `json:"confirmationStatus"`
+	}
+)
+
+// WithPaymentPollInterval sets the interval between the polls of Run; default is 2 seconds.
+func WithPaymentPollInterval(interval time.Duration) PaymentWatcherOption {
+	return func(w *PaymentWatcher) {
+		w.interval = interval
+	}
+}
+
+// WithPaymentEventsBuffer sets the capacity of the events channel; default is 64.
+// The poll blocks while the channel is full.
+func WithPaymentEventsBuffer(size int) PaymentWatcherOption {
+	return func(w *PaymentWatcher) {
+		w.events = make(chan types.PaymentEvent, size)
+	}
+}
+
+// WithPaymentMatchedRetention sets the period the references of the finished payments are kept
+// to reject their repeated watch; default is 24 hours.
+func WithPaymentMatchedRetention(retention time.Duration) PaymentWatcherOption {
+	return func(w *PaymentWatcher) {
+		w.retention = retention
+	}
+}
+
+// WithPaymentErrorHandler sets the handler of the poll errors of Run; by default they are ignored
+// and the poll is retried on the next interval.
+func WithPaymentErrorHandler(handler func(error)) PaymentWatcherOption {
+	return func(w *PaymentWatcher) {
+		w.onError = handler
+	}
+}
+
+// NewPaymentWatcher creates a payment watcher.
+// The transactions are validated at the commitment of the context of Poll or Run;
+// the processed commitment is raised to confirmed.
+func (c *Client) NewPaymentWatcher(opts ...PaymentWatcherOption) *PaymentWatcher {
+	w := &PaymentWatcher{
+		client:    c,
+		interval:  defaultPaymentPollInterval,
+		retention: defaultPaymentMatchedRetention,
+		payments:  make(map[common.PublicKey]*watchedPayment),
+		matched:   make(map[common.PublicKey]time.Time),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	if w.events == nil {
+		w.events = make(chan types.PaymentEvent, defaultPaymentEventsBuffer)
+	}
+
+	return w
+}
+
+// Events returns the channel of the payment events.
+func (w *PaymentWatcher) Events() <-chan types.PaymentEvent {
+	return w.events
+}
+
+// Watch starts watching the payment.
+// Returns an error if the payment is invalid or its reference is already watched or matched.
+func (w *PaymentWatcher) Watch(payment types.ExpectedPayment) error {
+	if err := validatePaymentRequest(payment.PaymentRequest); err != nil {
+		return utils.StackErrors(ErrWatchPayment, err)
+	}
+	if payment.ID == "" {
+		payment.ID = payment.Reference.ToBase58()
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, watched := w.payments[payment.Reference]
+	_, matched := w.matched[payment.Reference]
+	if watched || matched {
+		return utils.StackErrors(
+			ErrWatchPayment,
+			ErrPaymentAlreadyWatched,
+			fmt.Errorf("reference %s", payment.Reference.ToBase58()),
+		)
+	}
+
+	w.seq++
+	w.payments[payment.Reference] = &watchedPayment{
+		payment: payment,
+		seq:     w.seq,
+		seen:    make(map[string]bool),
+		pending: make(map[string]bool),
+	}
+
+	return nil
+}
+
+// Unwatch stops watching the payment of the reference without an event.
+// Returns false if the reference is not watched.
+func (w *PaymentWatcher) Unwatch(reference common.PublicKey) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, ok := w.payments[reference]
+	delete(w.payments, reference)
+	return ok
+}
+
+// Watched returns the number of the watched payments.
+func (w *PaymentWatcher) Watched() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return len(w.payments)
+}
+
+// Run polls the watched payments on every interval until the context is done.
+// Returns the context error.
+func (w *PaymentWatcher) Run(ctx context.Context) error {
+	tick := time.NewTicker(w.interval)
+	defer tick.Stop()
+
+	for {
+		if err := w.Poll(ctx); err != nil && ctx.Err() == nil && w.onError != nil {
+			w.onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-tick.C:
+		}
+	}
+}
+
+// Poll looks up the new transactions of the watched payments once and emits their events,
+// then expires the payments past the deadline without pending transactions.
+// The transactions below the commitment are reported as pending and validated by a later poll.
+// The transactions that fail to fetch are retried by the next poll, the other payments are not affected.
+// Returns an error if the signatures or some transactions can not be fetched,
+// or the context is done while the events channel is full; the undelivered events are emitted by the next poll.
+func (w *PaymentWatcher) Poll(ctx context.Context) error {
+	w.pollMu.Lock()
+	defer w.pollMu.Unlock()
+
+	w.mu.Lock()
+	now := time.Now()
+	for reference, at := range w.matched {
+		if now.Sub(at) > w.retention {
+			delete(w.matched, reference)
+		}
+	}
+	watched := make([]*watchedPayment, 0, len(w.payments))
+	for _, p := range w.payments {
+		watched = append(watched, p)
+	}
+	w.mu.Unlock()
+	if len(watched) == 0 {
+		return nil
+	}
+	sort.Slice(watched, func(i, j int) bool { return watched[i].seq < watched[j].seq })
+
+	paramsList := make([][]any, len(watched))
+	for i, p := range watched {
+		paramsList[i] = []any{p.payment.Reference.ToBase58(), map[string]any{
+			"limit":      types.MaxPaymentCandidates,
+			"commitment": rpc.CommitmentConfirmed,
+		}}
+	}
+	pages, err := callBatch[[]referenceSignature](ctx, w.client, "getSignaturesForAddress", paramsList)
+	if err != nil {
+		return utils.StackErrors(ErrWatchPayment, err)
+	}
+
+	commitment := w.client.historyCommitment(ctx)
+	var signatures rpc.GetSignaturesForAddress
+	var owners []*watchedPayment
+	pending := make(map[*watchedPayment]bool)
+	for i, p := range watched {
+		// the signatures are ordered from the newest one
+		for j := len(pages[i]) - 1; j >= 0; j-- {
+			signature := pages[i][j]
+			if p.seen[signature.Signature] {
+				continue
+			}
+			if !commitmentReached(signature.ConfirmationStatus, commitment) {
+				pending[p] = true
+				if !p.pending[signature.Signature] {
+					if err := w.emit(ctx, types.PaymentEvent{
+						Type:      types.PaymentEventPending,
+						Payment:   p.payment,
+						Signature: signature.Signature,
+					}); err != nil {
+						return err
+					}
+					p.pending[signature.Signature] = true
+				}
+				continue
+			}
+			signatures = append(signatures, rpc.SignatureWithStatus{Signature: signature.Signature})
+			owners = append(owners, p)
+		}
+	}
+
+	// the transactions of a payment are validated from the oldest one, so the payment that failed to fetch
+	// a transaction skips its later ones; they are fetched again by the next poll
+	txs, fetchErrs := w.client.fetchTransactions(ctx, signatures)
+	errs := []error{ErrWatchPayment}
+	retry := make(map[*watchedPayment]bool)
+	for i, tx := range txs {
+		p := owners[i]
+		if retry[p] {
+			continue
+		}
+		if fetchErrs[i] != nil {
+			retry[p] = true
+			errs = append(errs, fetchErrs[i])
+			continue
+		}
+
+		candidate := CheckPaymentTransaction(p.payment.PaymentRequest, signatures[i].Signature, tx)
+		event := types.PaymentEvent{
+			Payment:   p.payment,
+			Signature: candidate.Signature,
+			Payer:     candidate.Payer,
+			Amount:    candidate.Amount,
+		}
+		paid := candidate.Valid() || errors.Is(candidate.Err, ErrPaymentAmountMismatch)
+		switch {
+		case p.done && !paid:
+			p.seen[signatures[i].Signature] = true
+			continue
+		case p.done:
+			// the payment finished earlier in the same poll, the transfer is to refund
+			event.Type = types.PaymentEventDuplicate
+			event.Err = utils.StackErrors(ErrPaymentDuplicate, fmt.Errorf("payment %s", p.signature))
+		case candidate.Valid():
+			event.Type = types.PaymentEventConfirmed
+		case paid && candidate.Amount < p.payment.Amount:
+			event.Type = types.PaymentEventUnderpaid
+		case paid:
+			event.Type = types.PaymentEventOverpaid
+		default:
+			event.Type = types.PaymentEventFailed
+			event.Err = candidate.Err
+		}
+		// the transaction of the undelivered event is validated again by the next poll
+		if err := w.emit(ctx, event); err != nil {
+			return err
+		}
+		p.seen[signatures[i].Signature] = true
+		if event.Type.Final() {
+			p.signature = candidate.Signature
+			p.done = true
+		}
+	}
+
+	// the payment found by the last poll, with a transaction to fetch again or a pending one is not expired;
+	// the finished payment with a transaction to fetch again is watched until its duplicates are reported
+	for _, p := range watched {
+		if retry[p] {
+			continue
+		}
+		if !p.done {
+			if pending[p] || p.payment.Deadline.IsZero() || !now.After(p.payment.Deadline) {
+				continue
+			}
+			if err := w.emit(ctx, types.PaymentEvent{Type: types.PaymentEventExpired, Payment: p.payment}); err != nil {
+				return err
+			}
+			p.done = true
+		}
+		w.finish(p)
+	}
+
+	if len(errs) > 1 {
+		return utils.StackErrors(errs...)
+	}
+	return nil
+}
+
+// finish ends the watch of the payment, so its reference is never matched again.
+func (w *PaymentWatcher) finish(p *watchedPayment) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.payments[p.payment.Reference] == p {
+		delete(w.payments, p.payment.Reference)
+		w.matched[p.payment.Reference] = time.Now()
+	}
+}
+
+// emit sends the event, blocking while the events channel is full.
+func (w *PaymentWatcher) emit(ctx context.Context, event types.PaymentEvent) error {
+	select {
+	case w.events <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
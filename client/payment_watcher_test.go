@@ -0,0 +1,319 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/program/system"
+	"github.com/EntySquare/solana-go-sdk/program/token"
+	"github.com/EntySquare/solana-go-sdk/rpc"
+	sdktypes "github.com/EntySquare/solana-go-sdk/types"
+	"github.com/EntySquare/solana/client"
+	"github.com/EntySquare/solana/tests/mock"
+	"github.com/EntySquare/solana/types"
+	"github.com/stretchr/testify/require"
+)
+
+// drainEvents returns the events emitted so far.
+func drainEvents(w *client.PaymentWatcher) []types.PaymentEvent {
+	var events []types.PaymentEvent
+	for {
+		select {
+		case event := <-w.Events():
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestPaymentWatcher_Poll(t *testing.T) {
+	ctx := context.Background()
+	fake := mock.NewRPCServer()
+	defer fake.Close()
+
+	payer := sdktypes.NewAccount().PublicKey
+	recipient := sdktypes.NewAccount().PublicKey
+	mint := sdktypes.NewAccount().PublicKey
+	source, _, _ := common.FindAssociatedTokenAddress(payer, mint)
+	recipientAta, _, _ := common.FindAssociatedTokenAddress(recipient, mint)
+	expect := func(id string, amount uint64, mint *common.PublicKey, deadline time.Time) types.ExpectedPayment {
+		return types.ExpectedPayment{
+			ID:             id,
+			PaymentRequest: types.PaymentRequest{Recipient: recipient, Amount: amount, SPLToken: mint, Reference: sdktypes.NewAccount().PublicKey},
+			Deadline:       deadline,
+		}
+	}
+	paySOL := func(payment types.ExpectedPayment, amount uint64) mock.RPCTransaction {
+		return historyTransaction(payer, nil, nil, withReference(
+			system.Transfer(system.TransferParam{From: payer, To: recipient, Amount: amount}), payment.Reference,
+		))
+	}
+
+	sol := expect("sol", 1000, nil, time.Now().Add(time.Hour))
+	tok := expect("token", 250, &mint, time.Time{})
+	late := expect("late", 1000, nil, time.Now().Add(-time.Minute))
+	slow := expect("slow", 500, nil, time.Time{})
+
+	c := client.New(client.SetSolanaEndpoint(fake.URL))
+	w := c.NewPaymentWatcher()
+	for _, payment := range []types.ExpectedPayment{sol, tok, late, slow} {
+		require.NoError(t, w.Watch(payment))
+	}
+	require.ErrorIs(t, w.Watch(sol), client.ErrPaymentAlreadyWatched)
+
+	require.NoError(t, w.Poll(ctx))
+	require.Equal(t, []types.PaymentEvent{{Type: types.PaymentEventExpired, Payment: late}}, drainEvents(w))
+
+	underpaid := fake.AddTransaction(paySOL(sol, 999))
+	paid := fake.AddTransaction(paySOL(sol, 1000))
+	duplicate := fake.AddTransaction(paySOL(sol, 1000))
+	overpaid := fake.AddTransaction(historyTransaction(payer, nil, nil, withReference(token.TransferChecked(token.TransferCheckedParam{
+		From: source, To: recipientAta, Mint: mint, Auth: payer, Amount: 300, Decimals: 2,
+	}), tok.Reference)))
+	failedTx := paySOL(slow, 500)
+	failedTx.Err = map[string]any{"InstructionError": []any{0, map[string]any{"Custom": 1}}}
+	failed := fake.AddTransaction(failedTx)
+	pending := fake.AddTransaction(paySOL(slow, 500))
+	fake.SetSignatureStatus(pending, mock.SignatureStatus{ConfirmationStatus: "confirmed"})
+
+	require.NoError(t, w.Poll(ctx))
+	events := drainEvents(w)
+	require.Len(t, events, 6)
+
+	require.Equal(t, types.PaymentEvent{Type: types.PaymentEventPending, Payment: slow, Signature: pending}, events[0])
+	require.Equal(t, types.PaymentEvent{Type: types.PaymentEventUnderpaid, Payment: sol, Signature: underpaid, Payer: payer, Amount: 999}, events[1])
+	require.Equal(t, types.PaymentEvent{Type: types.PaymentEventConfirmed, Payment: sol, Signature: paid, Payer: payer, Amount: 1000}, events[2])
+	// the second payment of the same poll is reported to refund
+	require.Equal(t, types.PaymentEventDuplicate, events[3].Type)
+	require.Equal(t, duplicate, events[3].Signature)
+	require.EqualValues(t, 1000, events[3].Amount)
+	require.ErrorIs(t, events[3].Err, client.ErrPaymentDuplicate)
+	require.Equal(t, types.PaymentEvent{Type: types.PaymentEventOverpaid, Payment: tok, Signature: overpaid, Payer: payer, Amount: 300}, events[4])
+	require.Equal(t, types.PaymentEventFailed, events[5].Type)
+	require.Equal(t, failed, events[5].Signature)
+	require.ErrorIs(t, events[5].Err, client.ErrPaymentFailed)
+	require.Equal(t, 1, w.Watched())
+
+	// the matched payments are never reported again
+	require.NoError(t, w.Poll(ctx))
+	require.Empty(t, drainEvents(w))
+	require.ErrorIs(t, w.Watch(sol), client.ErrPaymentAlreadyWatched)
+
+	fake.SetSignatureStatus(pending, mock.SignatureStatus{ConfirmationStatus: "finalized"})
+	require.NoError(t, w.Poll(ctx))
+	require.Equal(t, []types.PaymentEvent{
+		{Type: types.PaymentEventConfirmed, Payment: slow, Signature: pending, Payer: payer, Amount: 500},
+	}, drainEvents(w))
+	require.Zero(t, w.Watched())
+
+	// the confirmed commitment validates the transaction at once
+	fast := expect("fast", 700, nil, time.Time{})
+	require.NoError(t, w.Watch(fast))
+	signature := fake.AddTransaction(paySOL(fast, 700))
+	fake.SetSignatureStatus(signature, mock.SignatureStatus{ConfirmationStatus: "confirmed"})
+	require.NoError(t, w.Poll(client.WithCommitment(ctx, rpc.CommitmentConfirmed)))
+	require.Equal(t, []types.PaymentEvent{
+		{Type: types.PaymentEventConfirmed, Payment: fast, Signature: signature, Payer: payer, Amount: 700},
+	}, drainEvents(w))
+
+	require.ErrorIs(t, w.Watch(expect("", 0, nil, time.Time{})), client.ErrInvalidPaymentRequest)
+}
+
+func TestPaymentWatcher_Poll_FetchError(t *testing.T) {
+	ctx := context.Background()
+	fake := mock.NewRPCServer()
+	defer fake.Close()
+
+	payer := sdktypes.NewAccount().PublicKey
+	recipient := sdktypes.NewAccount().PublicKey
+	expect := func(id string, deadline time.Time) types.ExpectedPayment {
+		return types.ExpectedPayment{
+			ID:             id,
+			PaymentRequest: types.PaymentRequest{Recipient: recipient, Amount: 1000, Reference: sdktypes.NewAccount().PublicKey},
+			Deadline:       deadline,
+		}
+	}
+	pay := func(payment types.ExpectedPayment, amount uint64) string {
+		return fake.AddTransaction(historyTransaction(payer, nil, nil, withReference(
+			system.Transfer(system.TransferParam{From: payer, To: recipient, Amount: amount}), payment.Reference,
+		)))
+	}
+
+	flaky := expect("flaky", time.Now().Add(-time.Minute))
+	healthy := expect("healthy", time.Time{})
+	late := expect("late", time.Now().Add(-time.Minute))
+
+	c := client.New(client.SetSolanaEndpoint(fake.URL))
+	w := c.NewPaymentWatcher()
+	for _, payment := range []types.ExpectedPayment{flaky, healthy, late} {
+		require.NoError(t, w.Watch(payment))
+	}
+
+	underpaid := pay(flaky, 999)
+	paid := pay(flaky, 1000)
+	healthyPaid := pay(healthy, 1000)
+
+	// the oldest transaction of the flaky payment can not be fetched
+	getTransaction := fake.Builtin("getTransaction")
+	fake.Handle("getTransaction", func(params []json.RawMessage) (any, error) {
+		if strings.Contains(string(params[0]), underpaid) {
+			return nil, &mock.RPCError{Code: -32603, Message: "internal error"}
+		}
+		return getTransaction(params)
+	})
+
+	err := w.Poll(ctx)
+	require.ErrorIs(t, err, client.ErrWatchPayment)
+	require.ErrorContains(t, err, underpaid)
+	require.Equal(t, []types.PaymentEvent{
+		{Type: types.PaymentEventConfirmed, Payment: healthy, Signature: healthyPaid, Payer: payer, Amount: 1000},
+		{Type: types.PaymentEventExpired, Payment: late},
+	}, drainEvents(w))
+	require.Equal(t, 1, w.Watched())
+
+	// the skipped transactions are validated by the next poll, before the expiry
+	fake.Handle("getTransaction", getTransaction)
+	require.NoError(t, w.Poll(ctx))
+	require.Equal(t, []types.PaymentEvent{
+		{Type: types.PaymentEventUnderpaid, Payment: flaky, Signature: underpaid, Payer: payer, Amount: 999},
+		{Type: types.PaymentEventConfirmed, Payment: flaky, Signature: paid, Payer: payer, Amount: 1000},
+	}, drainEvents(w))
+	require.Zero(t, w.Watched())
+}
+
+func TestPaymentWatcher_Run(t *testing.T) {
+	fake := mock.NewRPCServer()
+	defer fake.Close()
+
+	payer := sdktypes.NewAccount().PublicKey
+	recipient := sdktypes.NewAccount().PublicKey
+	reference := sdktypes.NewAccount().PublicKey
+
+	c := client.New(client.SetSolanaEndpoint(fake.URL))
+	w := c.NewPaymentWatcher(client.WithPaymentPollInterval(10 * time.Millisecond))
+	require.NoError(t, w.Watch(types.ExpectedPayment{
+		PaymentRequest: types.PaymentRequest{Recipient: recipient, Amount: 1000, Reference: reference},
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.Run(ctx) }()
+
+	signature := fake.AddTransaction(historyTransaction(payer, nil, nil, withReference(
+		system.Transfer(system.TransferParam{From: payer, To: recipient, Amount: 1000}), reference,
+	)))
+	select {
+	case event := <-w.Events():
+		require.Equal(t, types.PaymentEventConfirmed, event.Type)
+		require.Equal(t, signature, event.Signature)
+		require.Equal(t, reference.ToBase58(), event.Payment.ID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no payment event")
+	}
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestPaymentWatcher_Poll_EventsBufferFull(t *testing.T) {
+	fake := mock.NewRPCServer()
+	defer fake.Close()
+
+	payer := sdktypes.NewAccount().PublicKey
+	recipient := sdktypes.NewAccount().PublicKey
+	expect := func(id string, deadline time.Time) types.ExpectedPayment {
+		return types.ExpectedPayment{
+			ID:             id,
+			PaymentRequest: types.PaymentRequest{Recipient: recipient, Amount: 1000, Reference: sdktypes.NewAccount().PublicKey},
+			Deadline:       deadline,
+		}
+	}
+	pay := func(payment types.ExpectedPayment) string {
+		return fake.AddTransaction(historyTransaction(payer, nil, nil, withReference(
+			system.Transfer(system.TransferParam{From: payer, To: recipient, Amount: 1000}), payment.Reference,
+		)))
+	}
+
+	first := expect("first", time.Time{})
+	second := expect("second", time.Time{})
+	late := expect("late", time.Now().Add(-time.Minute))
+
+	c := client.New(client.SetSolanaEndpoint(fake.URL))
+	w := c.NewPaymentWatcher(client.WithPaymentEventsBuffer(1))
+	for _, payment := range []types.ExpectedPayment{first, second, late} {
+		require.NoError(t, w.Watch(payment))
+	}
+	firstPaid := pay(first)
+	secondPaid := pay(second)
+
+	poll := func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		return w.Poll(ctx)
+	}
+
+	// the events that do not fit the buffer are emitted by the next polls, nothing is lost
+	require.ErrorIs(t, poll(), context.DeadlineExceeded)
+	require.Equal(t, []types.PaymentEvent{
+		{Type: types.PaymentEventConfirmed, Payment: first, Signature: firstPaid, Payer: payer, Amount: 1000},
+	}, drainEvents(w))
+	// the watch of the confirmed payment ends after the other events of the poll are delivered
+	require.Equal(t, 3, w.Watched())
+
+	require.ErrorIs(t, poll(), context.DeadlineExceeded)
+	require.Equal(t, []types.PaymentEvent{
+		{Type: types.PaymentEventConfirmed, Payment: second, Signature: secondPaid, Payer: payer, Amount: 1000},
+	}, drainEvents(w))
+	require.Equal(t, 1, w.Watched())
+
+	require.NoError(t, poll())
+	require.Equal(t, []types.PaymentEvent{{Type: types.PaymentEventExpired, Payment: late}}, drainEvents(w))
+	require.Zero(t, w.Watched())
+}
+
+func TestPaymentWatcher_Poll_PendingNotExpired(t *testing.T) {
+	ctx := context.Background()
+	fake := mock.NewRPCServer()
+	defer fake.Close()
+
+	payer := sdktypes.NewAccount().PublicKey
+	recipient := sdktypes.NewAccount().PublicKey
+	late := types.ExpectedPayment{
+		ID:             "late",
+		PaymentRequest: types.PaymentRequest{Recipient: recipient, Amount: 1000, Reference: sdktypes.NewAccount().PublicKey},
+		Deadline:       time.Now().Add(-time.Minute),
+	}
+
+	c := client.New(client.SetSolanaEndpoint(fake.URL))
+	w := c.NewPaymentWatcher(client.WithPaymentMatchedRetention(0))
+	require.NoError(t, w.Watch(late))
+
+	signature := fake.AddTransaction(historyTransaction(payer, nil, nil, withReference(
+		system.Transfer(system.TransferParam{From: payer, To: recipient, Amount: 1000}), late.Reference,
+	)))
+	fake.SetSignatureStatus(signature, mock.SignatureStatus{ConfirmationStatus: "confirmed"})
+
+	// the payment sent before the deadline is not expired while its transaction is pending
+	require.NoError(t, w.Poll(ctx))
+	require.Equal(t, []types.PaymentEvent{
+		{Type: types.PaymentEventPending, Payment: late, Signature: signature},
+	}, drainEvents(w))
+	require.Equal(t, 1, w.Watched())
+
+	fake.SetSignatureStatus(signature, mock.SignatureStatus{ConfirmationStatus: "finalized"})
+	require.NoError(t, w.Poll(ctx))
+	require.Equal(t, []types.PaymentEvent{
+		{Type: types.PaymentEventConfirmed, Payment: late, Signature: signature, Payer: payer, Amount: 1000},
+	}, drainEvents(w))
+	require.Zero(t, w.Watched())
+
+	// the reference of the finished payment is forgotten after the retention period
+	require.ErrorIs(t, w.Watch(late), client.ErrPaymentAlreadyWatched)
+	require.NoError(t, w.Poll(ctx))
+	require.NoError(t, w.Watch(late))
+}
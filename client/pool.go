@@ -0,0 +1,528 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// poolMaxCooldown is the max time a failing endpoint is skipped for.
+	poolMaxCooldown = 30 * time.Second
+	// poolHealthCheckTimeout is the timeout of a single endpoint health check.
+	poolHealthCheckTimeout = 5 * time.Second
+)
+
+type (
+	// RPCPool is the http transport which routes the JSON-RPC requests across several endpoints.
+	// Reads go to the healthiest endpoint and fail over to the next one on 429, 5xx and network errors.
+	// Transactions are sent to several endpoints at once.
+	RPCPool struct {
+		nodes               []*poolNode
+		transport           http.RoundTripper
+		healthCheckInterval time.Duration
+		maxSlotLag          uint64
+		sendFanout          int
+
+		mu     sync.RWMutex
+		ctx    context.Context // canceled by Close
+		cancel context.CancelFunc
+		done   chan struct{} // closed when the health checks stop
+	}
+
+	// PoolOption is the option for the RPC pool.
+	PoolOption func(*RPCPool)
+
+	// PoolNodeStatus is the health status of the pool endpoint.
+	PoolNodeStatus struct {
+		Endpoint string
+		Healthy  bool   // getHealth reports ok and the slot lag is within the limit
+		Slot     uint64 // last slot reported by the endpoint
+		SlotLag  uint64 // number of slots behind the most advanced endpoint
+		Failures int    // number of consecutive failed requests
+	}
+
+	poolNode struct {
+		endpoint      string
+		url           *url.URL
+		healthy       bool
+		slot          uint64
+		slotLag       uint64
+		failures      int
+		cooldownUntil time.Time
+	}
+
+	poolResponse struct {
+		node *poolNode
+		resp *http.Response
+		err  error
+	}
+)
+
+// WithHealthCheckInterval sets the interval of the endpoint health checks; default is 10 seconds.
+func WithHealthCheckInterval(interval time.Duration) PoolOption {
+	return func(p *RPCPool) {
+		p.healthCheckInterval = interval
+	}
+}
+
+// WithMaxSlotLag sets the number of slots an endpoint may be behind the others and still be healthy; default is 20.
+func WithMaxSlotLag(lag uint64) PoolOption {
+	return func(p *RPCPool) {
+		p.maxSlotLag = lag
+	}
+}
+
+// WithSendFanout sets the number of endpoints a transaction is sent to; default is 3.
+func WithSendFanout(n int) PoolOption {
+	return func(p *RPCPool) {
+		p.sendFanout = n
+	}
+}
+
+// WithPoolTransport sets the http transport used to reach the endpoints; default is http.DefaultTransport.
+func WithPoolTransport(transport http.RoundTripper) PoolOption {
+	return func(p *RPCPool) {
+		p.transport = transport
+	}
+}
+
+// NewRPCPool creates a new pool of the solana RPC endpoints and starts the health checks.
+// The pool must be closed to stop the health checks; Client.Close closes the pool set by SetSolanaEndpoints.
+// Returns the pool or an error if there is no endpoint or an endpoint is not a valid url.
+func NewRPCPool(endpoints []string, opts ...PoolOption) (*RPCPool, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("at least one endpoint is required")
+	}
+
+	p := &RPCPool{
+		transport:           http.DefaultTransport,
+		healthCheckInterval: 10 * time.Second,
+		maxSlotLag:          20,
+		sendFanout:          3,
+		done:                make(chan struct{}),
+	}
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+	for _, endpoint := range endpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid endpoint %s: %w", endpoint, err)
+		}
+		// the endpoints are healthy until the first check proves otherwise
+		p.nodes = append(p.nodes, &poolNode{endpoint: endpoint, url: u, healthy: true})
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	go p.runHealthChecks()
+
+	return p, nil
+}
+
+// Endpoints returns the pool endpoints.
+func (p *RPCPool) Endpoints() []string {
+	result := make([]string, 0, len(p.nodes))
+	for _, node := range p.nodes {
+		result = append(result, node.endpoint)
+	}
+	return result
+}
+
+// Status returns the health status of the endpoints, the healthiest first.
+func (p *RPCPool) Status() []PoolNodeStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	nodes := p.rankedLocked()
+	result := make([]PoolNodeStatus, 0, len(nodes))
+	for _, node := range nodes {
+		result = append(result, PoolNodeStatus{
+			Endpoint: node.endpoint,
+			Healthy:  node.healthy,
+			Slot:     node.slot,
+			SlotLag:  node.slotLag,
+			Failures: node.failures,
+		})
+	}
+	return result
+}
+
+// Close stops the health checks, canceling the running one, and waits for them to return.
+// It is safe to call Close several times.
+func (p *RPCPool) Close() {
+	p.cancel()
+	<-p.done
+}
+
+// RoundTrip sends the JSON-RPC request to the pool endpoints.
+func (p *RPCPool) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if sendsTransaction(body) {
+		return p.fanout(req, body)
+	}
+	return p.failover(req, body)
+}
+
+// sendsTransaction returns true if the JSON-RPC request, or one of the requests of a batch, is sendTransaction.
+func sendsTransaction(body []byte) bool {
+	type request struct {
+		Method string `json:"method"`
+	}
+
+	var requests []request
+	if err := json.Unmarshal(body, &requests); err != nil {
+		var single request
+		json.Unmarshal(body, &single)
+		requests = []request{single}
+	}
+	for _, r := range requests {
+		if r.Method == "sendTransaction" {
+			return true
+		}
+	}
+	return false
+}
+
+// failover sends the request to the endpoints in the health order until one of them answers.
+func (p *RPCPool) failover(req *http.Request, body []byte) (*http.Response, error) {
+	tracker := rpcCallTrackerFromContext(req.Context())
+
+	var lastResp *http.Response
+	var lastErr error
+	for i, node := range p.ranked() {
+		if lastResp != nil {
+			lastResp.Body.Close()
+		}
+		if i > 0 {
+			tracker.addRetry()
+		}
+		tracker.setEndpoint(node.endpoint)
+
+		resp, err := p.send(req, node, body)
+		if err == nil && !retryableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+		if req.Context().Err() != nil {
+			if resp != nil {
+				resp.Body.Close()
+			}
+			return nil, req.Context().Err()
+		}
+		lastResp, lastErr = resp, err
+	}
+
+	if lastResp != nil {
+		return lastResp, nil
+	}
+	return nil, lastErr
+}
+
+// fanout sends the request to several endpoints at once.
+// Returns the first successful response, or the most useful failed one if none succeeded.
+func (p *RPCPool) fanout(req *http.Request, body []byte) (*http.Response, error) {
+	nodes := p.ranked()
+	if p.sendFanout > 0 && len(nodes) > p.sendFanout {
+		nodes = nodes[:p.sendFanout]
+	}
+
+	responses := make(chan poolResponse, len(nodes))
+	for _, node := range nodes {
+		go func(node *poolNode) {
+			resp, err := p.send(req, node, body)
+			if err == nil {
+				resp, err = bufferResponse(resp)
+			}
+			responses <- poolResponse{node: node, resp: resp, err: err}
+		}(node)
+	}
+
+	tracker := rpcCallTrackerFromContext(req.Context())
+
+	var fallback *poolResponse
+	for i := 0; i < len(nodes); i++ {
+		r := <-responses
+		if r.err == nil && !retryableStatus(r.resp.StatusCode) && !rpcErrorResponse(r.resp) {
+			go drainResponses(responses, len(nodes)-i-1)
+			if fallback != nil && fallback.resp != nil {
+				fallback.resp.Body.Close()
+			}
+			tracker.setEndpoint(r.node.endpoint)
+			return r.resp, nil
+		}
+
+		if fallback == nil || r.rank() > fallback.rank() {
+			if fallback != nil && fallback.resp != nil {
+				fallback.resp.Body.Close()
+			}
+			r := r
+			fallback = &r
+		} else if r.resp != nil {
+			r.resp.Body.Close()
+		}
+	}
+
+	tracker.setEndpoint(fallback.node.endpoint)
+	return fallback.resp, fallback.err
+}
+
+// send sends the request to the endpoint and records the result.
+func (p *RPCPool) send(req *http.Request, node *poolNode, body []byte) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	r.URL = node.url
+	r.Host = node.url.Host
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.ContentLength = int64(len(body))
+
+	resp, err := p.transport.RoundTrip(r)
+	switch {
+	case err != nil && req.Context().Err() != nil:
+		// the caller gave up, it says nothing about the endpoint
+	case err != nil || retryableStatus(resp.StatusCode):
+		p.markFailure(node)
+	default:
+		p.markSuccess(node)
+	}
+
+	return resp, err
+}
+
+// markFailure puts the endpoint on an exponentially growing cooldown.
+func (p *RPCPool) markFailure(node *poolNode) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	node.failures++
+	cooldown := time.Second << (node.failures - 1)
+	if cooldown > poolMaxCooldown || cooldown <= 0 {
+		cooldown = poolMaxCooldown
+	}
+	node.cooldownUntil = time.Now().Add(cooldown)
+}
+
+// markSuccess resets the failures of the endpoint.
+func (p *RPCPool) markSuccess(node *poolNode) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	node.failures = 0
+	node.cooldownUntil = time.Time{}
+}
+
+// ranked returns the endpoints, the healthiest first.
+func (p *RPCPool) ranked() []*poolNode {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.rankedLocked()
+}
+
+// rankedLocked returns the endpoints, the healthiest first.
+// Must be called with p.mu locked.
+func (p *RPCPool) rankedLocked() []*poolNode {
+	now := time.Now()
+	nodes := append([]*poolNode(nil), p.nodes...)
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+		if coolA, coolB := now.Before(a.cooldownUntil), now.Before(b.cooldownUntil); coolA != coolB {
+			return coolB
+		}
+		if a.healthy != b.healthy {
+			return a.healthy
+		}
+		if a.slotLag != b.slotLag {
+			return a.slotLag < b.slotLag
+		}
+		return a.failures < b.failures
+	})
+
+	return nodes
+}
+
+// runHealthChecks checks the endpoints on every interval until the pool is closed.
+func (p *RPCPool) runHealthChecks() {
+	defer close(p.done)
+
+	p.checkHealth()
+
+	tick := time.NewTicker(p.healthCheckInterval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-tick.C:
+			p.checkHealth()
+		}
+	}
+}
+
+// checkHealth queries getHealth and getSlot of all the endpoints and updates their slot lag.
+func (p *RPCPool) checkHealth() {
+	type result struct {
+		healthy bool
+		slot    uint64
+	}
+
+	results := make([]result, len(p.nodes))
+	var wg sync.WaitGroup
+	for i, node := range p.nodes {
+		wg.Add(1)
+		go func(i int, node *poolNode) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(p.ctx, poolHealthCheckTimeout)
+			defer cancel()
+
+			var health string
+			if err := p.call(ctx, node, "getHealth", &health); err != nil || health != "ok" {
+				return
+			}
+			var slot uint64
+			if err := p.call(ctx, node, "getSlot", &slot); err != nil {
+				return
+			}
+			results[i] = result{healthy: true, slot: slot}
+		}(i, node)
+	}
+	wg.Wait()
+	// the checks canceled by Close say nothing about the endpoints
+	if p.ctx.Err() != nil {
+		return
+	}
+
+	var maxSlot uint64
+	for _, r := range results {
+		if r.slot > maxSlot {
+			maxSlot = r.slot
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, node := range p.nodes {
+		node.slot = results[i].slot
+		node.slotLag = maxSlot - results[i].slot
+		node.healthy = results[i].healthy && node.slotLag <= p.maxSlotLag
+	}
+}
+
+// call calls the JSON-RPC method without params on the endpoint.
+func (p *RPCPool) call(ctx context.Context, node *poolNode, method string, result any) error {
+	body, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "method": method})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, node.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.transport.RoundTrip(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: status code %d", method, resp.StatusCode)
+	}
+
+	var rpcResp struct {
+		Result json.RawMessage `json:"result"`
+		Error  json.RawMessage `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return err
+	}
+	if len(rpcResp.Error) > 0 && string(rpcResp.Error) != "null" {
+		return fmt.Errorf("%s: %s", method, rpcResp.Error)
+	}
+
+	return json.Unmarshal(rpcResp.Result, result)
+}
+
+// rank returns how useful the failed response is to the caller.
+// The JSON-RPC errors, e.g. the preflight failures, are preferred over the endpoint failures.
+func (r poolResponse) rank() int {
+	switch {
+	case r.err != nil:
+		return 0
+	case retryableStatus(r.resp.StatusCode):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// retryableStatus returns true if the status code means the request should go to another endpoint.
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// rpcErrorResponse returns true if the buffered response, or one of the responses of a batch, carries a JSON-RPC error.
+func rpcErrorResponse(resp *http.Response) bool {
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	type response struct {
+		Error json.RawMessage `json:"error"`
+	}
+
+	var responses []response
+	if err := json.Unmarshal(body, &responses); err != nil {
+		var single response
+		if err := json.Unmarshal(body, &single); err != nil {
+			return true
+		}
+		responses = []response{single}
+	}
+	for _, r := range responses {
+		if len(r.Error) > 0 && string(r.Error) != "null" {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferResponse reads the response body into memory, so the connection is released.
+func bufferResponse(resp *http.Response) (*http.Response, error) {
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	return resp, nil
+}
+
+// drainResponses closes the remaining responses of the fanout.
+func drainResponses(responses <-chan poolResponse, n int) {
+	for i := 0; i < n; i++ {
+		if r := <-responses; r.resp != nil {
+			r.resp.Body.Close()
+		}
+	}
+}
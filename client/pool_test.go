@@ -0,0 +1,218 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/EntySquare/solana/client"
+	"github.com/stretchr/testify/require"
+)
+
+// poolNodeServer is a json rpc server with a fixed slot and a fixed answer to the other methods.
+type poolNodeServer struct {
+	*httptest.Server
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func newPoolNodeServer(t *testing.T, slot uint64, status int, result any) *poolNodeServer {
+	type request struct {
+		ID     uint64 `json:"id"`
+		Method string `json:"method"`
+	}
+
+	s := &poolNodeServer{calls: make(map[string]int)}
+	handle := func(req request) (int, map[string]any) {
+		s.mu.Lock()
+		s.calls[req.Method]++
+		s.mu.Unlock()
+
+		resp := map[string]any{"jsonrpc": "2.0", "id": req.ID}
+		switch req.Method {
+		case "getHealth":
+			resp["result"] = "ok"
+		case "getSlot":
+			resp["result"] = slot
+		default:
+			if status != http.StatusOK {
+				return status, nil
+			}
+			if err, ok := result.(error); ok {
+				resp["error"] = map[string]any{"code": -32002, "message": err.Error()}
+			} else {
+				resp["result"] = result
+			}
+		}
+		return http.StatusOK, resp
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var raw json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if raw[0] == '[' {
+			var batch []request
+			if err := json.Unmarshal(raw, &batch); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			responses := make([]any, 0, len(batch))
+			for _, req := range batch {
+				code, resp := handle(req)
+				if code != http.StatusOK {
+					w.WriteHeader(code)
+					return
+				}
+				responses = append(responses, resp)
+			}
+			json.NewEncoder(w).Encode(responses)
+			return
+		}
+
+		var req request
+		if err := json.Unmarshal(raw, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		code, resp := handle(req)
+		if code != http.StatusOK {
+			w.WriteHeader(code)
+			return
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *poolNodeServer) count(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method]
+}
+
+func TestRPCPool_Failover(t *testing.T) {
+	throttled := newPoolNodeServer(t, 100, http.StatusTooManyRequests, nil)
+	lagging := newPoolNodeServer(t, 50, http.StatusOK, 1)
+	healthy := newPoolNodeServer(t, 100, http.StatusOK, 2)
+
+	c := client.New(client.SetSolanaEndpoints(
+		[]string{throttled.URL, lagging.URL, healthy.URL},
+		client.WithHealthCheckInterval(time.Hour),
+	))
+	defer c.Close()
+
+	require.Eventually(t, func() bool {
+		for _, status := range c.RPCPool().Status() {
+			if status.Endpoint == lagging.URL {
+				return !status.Healthy && status.SlotLag == 50
+			}
+		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+
+	height, err := c.GetBlockHeight(context.Background())
+	require.NoError(t, err)
+	require.EqualValues(t, 2, height)
+	require.Equal(t, 1, throttled.count("getBlockHeight"))
+	require.Equal(t, 0, lagging.count("getBlockHeight"))
+
+	// the throttled endpoint is skipped while it cools down
+	status := c.RPCPool().Status()
+	require.Equal(t, healthy.URL, status[0].Endpoint)
+	require.Equal(t, throttled.URL, status[2].Endpoint)
+	require.Equal(t, 1, status[2].Failures)
+
+	_, err = c.GetBlockHeight(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, throttled.count("getBlockHeight"))
+	require.Equal(t, 2, healthy.count("getBlockHeight"))
+}
+
+func TestRPCPool_SendFanout(t *testing.T) {
+	failing := newPoolNodeServer(t, 100, http.StatusInternalServerError, nil)
+	rejecting := newPoolNodeServer(t, 100, http.StatusOK, errTestNodeBehind)
+	accepting := newPoolNodeServer(t, 100, http.StatusOK, testTxSignature)
+
+	c := client.New(client.SetSolanaEndpoints(
+		[]string{failing.URL, rejecting.URL, accepting.URL},
+		client.WithHealthCheckInterval(time.Hour),
+	))
+	defer c.Close()
+
+	body, err := c.Solana().RpcClient.Call(context.Background(), "sendTransaction", "tx")
+	require.NoError(t, err)
+
+	var resp struct {
+		Result string `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(body, &resp))
+	require.Equal(t, testTxSignature, resp.Result)
+
+	for _, server := range []*poolNodeServer{failing, rejecting, accepting} {
+		require.Equal(t, 1, server.count("sendTransaction"))
+	}
+}
+
+func TestRPCPool_SendFanoutBatch(t *testing.T) {
+	failing := newPoolNodeServer(t, 100, http.StatusInternalServerError, nil)
+	rejecting := newPoolNodeServer(t, 100, http.StatusOK, errTestNodeBehind)
+	accepting := newPoolNodeServer(t, 100, http.StatusOK, testTxSignature)
+
+	pool, err := client.NewRPCPool(
+		[]string{failing.URL, rejecting.URL, accepting.URL},
+		client.WithHealthCheckInterval(time.Hour),
+	)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	// the transactions sent in a batch are fanned out too
+	body := `[{"jsonrpc":"2.0","id":0,"method":"sendTransaction","params":["tx1"]},` +
+		`{"jsonrpc":"2.0","id":1,"method":"sendTransaction","params":["tx2"]}]`
+	req, err := http.NewRequest(http.MethodPost, failing.URL, strings.NewReader(body))
+	require.NoError(t, err)
+	resp, err := pool.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var responses []struct {
+		Result string `json:"result"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&responses))
+	require.Len(t, responses, 2)
+	require.Equal(t, testTxSignature, responses[0].Result)
+
+	// the failing node answers 500 on the first transaction of the batch
+	require.Equal(t, 1, failing.count("sendTransaction"))
+	require.Equal(t, 2, rejecting.count("sendTransaction"))
+	require.Equal(t, 2, accepting.count("sendTransaction"))
+}
+
+func TestRPCPool_Close(t *testing.T) {
+	node := newPoolNodeServer(t, 100, http.StatusOK, nil)
+
+	pool, err := client.NewRPCPool([]string{node.URL}, client.WithHealthCheckInterval(10*time.Millisecond))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return node.count("getHealth") >= 2 }, time.Second, 5*time.Millisecond)
+
+	// no health check runs after Close returns
+	pool.Close()
+	checks := node.count("getHealth")
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, checks, node.count("getHealth"))
+	pool.Close()
+}
+
+var errTestNodeBehind = errors.New("node is behind")
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"sort"
+
+	"github.com/EntySquare/solana/types"
+	"github.com/EntySquare/solana/utils"
+)
+
+// PrioritizationFee is the prioritization fee paid in a recent slot.
+type PrioritizationFee struct {
+	Slot              uint64 `json:"slot"`
+	PrioritizationFee uint64 `json:"prioritizationFee"` // in micro-lamports per compute unit
+}
+
+// GetRecentPrioritizationFees returns the prioritization fees paid in the recent slots.
+// base58Addrs are the writable accounts the fees should be scoped to; if empty, the global fees are returned.
+// Returns the list of fees or an error.
+func (c *Client) GetRecentPrioritizationFees(ctx context.Context, base58Addrs ...string) ([]PrioritizationFee, error) {
+	if len(base58Addrs) > types.MaxPrioritizationFeeKeys {
+		base58Addrs = base58Addrs[:types.MaxPrioritizationFeeKeys]
+	}
+
+	params := []any{}
+	if len(base58Addrs) > 0 {
+		params = append(params, base58Addrs)
+	}
+
+	fees, err := callRPC[[]PrioritizationFee](ctx, c, "getRecentPrioritizationFees", params...)
+	if err != nil {
+		return nil, utils.StackErrors(ErrGetRecentPrioritizationFees, err)
+	}
+
+	return fees, nil
+}
+
+// EstimateComputeUnitPrice estimates the compute unit price from the recent prioritization fees
+// paid for the given writable accounts.
+// Returns the price in micro-lamports per compute unit or an error.
+func (c *Client) EstimateComputeUnitPrice(ctx context.Context, base58Addrs ...string) (uint64, error) {
+	fees, err := c.GetRecentPrioritizationFees(ctx, base58Addrs...)
+	if err != nil {
+		return 0, utils.StackErrors(ErrEstimateComputeUnitPrice, err)
+	}
+
+	return feePercentile(fees, types.PriorityFeePercentile), nil
+}
+
+// feePercentile returns the given percentile of the prioritization fees.
+func feePercentile(fees []PrioritizationFee, percentile uint8) uint64 {
+	if len(fees) == 0 {
+		return 0
+	}
+	if percentile > 100 {
+		percentile = 100
+	}
+
+	values := make([]uint64, 0, len(fees))
+	for _, fee := range fees {
+		values = append(values, fee.PrioritizationFee)
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+	idx := (len(values)*int(percentile) + 99) / 100
+	if idx > 0 {
+		idx--
+	}
+
+	return values[idx]
+}
@@ -0,0 +1,78 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/EntySquare/solana/client"
+	"github.com/EntySquare/solana/tests/mock"
+	"github.com/EntySquare/solana/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateComputeUnitPrice(t *testing.T) {
+	// the estimate is the 75th percentile of the recent fees by the nearest rank
+	tests := []struct {
+		name string
+		fees []uint64
+		want uint64
+	}{
+		{name: "no fees", fees: nil, want: 0},
+		{name: "single fee", fees: []uint64{5}, want: 5},
+		{name: "exact rank", fees: []uint64{1, 2, 3, 4}, want: 3},
+		{name: "rounded up rank", fees: []uint64{40, 10, 30, 20, 50}, want: 40},
+		{name: "mostly free", fees: []uint64{0, 0, 0, 100}, want: 0},
+		{name: "eight slots", fees: []uint64{8, 7, 6, 5, 4, 3, 2, 1}, want: 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := mock.NewRPCServer()
+			defer fake.Close()
+
+			fees := make([]client.PrioritizationFee, len(tt.fees))
+			for i, fee := range tt.fees {
+				fees[i] = client.PrioritizationFee{Slot: uint64(100 + i), PrioritizationFee: fee}
+			}
+			fake.SetResult("getRecentPrioritizationFees", fees)
+
+			c := client.New(client.SetSolanaEndpoint(fake.URL))
+			price, err := c.EstimateComputeUnitPrice(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, tt.want, price)
+		})
+	}
+}
+
+func TestGetRecentPrioritizationFees(t *testing.T) {
+	ctx := context.Background()
+	fake := mock.NewRPCServer()
+	defer fake.Close()
+
+	fake.SetResult("getRecentPrioritizationFees", []client.PrioritizationFee{{Slot: 1, PrioritizationFee: 10}})
+	c := client.New(client.SetSolanaEndpoint(fake.URL))
+
+	// the global fees are requested without params
+	fees, err := c.GetRecentPrioritizationFees(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []client.PrioritizationFee{{Slot: 1, PrioritizationFee: 10}}, fees)
+	require.Empty(t, fake.RequestsOf("getRecentPrioritizationFees")[0].Params)
+
+	// the accounts are capped to the max accepted by the node
+	addrs := make([]string, types.MaxPrioritizationFeeKeys+5)
+	for i := range addrs {
+		addrs[i] = fmt.Sprintf("account-%d", i)
+	}
+	_, err = c.GetRecentPrioritizationFees(ctx, addrs...)
+	require.NoError(t, err)
+	var sent []string
+	require.NoError(t, json.Unmarshal(fake.RequestsOf("getRecentPrioritizationFees")[1].Params[0], &sent))
+	require.Equal(t, addrs[:types.MaxPrioritizationFeeKeys], sent)
+
+	fake.SetError("getRecentPrioritizationFees", -32603, "internal error")
+	_, err = c.GetRecentPrioritizationFees(ctx)
+	require.ErrorIs(t, err, client.ErrGetRecentPrioritizationFees)
+	_, err = c.EstimateComputeUnitPrice(ctx)
+	require.ErrorIs(t, err, client.ErrEstimateComputeUnitPrice)
+}
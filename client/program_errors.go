@@ -0,0 +1,218 @@
+package client
+
+import (
+	"errors"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	commonx "github.com/EntySquare/solana/common"
+)
+
+// Transaction errors
+var (
+	ErrTransactionFailed         = errors.New("transaction failed")
+	ErrBlockhashNotFound         = errors.New("blockhash not found")
+	ErrAlreadyProcessed          = errors.New("transaction has already been processed")
+	ErrAccountNotFound           = errors.New("attempt to debit an account but found no record of a prior credit")
+	ErrInsufficientFundsForFee   = errors.New("insufficient funds for fee")
+	ErrInsufficientFundsForRent  = errors.New("insufficient funds for rent")
+	ErrProgramAccountNotFound    = errors.New("attempt to load a program that does not exist")
+	ErrInvalidAccountForFee      = errors.New("account can not be used to pay transaction fees")
+	ErrSignatureFailure          = errors.New("transaction did not pass signature verification")
+	ErrAddressLookupTableMissing = errors.New("transaction loads an address table account that does not exist")
+)
+
+// Instruction errors
+var (
+	ErrInstructionFailed        = errors.New("instruction failed")
+	ErrCustomProgramError       = errors.New("custom program error")
+	ErrInstructionInsufficient  = errors.New("insufficient funds for instruction")
+	ErrInvalidAccountData       = errors.New("invalid account data for instruction")
+	ErrAccountAlreadyInit       = errors.New("instruction requires an uninitialized account")
+	ErrUninitializedAccount     = errors.New("instruction requires an initialized account")
+	ErrMissingRequiredSignature = errors.New("missing required signature for instruction")
+	ErrIncorrectProgramID       = errors.New("incorrect program id for instruction")
+	ErrComputationalBudget      = errors.New("computational budget exceeded")
+)
+
+// System program errors
+var (
+	ErrSystemAccountAlreadyInUse           = errors.New("system: an account with the same address already exists")
+	ErrSystemResultWithNegativeLamports    = errors.New("system: account does not have enough SOL to perform the operation")
+	ErrSystemInvalidProgramID              = errors.New("system: cannot assign account to this program id")
+	ErrSystemInvalidAccountDataLength      = errors.New("system: cannot allocate account data of this length")
+	ErrSystemMaxSeedLengthExceeded         = errors.New("system: length of requested seed is too long")
+	ErrSystemAddressWithSeedMismatch       = errors.New("system: provided address does not match addressed derived from seed")
+	ErrSystemNonceNoRecentBlockhashes      = errors.New("system: advancing stored nonce requires a populated recent blockhashes sysvar")
+	ErrSystemNonceBlockhashNotExpired      = errors.New("system: stored nonce is still in recent blockhashes")
+	ErrSystemNonceUnexpectedBlockhashValue = errors.New("system: specified nonce does not match stored nonce")
+)
+
+// SPL Token program errors
+var (
+	ErrTokenNotRentExempt                  = errors.New("token: lamport balance below rent-exempt threshold")
+	ErrTokenInsufficientFunds              = errors.New("token: insufficient funds")
+	ErrTokenInvalidMint                    = errors.New("token: invalid mint")
+	ErrTokenMintMismatch                   = errors.New("token: account not associated with this mint")
+	ErrTokenOwnerMismatch                  = errors.New("token: owner does not match")
+	ErrTokenFixedSupply                    = errors.New("token: fixed supply")
+	ErrTokenAlreadyInUse                   = errors.New("token: already in use")
+	ErrTokenInvalidNumberOfProvidedSigners = errors.New("token: invalid number of provided signers")
+	ErrTokenInvalidNumberOfRequiredSigners = errors.New("token: invalid number of required signers")
+	ErrTokenUninitializedState             = errors.New("token: state is uninitialized")
+	ErrTokenNativeNotSupported             = errors.New("token: instruction does not support native tokens")
+	ErrTokenNonNativeHasBalance            = errors.New("token: non-native account can only be closed if its balance is zero")
+	ErrTokenInvalidInstruction             = errors.New("token: invalid instruction")
+	ErrTokenInvalidState                   = errors.New("token: state is invalid for requested operation")
+	ErrTokenOverflow                       = errors.New("token: operation overflowed")
+	ErrTokenAuthorityTypeNotSupported      = errors.New("token: account does not support specified authority type")
+	ErrTokenMintCannotFreeze               = errors.New("token: this token mint cannot freeze accounts")
+	ErrTokenAccountFrozen                  = errors.New("token: account is frozen")
+	ErrTokenMintDecimalsMismatch           = errors.New("token: the provided decimals value different from the mint decimals")
+	ErrTokenNonNativeNotSupported          = errors.New("token: instruction does not support non-native tokens")
+)
+
+// Associated Token Account program errors
+var (
+	ErrAssociatedTokenInvalidOwner = errors.New("associated token: owner does not match address derivation")
+)
+
+// Metaplex Token Metadata program errors
+var (
+	ErrMetadataUpdateAuthorityIncorrect            = errors.New("metadata: update authority given does not match")
+	ErrMetadataUpdateAuthorityIsNotSigner          = errors.New("metadata: update authority needs to be signer to update metadata")
+	ErrMetadataNotMintAuthority                    = errors.New("metadata: you must be the mint authority and signer on this transaction")
+	ErrMetadataInvalidMintAuthority                = errors.New("metadata: mint authority provided does not match the authority on the mint")
+	ErrMetadataNameTooLong                         = errors.New("metadata: name too long")
+	ErrMetadataSymbolTooLong                       = errors.New("metadata: symbol too long")
+	ErrMetadataURITooLong                          = errors.New("metadata: uri too long")
+	ErrMetadataMintMismatch                        = errors.New("metadata: mint given does not match mint on metadata")
+	ErrMetadataEditionsMustHaveExactlyOneToken     = errors.New("metadata: editions must have exactly one token")
+	ErrMetadataMaxEditionsMintedAlready            = errors.New("metadata: maximum editions printed already")
+	ErrMetadataEditionAlreadyMinted                = errors.New("metadata: edition already minted")
+	ErrMetadataDerivedKeyInvalid                   = errors.New("metadata: derived key invalid")
+	ErrMetadataNotEnoughTokens                     = errors.New("metadata: not enough tokens to mint a limited edition")
+	ErrMetadataCreatorsTooLong                     = errors.New("metadata: creators list too long")
+	ErrMetadataCreatorsMustBeAtLeastOne            = errors.New("metadata: creators must be at least one if set")
+	ErrMetadataMustBeOneOfCreators                 = errors.New("metadata: if using a creators array, you must be one of the creators listed")
+	ErrMetadataCreatorNotFound                     = errors.New("metadata: creator not found in metadata creators list")
+	ErrMetadataInvalidBasisPoints                  = errors.New("metadata: basis points cannot be more than 10000")
+	ErrMetadataShareTotalMustBe100                 = errors.New("metadata: share total must equal 100 for creator array")
+	ErrMetadataCannotVerifyAnotherCreator          = errors.New("metadata: you cannot unilaterally verify another creator, they must sign")
+	ErrMetadataIncorrectOwner                      = errors.New("metadata: incorrect account owner")
+	ErrMetadataDataIsImmutable                     = errors.New("metadata: data is immutable")
+	ErrMetadataDuplicateCreatorAddress             = errors.New("metadata: no duplicate creator addresses")
+	ErrMetadataIsMutableCanOnlyBeFlippedToFalse    = errors.New("metadata: is mutable can only be flipped to false")
+	ErrMetadataCollectionNotFound                  = errors.New("metadata: collection not found")
+	ErrMetadataInvalidCollectionUpdateAuthority    = errors.New("metadata: collection update authority is invalid")
+	ErrMetadataCollectionMustBeUniqueMasterEdition = errors.New("metadata: collection must be a unique master edition v2")
+	ErrMetadataUnusable                            = errors.New("metadata: this token has no uses")
+	ErrMetadataNotEnoughUses                       = errors.New("metadata: there are not enough uses left on this token")
+	ErrMetadataCollectionAuthorityDoesNotExist     = errors.New("metadata: collection authority does not exist")
+)
+
+// transactionErrors maps the transaction error names to the sentinel errors.
+var transactionErrors = map[string]error{
+	"BlockhashNotFound":          ErrBlockhashNotFound,
+	"AlreadyProcessed":           ErrAlreadyProcessed,
+	"AccountNotFound":            ErrAccountNotFound,
+	"InsufficientFundsForFee":    ErrInsufficientFundsForFee,
+	"InsufficientFundsForRent":   ErrInsufficientFundsForRent,
+	"ProgramAccountNotFound":     ErrProgramAccountNotFound,
+	"InvalidAccountForFee":       ErrInvalidAccountForFee,
+	"SignatureFailure":           ErrSignatureFailure,
+	"AddressLookupTableNotFound": ErrAddressLookupTableMissing,
+}
+
+// instructionErrors maps the builtin instruction error names to the sentinel errors.
+var instructionErrors = map[string]error{
+	"InsufficientFunds":           ErrInstructionInsufficient,
+	"InvalidAccountData":          ErrInvalidAccountData,
+	"AccountAlreadyInitialized":   ErrAccountAlreadyInit,
+	"UninitializedAccount":        ErrUninitializedAccount,
+	"MissingRequiredSignature":    ErrMissingRequiredSignature,
+	"IncorrectProgramId":          ErrIncorrectProgramID,
+	"ComputationalBudgetExceeded": ErrComputationalBudget,
+}
+
+// tokenProgramErrors maps the custom error codes of the SPL Token program to the sentinel errors.
+// The Token-2022 program keeps the same codes.
+var tokenProgramErrors = map[uint32]error{
+	0:  ErrTokenNotRentExempt,
+	1:  ErrTokenInsufficientFunds,
+	2:  ErrTokenInvalidMint,
+	3:  ErrTokenMintMismatch,
+	4:  ErrTokenOwnerMismatch,
+	5:  ErrTokenFixedSupply,
+	6:  ErrTokenAlreadyInUse,
+	7:  ErrTokenInvalidNumberOfProvidedSigners,
+	8:  ErrTokenInvalidNumberOfRequiredSigners,
+	9:  ErrTokenUninitializedState,
+	10: ErrTokenNativeNotSupported,
+	11: ErrTokenNonNativeHasBalance,
+	12: ErrTokenInvalidInstruction,
+	13: ErrTokenInvalidState,
+	14: ErrTokenOverflow,
+	15: ErrTokenAuthorityTypeNotSupported,
+	16: ErrTokenMintCannotFreeze,
+	17: ErrTokenAccountFrozen,
+	18: ErrTokenMintDecimalsMismatch,
+	19: ErrTokenNonNativeNotSupported,
+}
+
+// programErrors maps the custom error codes of the known programs to the sentinel errors.
+var programErrors = map[common.PublicKey]map[uint32]error{
+	common.SystemProgramID: {
+		0: ErrSystemAccountAlreadyInUse,
+		1: ErrSystemResultWithNegativeLamports,
+		2: ErrSystemInvalidProgramID,
+		3: ErrSystemInvalidAccountDataLength,
+		4: ErrSystemMaxSeedLengthExceeded,
+		5: ErrSystemAddressWithSeedMismatch,
+		6: ErrSystemNonceNoRecentBlockhashes,
+		7: ErrSystemNonceBlockhashNotExpired,
+		8: ErrSystemNonceUnexpectedBlockhashValue,
+	},
+	common.TokenProgramID:      tokenProgramErrors,
+	commonx.Token2022ProgramID: tokenProgramErrors,
+	common.SPLAssociatedTokenAccountProgramID: {
+		0: ErrAssociatedTokenInvalidOwner,
+	},
+	common.MetaplexTokenMetaProgramID: {
+		7:  ErrMetadataUpdateAuthorityIncorrect,
+		8:  ErrMetadataUpdateAuthorityIsNotSigner,
+		9:  ErrMetadataNotMintAuthority,
+		10: ErrMetadataInvalidMintAuthority,
+		11: ErrMetadataNameTooLong,
+		12: ErrMetadataSymbolTooLong,
+		13: ErrMetadataURITooLong,
+		15: ErrMetadataMintMismatch,
+		16: ErrMetadataEditionsMustHaveExactlyOneToken,
+		17: ErrMetadataMaxEditionsMintedAlready,
+		21: ErrMetadataEditionAlreadyMinted,
+		27: ErrMetadataDerivedKeyInvalid,
+		32: ErrMetadataNotEnoughTokens,
+		36: ErrMetadataCreatorsTooLong,
+		37: ErrMetadataCreatorsMustBeAtLeastOne,
+		38: ErrMetadataMustBeOneOfCreators,
+		40: ErrMetadataCreatorNotFound,
+		41: ErrMetadataInvalidBasisPoints,
+		45: ErrMetadataShareTotalMustBe100,
+		54: ErrMetadataCannotVerifyAnotherCreator,
+		57: ErrMetadataIncorrectOwner,
+		59: ErrMetadataDataIsImmutable,
+		60: ErrMetadataDuplicateCreatorAddress,
+		73: ErrMetadataIsMutableCanOnlyBeFlippedToFalse,
+		80: ErrMetadataCollectionNotFound,
+		81: ErrMetadataInvalidCollectionUpdateAuthority,
+		82: ErrMetadataCollectionMustBeUniqueMasterEdition,
+		85: ErrMetadataUnusable,
+		86: ErrMetadataNotEnoughUses,
+		88: ErrMetadataCollectionAuthorityDoesNotExist,
+	},
+}
+
+// ProgramError returns the sentinel error for the custom error code of the given program.
+// Returns nil if the program or the code is unknown.
+func ProgramError(programID common.PublicKey, code uint32) error {
+	return programErrors[programID][code]
+}
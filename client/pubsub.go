@@ -0,0 +1,565 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/EntySquare/solana/utils"
+	"github.com/gorilla/websocket"
+)
+
+type (
+	// PubSub is the client of the solana PubSub websocket API.
+	// It reconnects automatically and resubscribes all active subscriptions after a reconnect.
+	// The connection is pinged, so a half-open connection is detected by the missing pongs and reconnected.
+	PubSub struct {
+		endpoint          string
+		dialer            *websocket.Dialer
+		reconnectDelay    time.Duration
+		maxReconnectDelay time.Duration
+		pingInterval      time.Duration
+
+		writeMu sync.Mutex
+		mu      sync.Mutex
+		conn    *websocket.Conn
+		nextID  uint64
+		pending map[uint64]func(result json.RawMessage, err error) // request id -> response handler
+		subs    map[uint64]*subscription                           // local subscription id -> subscription
+		active  map[uint64]*subscription                           // server subscription id -> subscription
+
+		closed    chan struct{}
+		closeOnce sync.Once
+	}
+
+	// PubSubOption is the option for the PubSub client.
+	PubSubOption func(*PubSub)
+
+	// Subscription is the typed subscription to the PubSub notifications.
+	Subscription[T any] struct {
+		pubsub *PubSub
+		sub    *subscription
+		ch     chan T
+		mu     sync.Mutex
+		closed bool
+		err    error
+		done   chan struct{}
+		once   sync.Once
+	}
+
+	// subscription is the untyped subscription state.
+	subscription struct {
+		id          uint64
+		method      string
+		unsubMethod string
+		params      []any
+		serverID    *uint64
+		oneShot     bool // the server cancels the subscription after the first notification
+		deliver     func(result json.RawMessage) bool
+		close       func(err error)
+	}
+
+	pubsubRequest struct {
+		JsonRpc string `json:"jsonrpc"`
+		ID      uint64 `json:"id"`
+		Method  string `json:"method"`
+		Params  []any  `json:"params,omitempty"`
+	}
+
+	pubsubMessage struct {
+		ID     *uint64         `json:"id"`
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+		Method string `json:"method"`
+		Params *struct {
+			Result       json.RawMessage `json:"result"`
+			Subscription uint64          `json:"subscription"`
+		} `json:"params"`
+	}
+)
+
+// WithPubSubReconnectDelay sets the initial delay between reconnect attempts.
+// The delay is doubled after each failed attempt up to the max delay.
+func WithPubSubReconnectDelay(delay, maxDelay time.Duration) PubSubOption {
+	return func(p *PubSub) {
+		p.reconnectDelay = delay
+		p.maxReconnectDelay = maxDelay
+	}
+}
+
+// WithPubSubPingInterval sets the interval of the pings; default is 30 seconds.
+// The connection is considered lost if no message or pong is received for two intervals.
+func WithPubSubPingInterval(interval time.Duration) PubSubOption {
+	return func(p *PubSub) {
+		p.pingInterval = interval
+	}
+}
+
+// WithPubSubDialer sets a custom websocket dialer.
+func WithPubSubDialer(dialer *websocket.Dialer) PubSubOption {
+	return func(p *PubSub) {
+		p.dialer = dialer
+	}
+}
+
+// NewPubSub connects to the solana PubSub websocket endpoint.
+// Returns the PubSub client or an error.
+func NewPubSub(ctx context.Context, endpoint string, opts ...PubSubOption) (*PubSub, error) {
+	p := &PubSub{
+		endpoint:          endpoint,
+		dialer:            websocket.DefaultDialer,
+		reconnectDelay:    500 * time.Millisecond,
+		maxReconnectDelay: 30 * time.Second,
+		pingInterval:      30 * time.Second,
+		pending:           make(map[uint64]func(json.RawMessage, error)),
+		subs:              make(map[uint64]*subscription),
+		active:            make(map[uint64]*subscription),
+		closed:            make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	conn, _, err := p.dialer.DialContext(ctx, p.endpoint, nil)
+	if err != nil {
+		return nil, utils.StackErrors(ErrConnectPubSub, err)
+	}
+	p.conn = conn
+
+	go p.run(conn)
+
+	return p, nil
+}
+
+// Close closes the connection and all the subscriptions.
+func (p *PubSub) Close() error {
+	var err error
+	p.closeOnce.Do(func() {
+		close(p.closed)
+
+		p.mu.Lock()
+		conn := p.conn
+		p.conn = nil
+		subs := p.subs
+		p.subs = make(map[uint64]*subscription)
+		p.active = make(map[uint64]*subscription)
+		p.failPending(ErrPubSubClosed)
+		p.mu.Unlock()
+
+		for _, sub := range subs {
+			sub.close(nil)
+		}
+		if conn != nil {
+			err = conn.Close()
+		}
+	})
+
+	return err
+}
+
+// Notifications returns the channel of the subscription notifications.
+// The channel is closed when the subscription is cancelled, the PubSub client is closed
+// or the server rejects the resubscription after a reconnect, see Err.
+// The consumer must drain the channel, otherwise the notifications of the other subscriptions are blocked.
+func (s *Subscription[T]) Notifications() <-chan T {
+	return s.ch
+}
+
+// Err returns the error that closed the notifications channel, e.g. ErrResubscribe;
+// nil if the subscription is active, cancelled or the PubSub client is closed.
+func (s *Subscription[T]) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Unsubscribe cancels the subscription and closes the notifications channel.
+func (s *Subscription[T]) Unsubscribe() {
+	s.pubsub.unsubscribe(s.sub)
+	s.close(nil)
+}
+
+// close closes the notifications channel with the error, if any.
+func (s *Subscription[T]) close(err error) {
+	s.once.Do(func() { close(s.done) })
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		s.err = err
+		close(s.ch)
+	}
+}
+
+// send sends the notification to the channel.
+// Returns false if the subscription is closed.
+func (s *Subscription[T]) send(v T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+
+	select {
+	case s.ch <- v:
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+// subscribe creates a new typed subscription.
+func subscribe[T any](
+	ctx context.Context,
+	p *PubSub,
+	method, unsubMethod string,
+	params []any,
+	oneShot bool,
+	decode func(result json.RawMessage) (T, error),
+) (*Subscription[T], error) {
+	s := &Subscription[T]{
+		pubsub: p,
+		ch:     make(chan T, 1),
+		done:   make(chan struct{}),
+	}
+	s.sub = &subscription{
+		method:      method,
+		unsubMethod: unsubMethod,
+		params:      params,
+		oneShot:     oneShot,
+		deliver: func(result json.RawMessage) bool {
+			v, err := decode(result)
+			if err != nil {
+				return true
+			}
+			return s.send(v)
+		},
+		close: s.close,
+	}
+
+	if err := p.subscribe(ctx, s.sub); err != nil {
+		return nil, utils.StackErrors(ErrSubscribe, fmt.Errorf("%s", method), err)
+	}
+
+	return s, nil
+}
+
+// subscribe registers the subscription and waits for the server subscription id.
+func (p *PubSub) subscribe(ctx context.Context, sub *subscription) error {
+	done := make(chan error, 1)
+
+	p.mu.Lock()
+	select {
+	case <-p.closed:
+		p.mu.Unlock()
+		return ErrPubSubClosed
+	default:
+	}
+	p.nextID++
+	sub.id = p.nextID
+	p.subs[sub.id] = sub
+	conn, req, err := p.requestLocked(sub.method, sub.params, func(result json.RawMessage, err error) {
+		if err == nil {
+			err = p.activate(sub, result)
+		}
+		done <- err
+	})
+	p.mu.Unlock()
+
+	if err == nil {
+		err = p.write(conn, req)
+	}
+	if err != nil {
+		p.unsubscribe(sub)
+		return err
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			p.unsubscribe(sub)
+		}
+		return err
+	case <-ctx.Done():
+		p.unsubscribe(sub)
+		return ctx.Err()
+	}
+}
+
+// activate stores the server subscription id of the subscription.
+// Must be called from the response handler.
+func (p *PubSub) activate(sub *subscription, result json.RawMessage) error {
+	var serverID uint64
+	if err := json.Unmarshal(result, &serverID); err != nil {
+		return fmt.Errorf("invalid subscription id: %w", err)
+	}
+
+	p.mu.Lock()
+	if _, ok := p.subs[sub.id]; !ok {
+		// unsubscribed while waiting for the response
+		conn, req, err := p.requestLocked(sub.unsubMethod, []any{serverID}, nil)
+		p.mu.Unlock()
+		if err == nil {
+			p.write(conn, req)
+		}
+		return nil
+	}
+	sub.serverID = &serverID
+	p.active[serverID] = sub
+	p.mu.Unlock()
+
+	return nil
+}
+
+// unsubscribe removes the subscription and cancels it on the server.
+func (p *PubSub) unsubscribe(sub *subscription) {
+	p.mu.Lock()
+	if _, ok := p.subs[sub.id]; !ok || sub.serverID == nil {
+		delete(p.subs, sub.id)
+		p.mu.Unlock()
+		return
+	}
+	delete(p.subs, sub.id)
+	delete(p.active, *sub.serverID)
+	conn, req, err := p.requestLocked(sub.unsubMethod, []any{*sub.serverID}, nil)
+	p.mu.Unlock()
+
+	if err == nil {
+		p.write(conn, req)
+	}
+}
+
+// requestLocked builds the request on the current connection and registers its response handler.
+// Must be called with p.mu locked; the request is written by write after p.mu is unlocked,
+// so a slow connection does not block the other calls.
+func (p *PubSub) requestLocked(method string, params []any, handler func(json.RawMessage, error)) (*websocket.Conn, pubsubRequest, error) {
+	if p.conn == nil {
+		return nil, pubsubRequest{}, ErrPubSubDisconnected
+	}
+
+	p.nextID++
+	req := pubsubRequest{JsonRpc: "2.0", ID: p.nextID, Method: method, Params: params}
+	if handler != nil {
+		p.pending[req.ID] = handler
+	}
+
+	return p.conn, req, nil
+}
+
+// write writes the request to the connection.
+// The response handler of the request is removed if the write fails.
+func (p *PubSub) write(conn *websocket.Conn, req pubsubRequest) error {
+	p.writeMu.Lock()
+	conn.SetWriteDeadline(time.Now().Add(p.pingInterval))
+	err := conn.WriteJSON(req)
+	p.writeMu.Unlock()
+	if err != nil {
+		p.mu.Lock()
+		delete(p.pending, req.ID)
+		p.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// failPending fails all the requests waiting for the response.
+// Must be called with p.mu locked.
+func (p *PubSub) failPending(err error) {
+	for id, handler := range p.pending {
+		delete(p.pending, id)
+		go handler(nil, err)
+	}
+}
+
+// run reads the messages from the connection and reconnects if the connection is lost.
+func (p *PubSub) run(conn *websocket.Conn) {
+	for {
+		p.read(conn)
+
+		select {
+		case <-p.closed:
+			return
+		default:
+		}
+
+		conn = p.reconnect()
+		if conn == nil {
+			return
+		}
+	}
+}
+
+// read reads the messages until the connection fails.
+// The connection fails if neither a message nor a pong is received for two ping intervals.
+func (p *PubSub) read(conn *websocket.Conn) {
+	stop := make(chan struct{})
+	defer close(stop)
+	go p.ping(conn, stop)
+
+	timeout := 2 * p.pingInterval
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(timeout))
+	})
+
+	for {
+		var msg pubsubMessage
+		err := conn.ReadJSON(&msg)
+		conn.SetReadDeadline(time.Now().Add(timeout))
+		if err != nil {
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				continue
+			}
+			return
+		}
+
+		switch {
+		case msg.ID != nil:
+			p.handleResponse(msg)
+		case msg.Params != nil:
+			p.handleNotification(msg)
+		}
+	}
+}
+
+// ping pings the connection on every ping interval until stopped.
+func (p *PubSub) ping(conn *websocket.Conn, stop <-chan struct{}) {
+	tick := time.NewTicker(p.pingInterval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-tick.C:
+			// the failed ping is detected by the read deadline
+			conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(p.pingInterval))
+		}
+	}
+}
+
+// handleResponse calls the response handler of the request.
+func (p *PubSub) handleResponse(msg pubsubMessage) {
+	p.mu.Lock()
+	handler, ok := p.pending[*msg.ID]
+	delete(p.pending, *msg.ID)
+	p.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	if msg.Error != nil {
+		handler(nil, fmt.Errorf("rpc error %d: %s", msg.Error.Code, msg.Error.Message))
+		return
+	}
+	handler(msg.Result, nil)
+}
+
+// handleNotification delivers the notification to the subscription.
+func (p *PubSub) handleNotification(msg pubsubMessage) {
+	p.mu.Lock()
+	sub, ok := p.active[msg.Params.Subscription]
+	if ok && sub.oneShot {
+		delete(p.active, msg.Params.Subscription)
+		delete(p.subs, sub.id)
+	}
+	p.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	sub.deliver(msg.Params.Result)
+	if sub.oneShot {
+		sub.close(nil)
+	}
+}
+
+// reconnect dials the endpoint until it succeeds or the client is closed,
+// then resubscribes all the active subscriptions.
+// Returns the new connection or nil if the client is closed.
+func (p *PubSub) reconnect() *websocket.Conn {
+	p.mu.Lock()
+	if p.conn != nil {
+		p.conn.Close()
+		p.conn = nil
+	}
+	p.active = make(map[uint64]*subscription)
+	p.failPending(ErrPubSubDisconnected)
+	p.mu.Unlock()
+
+	delay := p.reconnectDelay
+	for {
+		select {
+		case <-p.closed:
+			return nil
+		case <-time.After(delay):
+		}
+
+		conn, _, err := p.dialer.Dial(p.endpoint, nil)
+		if err != nil {
+			if delay *= 2; delay > p.maxReconnectDelay {
+				delay = p.maxReconnectDelay
+			}
+			continue
+		}
+
+		p.mu.Lock()
+		select {
+		case <-p.closed:
+			p.mu.Unlock()
+			conn.Close()
+			return nil
+		default:
+		}
+		p.conn = conn
+		reqs := make([]pubsubRequest, 0, len(p.subs))
+		for _, sub := range p.subs {
+			sub := sub
+			sub.serverID = nil
+			_, req, _ := p.requestLocked(sub.method, sub.params, func(result json.RawMessage, err error) {
+				if err == nil {
+					err = p.activate(sub, result)
+				}
+				p.resubscribed(sub, err)
+			})
+			reqs = append(reqs, req)
+		}
+		p.mu.Unlock()
+
+		// the failed write breaks the connection, the subscriptions are resubscribed after the next reconnect
+		for _, req := range reqs {
+			if p.write(conn, req) != nil {
+				break
+			}
+		}
+
+		return conn
+	}
+}
+
+// resubscribed handles the response of the resubscription after a reconnect.
+// The subscription rejected by the server is removed and its notifications channel is closed with the error.
+func (p *PubSub) resubscribed(sub *subscription, err error) {
+	if err == nil || errors.Is(err, ErrPubSubDisconnected) || errors.Is(err, ErrPubSubClosed) {
+		return
+	}
+
+	p.mu.Lock()
+	_, ok := p.subs[sub.id]
+	delete(p.subs, sub.id)
+	p.mu.Unlock()
+
+	if ok {
+		sub.close(utils.StackErrors(ErrResubscribe, fmt.Errorf("%s", sub.method), err))
+	}
+}
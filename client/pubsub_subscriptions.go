@@ -0,0 +1,172 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/EntySquare/solana-go-sdk/client"
+	"github.com/EntySquare/solana-go-sdk/rpc"
+)
+
+type (
+	// AccountNotification is the notification of the account change.
+	AccountNotification struct {
+		Slot    uint64
+		Account client.AccountInfo
+	}
+
+	// SignatureNotification is the notification of the processed transaction.
+	SignatureNotification struct {
+		Slot uint64
+		Err  *TransactionError // nil if the transaction succeeded
+	}
+
+	// LogsNotification is the notification of the transaction logs.
+	LogsNotification struct {
+		Slot      uint64
+		Signature string
+		Err       *TransactionError // nil if the transaction succeeded
+		Logs      []string
+	}
+
+	// ProgramNotification is the notification of the change of an account owned by the program.
+	ProgramNotification struct {
+		Slot    uint64
+		Pubkey  string
+		Account client.AccountInfo
+	}
+
+	// SlotNotification is the notification of the processed slot.
+	SlotNotification struct {
+		Slot   uint64 `json:"slot"`
+		Parent uint64 `json:"parent"`
+		Root   uint64 `json:"root"`
+	}
+
+	// LogsFilter is the filter of the logs subscription.
+	LogsFilter struct {
+		All             bool   // all transactions except simple vote transactions
+		AllWithVotes    bool   // all transactions including simple vote transactions
+		MentionsAccount string // transactions that mention the base58 encoded account
+	}
+)
+
+// AccountSubscribe subscribes to the changes of the base58 encoded account.
+// Returns the subscription or an error.
+func (p *PubSub) AccountSubscribe(ctx context.Context, base58Addr string, commitment rpc.Commitment) (*Subscription[AccountNotification], error) {
+	return subscribe(ctx, p, "accountSubscribe", "accountUnsubscribe",
+		[]any{base58Addr, pubsubConfig(commitment, true)}, false,
+		func(result json.RawMessage) (AccountNotification, error) {
+			var v rpc.ValueWithContext[encodedAccount]
+			if err := json.Unmarshal(result, &v); err != nil {
+				return AccountNotification{}, err
+			}
+			account, err := v.Value.accountInfo()
+			if err != nil {
+				return AccountNotification{}, err
+			}
+			return AccountNotification{Slot: v.Context.Slot, Account: account}, nil
+		},
+	)
+}
+
+// SignatureSubscribe subscribes to the processing of the transaction with the given signature.
+// The subscription is cancelled by the server after the first notification, the channel is closed after it.
+// Returns the subscription or an error.
+func (p *PubSub) SignatureSubscribe(ctx context.Context, txhash string, commitment rpc.Commitment) (*Subscription[SignatureNotification], error) {
+	return subscribe(ctx, p, "signatureSubscribe", "signatureUnsubscribe",
+		[]any{txhash, pubsubConfig(commitment, false)}, true,
+		func(result json.RawMessage) (SignatureNotification, error) {
+			var v rpc.ValueWithContext[struct {
+				Err any `json:"err"`
+			}]
+			if err := json.Unmarshal(result, &v); err != nil {
+				return SignatureNotification{}, err
+			}
+			return SignatureNotification{Slot: v.Context.Slot, Err: ParseTransactionError(v.Value.Err)}, nil
+		},
+	)
+}
+
+// LogsSubscribe subscribes to the logs of the transactions matching the filter.
+// Returns the subscription or an error.
+func (p *PubSub) LogsSubscribe(ctx context.Context, filter LogsFilter, commitment rpc.Commitment) (*Subscription[LogsNotification], error) {
+	var filterParam any
+	switch {
+	case filter.MentionsAccount != "":
+		filterParam = map[string]any{"mentions": []string{filter.MentionsAccount}}
+	case filter.AllWithVotes:
+		filterParam = "allWithVotes"
+	case filter.All:
+		filterParam = "all"
+	default:
+		return nil, fmt.Errorf("logs filter is required")
+	}
+
+	return subscribe(ctx, p, "logsSubscribe", "logsUnsubscribe",
+		[]any{filterParam, pubsubConfig(commitment, false)}, false,
+		func(result json.RawMessage) (LogsNotification, error) {
+			var v rpc.ValueWithContext[struct {
+				Signature string   `json:"signature"`
+				Err       any      `json:"err"`
+				Logs      []string `json:"logs"`
+			}]
+			if err := json.Unmarshal(result, &v); err != nil {
+				return LogsNotification{}, err
+			}
+			return LogsNotification{
+				Slot:      v.Context.Slot,
+				Signature: v.Value.Signature,
+				Err:       ParseTransactionError(v.Value.Err),
+				Logs:      v.Value.Logs,
+			}, nil
+		},
+	)
+}
+
+// ProgramSubscribe subscribes to the changes of the accounts owned by the base58 encoded program.
+// Returns the subscription or an error.
+func (p *PubSub) ProgramSubscribe(ctx context.Context, base58ProgramID string, commitment rpc.Commitment) (*Subscription[ProgramNotification], error) {
+	return subscribe(ctx, p, "programSubscribe", "programUnsubscribe",
+		[]any{base58ProgramID, pubsubConfig(commitment, true)}, false,
+		func(result json.RawMessage) (ProgramNotification, error) {
+			var v rpc.ValueWithContext[struct {
+				Pubkey  string         `json:"pubkey"`
+				Account encodedAccount `json:"account"`
+			}]
+			if err := json.Unmarshal(result, &v); err != nil {
+				return ProgramNotification{}, err
+			}
+			account, err := v.Value.Account.accountInfo()
+			if err != nil {
+				return ProgramNotification{}, err
+			}
+			return ProgramNotification{Slot: v.Context.Slot, Pubkey: v.Value.Pubkey, Account: account}, nil
+		},
+	)
+}
+
+// SlotSubscribe subscribes to the slots processed by the node.
+// Returns the subscription or an error.
+func (p *PubSub) SlotSubscribe(ctx context.Context) (*Subscription[SlotNotification], error) {
+	return subscribe(ctx, p, "slotSubscribe", "slotUnsubscribe", nil, false,
+		func(result json.RawMessage) (SlotNotification, error) {
+			var v SlotNotification
+			err := json.Unmarshal(result, &v)
+			return v, err
+		},
+	)
+}
+
+// pubsubConfig returns the subscription config.
+func pubsubConfig(commitment rpc.Commitment, base64Encoding bool) map[string]any {
+	cfg := map[string]any{}
+	if commitment != "" {
+		cfg["commitment"] = commitment
+	}
+	if base64Encoding {
+		cfg["encoding"] = "base64"
+	}
+	return cfg
+}
@@ -0,0 +1,194 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/EntySquare/solana-go-sdk/rpc"
+	"github.com/EntySquare/solana/client"
+	"github.com/EntySquare/solana/tests/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPubSub_AccountSubscribe(t *testing.T) {
+	server := mock.NewPubSubServer()
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pubsub, err := client.NewPubSub(ctx, server.URL(), client.WithPubSubReconnectDelay(10*time.Millisecond, 50*time.Millisecond))
+	require.NoError(t, err)
+	defer pubsub.Close()
+
+	owner := "11111111111111111111111111111111"
+	sub, err := pubsub.AccountSubscribe(ctx, "FuQhSmAT6kAmmzCMiiYbzFcTQJFuu6raXAdCFibz4YPR", rpc.CommitmentConfirmed)
+	require.NoError(t, err)
+
+	notify := func(slot, lamports uint64) {
+		srvSub, ok := server.WaitForSubscription("accountSubscribe", 5*time.Second)
+		require.True(t, ok)
+		require.True(t, server.Notify(srvSub.ID, map[string]any{
+			"context": map[string]any{"slot": slot},
+			"value": map[string]any{
+				"lamports":   lamports,
+				"owner":      owner,
+				"data":       []string{"AQID", "base64"},
+				"executable": false,
+				"rentEpoch":  1,
+			},
+		}))
+	}
+
+	notify(10, 1000)
+	n := <-sub.Notifications()
+	require.EqualValues(t, 10, n.Slot)
+	require.EqualValues(t, 1000, n.Account.Lamports)
+	require.Equal(t, owner, n.Account.Owner.ToBase58())
+	require.Equal(t, []byte{1, 2, 3}, n.Account.Data)
+
+	// the client must reconnect and resubscribe
+	server.DropConnections()
+	notify(11, 2000)
+	n = <-sub.Notifications()
+	require.EqualValues(t, 11, n.Slot)
+	require.EqualValues(t, 2000, n.Account.Lamports)
+
+	sub.Unsubscribe()
+	_, ok := <-sub.Notifications()
+	require.False(t, ok)
+	require.Eventually(t, func() bool { return len(server.Subscriptions()) == 0 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestPubSub_HalfOpenConnection(t *testing.T) {
+	server := mock.NewPubSubServer()
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pubsub, err := client.NewPubSub(ctx, server.URL(),
+		client.WithPubSubReconnectDelay(10*time.Millisecond, 50*time.Millisecond),
+		client.WithPubSubPingInterval(20*time.Millisecond),
+	)
+	require.NoError(t, err)
+	defer pubsub.Close()
+
+	sub, err := pubsub.SlotSubscribe(ctx)
+	require.NoError(t, err)
+	srvSub, ok := server.WaitForSubscription("slotSubscribe", 5*time.Second)
+	require.True(t, ok)
+
+	// the connection stays open but goes silent, the client must detect it by the missing pongs and reconnect
+	server.StopPongs()
+	require.Eventually(t, func() bool {
+		latest, ok := server.WaitForSubscription("slotSubscribe", time.Second)
+		return ok && latest.ID > srvSub.ID
+	}, 5*time.Second, 10*time.Millisecond)
+
+	latest, _ := server.WaitForSubscription("slotSubscribe", time.Second)
+	require.True(t, server.Notify(latest.ID, map[string]any{"slot": 3, "parent": 2, "root": 1}))
+	require.Equal(t, client.SlotNotification{Slot: 3, Parent: 2, Root: 1}, <-sub.Notifications())
+}
+
+func TestPubSub_ResubscribeError(t *testing.T) {
+	server := mock.NewPubSubServer()
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pubsub, err := client.NewPubSub(ctx, server.URL(), client.WithPubSubReconnectDelay(10*time.Millisecond, 50*time.Millisecond))
+	require.NoError(t, err)
+	defer pubsub.Close()
+
+	sub, err := pubsub.LogsSubscribe(ctx, client.LogsFilter{All: true}, "")
+	require.NoError(t, err)
+	_, ok := server.WaitForSubscription("logsSubscribe", 5*time.Second)
+	require.True(t, ok)
+
+	// the subscription is closed with the error instead of silently staying without notifications
+	server.RejectSubscriptions("logsSubscribe", "too many subscriptions")
+	server.DropConnections()
+
+	select {
+	case _, ok := <-sub.Notifications():
+		require.False(t, ok)
+	case <-ctx.Done():
+		t.Fatal("subscription not closed")
+	}
+	require.ErrorIs(t, sub.Err(), client.ErrResubscribe)
+	require.ErrorContains(t, sub.Err(), "too many subscriptions")
+}
+
+func TestPubSub_SignatureSubscribe(t *testing.T) {
+	server := mock.NewPubSubServer()
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pubsub, err := client.NewPubSub(ctx, server.URL())
+	require.NoError(t, err)
+	defer pubsub.Close()
+
+	sub, err := pubsub.SignatureSubscribe(ctx, "5j7s6NiJS3JAkvgkoc18WVAsiSaci2pxB2A6ueCJP4tprA2TFg9wSyTLeYouxPBJEMzJinENTkpA52YStRW5Dia7", rpc.CommitmentFinalized)
+	require.NoError(t, err)
+
+	srvSub, ok := server.WaitForSubscription("signatureSubscribe", 5*time.Second)
+	require.True(t, ok)
+	require.True(t, server.Notify(srvSub.ID, map[string]any{
+		"context": map[string]any{"slot": 5},
+		"value":   map[string]any{"err": map[string]any{"InstructionError": []any{0, map[string]any{"Custom": 1}}}},
+	}))
+
+	n, ok := <-sub.Notifications()
+	require.True(t, ok)
+	require.EqualValues(t, 5, n.Slot)
+	require.NotNil(t, n.Err)
+	require.ErrorIs(t, n.Err, client.ErrCustomProgramError)
+
+	// the signature subscription is closed after the first notification
+	_, ok = <-sub.Notifications()
+	require.False(t, ok)
+}
+
+func TestPubSub_LogsAndSlotSubscribe(t *testing.T) {
+	server := mock.NewPubSubServer()
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pubsub, err := client.NewPubSub(ctx, server.URL())
+	require.NoError(t, err)
+
+	logs, err := pubsub.LogsSubscribe(ctx, client.LogsFilter{All: true}, "")
+	require.NoError(t, err)
+	slots, err := pubsub.SlotSubscribe(ctx)
+	require.NoError(t, err)
+
+	srvSub, ok := server.WaitForSubscription("logsSubscribe", 5*time.Second)
+	require.True(t, ok)
+	require.True(t, server.Notify(srvSub.ID, map[string]any{
+		"context": map[string]any{"slot": 7},
+		"value":   map[string]any{"signature": "sig", "err": nil, "logs": []string{"Program log: hello"}},
+	}))
+	l := <-logs.Notifications()
+	require.Equal(t, "sig", l.Signature)
+	require.Nil(t, l.Err)
+	require.Equal(t, []string{"Program log: hello"}, l.Logs)
+
+	srvSub, ok = server.WaitForSubscription("slotSubscribe", 5*time.Second)
+	require.True(t, ok)
+	require.True(t, server.Notify(srvSub.ID, map[string]any{"slot": 9, "parent": 8, "root": 1}))
+	require.Equal(t, client.SlotNotification{Slot: 9, Parent: 8, Root: 1}, <-slots.Notifications())
+
+	// closing the client closes all the subscriptions
+	require.NoError(t, pubsub.Close())
+	_, ok = <-logs.Notifications()
+	require.False(t, ok)
+	_, ok = <-slots.Notifications()
+	require.False(t, ok)
+}
@@ -0,0 +1,297 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type (
+	// RateLimit is the token bucket budget of the RPC requests.
+	RateLimit struct {
+		Rate  float64 // requests per second; zero means unlimited
+		Burst int     // max number of requests sent at once; default is 1
+	}
+
+	// RateLimiter paces the RPC requests with the token buckets.
+	// Every request takes a token from the client-wide bucket and from the bucket of its method, if the method has one;
+	// a JSON-RPC batch takes a token per element.
+	// A request throttled with 429 is retried with the exponential backoff and jitter, honouring Retry-After.
+	RateLimiter struct {
+		limit      *tokenBucket
+		methods    map[string]*tokenBucket
+		maxRetries int
+		baseDelay  time.Duration
+		maxDelay   time.Duration
+
+		randMu sync.Mutex
+		rand   *rand.Rand
+	}
+
+	// RateLimiterOption is the option for the rate limiter.
+	RateLimiterOption func(*RateLimiter)
+
+	// rateLimitTransport is the http transport which paces the requests with the rate limiter.
+	rateLimitTransport struct {
+		limiter *RateLimiter
+		next    http.RoundTripper
+	}
+
+	// tokenBucket is the token bucket refilled at the constant rate.
+	tokenBucket struct {
+		mu     sync.Mutex
+		rate   float64
+		burst  float64
+		tokens float64
+		last   time.Time
+	}
+)
+
+// WithMethodRateLimit sets the budget of the RPC method, e.g. getSignaturesForAddress.
+func WithMethodRateLimit(method string, limit RateLimit) RateLimiterOption {
+	return func(l *RateLimiter) {
+		l.methods[method] = newTokenBucket(limit)
+	}
+}
+
+// WithRetryBackoff sets the retries of the throttled requests.
+// The delay starts at the base delay and doubles after each retry up to the max delay;
+// default is 5 retries, 500 milliseconds base delay and 30 seconds max delay.
+func WithRetryBackoff(maxRetries int, baseDelay, maxDelay time.Duration) RateLimiterOption {
+	return func(l *RateLimiter) {
+		l.maxRetries = maxRetries
+		l.baseDelay = baseDelay
+		l.maxDelay = maxDelay
+	}
+}
+
+// NewRateLimiter creates a new rate limiter with the client-wide budget.
+func NewRateLimiter(limit RateLimit, opts ...RateLimiterOption) *RateLimiter {
+	l := &RateLimiter{
+		limit:      newTokenBucket(limit),
+		methods:    make(map[string]*tokenBucket),
+		maxRetries: 5,
+		baseDelay:  500 * time.Millisecond,
+		maxDelay:   30 * time.Second,
+		rand:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+// Wait blocks until the request of the RPC method fits the budget.
+// Returns an error if the context is done first, the reserved tokens are given back then.
+func (l *RateLimiter) Wait(ctx context.Context, method string) error {
+	return l.wait(ctx, []string{method})
+}
+
+// wait blocks until the requests of the RPC methods, e.g. the elements of a JSON-RPC batch, fit the budget.
+// Every request takes a token, the tokens are given back if the context is done first.
+func (l *RateLimiter) wait(ctx context.Context, methods []string) error {
+	counts := make(map[string]int)
+	for _, method := range methods {
+		counts[method]++
+	}
+
+	n := len(methods)
+	if n == 0 {
+		n = 1
+	}
+	delay := l.limit.reserve(n)
+	for method, count := range counts {
+		if bucket, ok := l.methods[method]; ok {
+			if d := bucket.reserve(count); d > delay {
+				delay = d
+			}
+		}
+	}
+
+	if err := sleep(ctx, delay); err != nil {
+		l.limit.cancel(n)
+		for method, count := range counts {
+			if bucket, ok := l.methods[method]; ok {
+				bucket.cancel(count)
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+// backoff returns the delay before the retry of the throttled request.
+// Retry-After of the response takes precedence over the exponential backoff.
+func (l *RateLimiter) backoff(attempt int, resp *http.Response) time.Duration {
+	if d, ok := retryAfter(resp); ok {
+		return d
+	}
+
+	delay := l.baseDelay << attempt
+	if delay > l.maxDelay || delay <= 0 {
+		delay = l.maxDelay
+	}
+
+	// full jitter spreads the retries of the concurrent requests
+	l.randMu.Lock()
+	defer l.randMu.Unlock()
+	return time.Duration(l.rand.Int63n(int64(delay) + 1))
+}
+
+// transport wraps the http transport with the rate limiter.
+func (l *RateLimiter) transport(next http.RoundTripper) http.RoundTripper {
+	return &rateLimitTransport{limiter: l, next: next}
+}
+
+// RoundTrip waits for the budget of the request method and retries the throttled request.
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	methods := requestMethods(body)
+	for attempt := 0; ; attempt++ {
+		if err := t.limiter.wait(req.Context(), methods); err != nil {
+			return nil, err
+		}
+
+		r := req.Clone(req.Context())
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+
+		resp, err := t.next.RoundTrip(r)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests || attempt >= t.limiter.maxRetries {
+			return resp, err
+		}
+
+		delay := t.limiter.backoff(attempt, resp)
+		resp.Body.Close()
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+		rpcCallTrackerFromContext(req.Context()).addRetry()
+	}
+}
+
+// requestMethods returns the methods of the JSON-RPC request, one per element of the batch.
+func requestMethods(body []byte) []string {
+	type rpcRequest struct {
+		Method string `json:"method"`
+	}
+
+	if body = bytes.TrimSpace(body); len(body) > 0 && body[0] == '[' {
+		var batch []rpcRequest
+		if err := json.Unmarshal(body, &batch); err == nil && len(batch) > 0 {
+			methods := make([]string, len(batch))
+			for i, r := range batch {
+				methods[i] = r.Method
+			}
+			return methods
+		}
+	}
+
+	var r rpcRequest
+	json.Unmarshal(body, &r)
+	return []string{r.Method}
+}
+
+// newTokenBucket creates a new full token bucket.
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &tokenBucket{rate: limit.Rate, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// reserve takes n tokens from the bucket.
+// Returns the time to wait until the tokens are available.
+func (b *tokenBucket) reserve(n int) time.Duration {
+	if b.rate <= 0 {
+		return 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	// the tokens are taken even if they are not available yet, the next requests queue up behind them
+	b.tokens -= float64(n)
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel gives back n tokens taken by the request which has not been sent.
+func (b *tokenBucket) cancel(n int) {
+	if b.rate <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens += float64(n)
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+// retryAfter parses the Retry-After header given in seconds or as a http date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	header := resp.Header.Get("Retry-After")
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// sleep waits for the duration or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
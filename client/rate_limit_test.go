@@ -0,0 +1,129 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/EntySquare/solana/client"
+	"github.com/stretchr/testify/require"
+)
+
+// newThrottlingServer starts a json rpc server which answers getBlockHeight with 429 to the first throttled requests.
+func newThrottlingServer(t *testing.T, throttled int32, calls *int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID uint64 `json:"id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if atomic.AddInt32(calls, 1) <= throttled {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": 42})
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestRateLimit_MethodBudget(t *testing.T) {
+	var calls int32
+	server := newThrottlingServer(t, 0, &calls)
+
+	c := client.New(
+		client.SetSolanaEndpoint(server.URL),
+		client.SetRateLimit(client.RateLimit{Rate: 1000, Burst: 10},
+			client.WithMethodRateLimit("getBlockHeight", client.RateLimit{Rate: 20, Burst: 1}),
+		),
+	)
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		_, err := c.GetBlockHeight(context.Background())
+		require.NoError(t, err)
+	}
+	// the first request takes the burst token, the next 4 wait 50ms each
+	require.GreaterOrEqual(t, time.Since(start), 180*time.Millisecond)
+	require.EqualValues(t, 5, atomic.LoadInt32(&calls))
+}
+
+func TestRateLimit_RetryThrottled(t *testing.T) {
+	var calls int32
+	server := newThrottlingServer(t, 2, &calls)
+
+	c := client.New(
+		client.SetSolanaEndpoint(server.URL),
+		client.SetRateLimit(client.RateLimit{}, client.WithRetryBackoff(3, time.Second, time.Second)),
+	)
+
+	// Retry-After takes precedence over the 1 second backoff
+	start := time.Now()
+	height, err := c.GetBlockHeight(context.Background())
+	require.NoError(t, err)
+	require.EqualValues(t, 42, height)
+	require.EqualValues(t, 3, atomic.LoadInt32(&calls))
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestRateLimit_RetriesExhausted(t *testing.T) {
+	var calls int32
+	server := newThrottlingServer(t, 10, &calls)
+
+	c := client.New(
+		client.SetSolanaEndpoint(server.URL),
+		client.SetRateLimit(client.RateLimit{}, client.WithRetryBackoff(2, time.Millisecond, time.Millisecond)),
+	)
+
+	_, err := c.GetBlockHeight(context.Background())
+	require.ErrorIs(t, err, client.ErrGetBlockHeight)
+	require.EqualValues(t, 3, atomic.LoadInt32(&calls))
+}
+
+func TestRateLimit_BatchWeight(t *testing.T) {
+	var requests int32
+	server := newAccountsServer(t, nil, &requests)
+
+	c := client.New(
+		client.SetSolanaEndpoint(server.URL),
+		client.SetRateLimit(client.RateLimit{Rate: 20, Burst: 1}),
+	)
+
+	addrs := make([]string, 201)
+	for i := range addrs {
+		addrs[i] = fmt.Sprintf("account-%d", i)
+	}
+
+	// the batch of 3 requests takes 3 tokens, the burst token and 2 more waiting 50ms each
+	start := time.Now()
+	_, err := c.GetMultipleAccounts(context.Background(), addrs)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, atomic.LoadInt32(&requests))
+	require.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+func TestRateLimiter_WaitCancelled(t *testing.T) {
+	limiter := client.NewRateLimiter(client.RateLimit{Rate: 10, Burst: 1})
+	require.NoError(t, limiter.Wait(context.Background(), "getBlockHeight"))
+
+	// the cancelled waits give their tokens back and do not delay the next requests
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 5; i++ {
+		require.ErrorIs(t, limiter.Wait(ctx, "getBlockHeight"), context.Canceled)
+	}
+
+	start := time.Now()
+	require.NoError(t, limiter.Wait(context.Background(), "getBlockHeight"))
+	require.Less(t, time.Since(start), 200*time.Millisecond)
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/EntySquare/solana-go-sdk/client"
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/rpc"
+	"github.com/EntySquare/solana/utils"
+)
+
+// encodedAccount is the base64 encoded account returned by the RPC node.
+type encodedAccount struct {
+	Lamports   uint64   `json:"lamports"`
+	Owner      string   `json:"owner"`
+	Executable bool     `json:"executable"`
+	RentEpoch  uint64   `json:"rentEpoch"`
+	Data       []string `json:"data"`
+}
+
+// callRPC calls the given json rpc method which is not covered by the solana-go-sdk client.
+// Returns the decoded result or an error.
+func callRPC[T any](ctx context.Context, c *Client, method string, params ...any) (T, error) {
+	var result T
+
+	body, err := c.rpcClient.RpcClient.Call(ctx, append([]any{method}, params...)...)
+	if err != nil {
+		return result, fmt.Errorf("rpc: call %s error: %w, body: %s", method, err, string(body))
+	}
+
+	var resp rpc.JsonRpcResponse[T]
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return result, fmt.Errorf("rpc: failed to decode %s response: %w", method, err)
+	}
+	if resp.Error != nil {
+		return result, resp.Error
+	}
+
+	return resp.Result, nil
+}
+
+// accountInfo converts the encoded account into the account info.
+func (a encodedAccount) accountInfo() (client.AccountInfo, error) {
+	info := client.AccountInfo{
+		Lamports:   a.Lamports,
+		Owner:      common.PublicKeyFromString(a.Owner),
+		Executable: a.Executable,
+		RentEpoch:  a.RentEpoch,
+	}
+	if len(a.Data) > 0 && a.Data[0] != "" {
+		data, err := utils.Base64ToBytes(a.Data[0])
+		if err != nil {
+			return client.AccountInfo{}, fmt.Errorf("failed to decode account data: %w", err)
+		}
+		info.Data = data
+	}
+
+	return info, nil
+}
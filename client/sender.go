@@ -0,0 +1,224 @@
+package client
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/EntySquare/solana-go-sdk/client"
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/rpc"
+	sdktypes "github.com/EntySquare/solana-go-sdk/types"
+	"github.com/EntySquare/solana/types"
+	"github.com/EntySquare/solana/utils"
+)
+
+const (
+	// maxTrackedBlockhashes is the number of recorded blockhashes after which the expired ones are pruned.
+	maxTrackedBlockhashes = 256
+	// blockhashValidity is the number of blocks after which a blockhash expires.
+	blockhashValidity = 150
+	// maxTrackedTransactions is the number of the sent transactions after which the stale ones are pruned.
+	maxTrackedTransactions = 256
+	// sentTransactionTTL is the time after which the sent transaction is not expected to be confirmed anymore.
+	sentTransactionTTL = 2 * time.Minute
+)
+
+// sentTransaction is the transaction sent by the client, waiting for the confirmation.
+type sentTransaction struct {
+	blockhash string   // empty for the durable nonce transaction, it never expires
+	writable  []string // base58 encoded writable accounts to invalidate once it is confirmed
+	sentAt    time.Time
+}
+
+// SendAndConfirmParams are the parameters for SendAndConfirmTransaction function.
+type SendAndConfirmParams struct {
+	Transaction          string                                    // required; the signed base64 encoded transaction
+	LastValidBlockHeight uint64                                    // optional; default is the height recorded for the transaction blockhash by NewTransaction
+	Commitment           rpc.Commitment                            // optional; default is the client commitment
+	ResendInterval       time.Duration                             // optional; default is 2 seconds
+	Rebuild              func(ctx context.Context) (string, error) // optional; returns the transaction re-signed with a fresh blockhash once the blockhash expired
+	MaxRebuilds          int                                       // optional; default is 3
+}
+
+// SendAndConfirmTransaction sends the transaction and rebroadcasts it on the resend interval
+// until it is confirmed or its blockhash expires.
+// If the blockhash expired or the node does not know it, and the rebuild callback is set, the rebuilt transaction is sent instead.
+// Returns the hash of the confirmed transaction and its status or an error.
+func (c *Client) SendAndConfirmTransaction(ctx context.Context, params SendAndConfirmParams) (string, types.TransactionStatus, error) {
+	if params.Commitment == "" {
+		params.Commitment = c.Commitment(ctx)
+	}
+	if params.ResendInterval == 0 {
+		params.ResendInterval = 2 * time.Second
+	}
+	if params.MaxRebuilds == 0 {
+		params.MaxRebuilds = 3
+	}
+
+	txSource := params.Transaction
+	lastValidBlockHeight := params.LastValidBlockHeight
+	for rebuilds := 0; ; rebuilds++ {
+		txhash, status, err := c.sendAndConfirm(ctx, txSource, lastValidBlockHeight, params)
+		if err == nil {
+			return txhash, status, nil
+		}
+		if !errors.Is(err, ErrBlockhashExpired) || params.Rebuild == nil || rebuilds >= params.MaxRebuilds {
+			return txhash, status, utils.StackErrors(ErrSendAndConfirmTransaction, err)
+		}
+
+		txSource, err = params.Rebuild(ctx)
+		if err != nil {
+			return txhash, status, utils.StackErrors(ErrSendAndConfirmTransaction, ErrRebuildTransaction, err)
+		}
+		// the height of the fresh blockhash is recorded by NewTransaction
+		lastValidBlockHeight = 0
+	}
+}
+
+// sendAndConfirm sends the transaction once and rebroadcasts it until the wait for the confirmation is done.
+func (c *Client) sendAndConfirm(ctx context.Context, txSource string, lastValidBlockHeight uint64, params SendAndConfirmParams) (string, types.TransactionStatus, error) {
+	tx, err := utils.DecodeTransaction(txSource)
+	if err != nil {
+		return "", types.TransactionStatusUnknown, utils.StackErrors(ErrDeserializeTransaction, err)
+	}
+
+	txhash, err := c.SendTransaction(ctx, txSource)
+	if err != nil {
+		// the blockhash unknown to the node after the retries of SendTransaction is stale, the transaction is rebuilt
+		if errors.Is(err, ErrBlockhashNotFound) || strings.Contains(err.Error(), "BlockhashNotFound") {
+			return "", types.TransactionStatusUnknown, utils.StackErrors(ErrBlockhashExpired, err)
+		}
+		return "", types.TransactionStatusUnknown, err
+	}
+
+	opts := []WaitOption{
+		WithWaitCommitment(params.Commitment),
+		WithPollInterval(params.ResendInterval),
+	}
+	// by default the expiry is derived from the blockhash of the sent transaction
+	if lastValidBlockHeight > 0 {
+		opts = append(opts, WithLastValidBlockHeight(lastValidBlockHeight))
+	}
+
+	resendCtx, stopResend := context.WithCancel(ctx)
+	defer stopResend()
+	go c.rebroadcast(resendCtx, tx, params.ResendInterval)
+
+	status, err := c.WaitForTransactionConfirmed(ctx, txhash, 0, opts...)
+	return txhash, status, err
+}
+
+// rebroadcast sends the transaction without the preflight on every interval until the context is done.
+// The errors are ignored, the node may reject the duplicates of the already processed transaction.
+func (c *Client) rebroadcast(ctx context.Context, tx sdktypes.Transaction, interval time.Duration) {
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+			c.rpcClient.SendTransactionWithConfig(ctx, tx, client.SendTransactionConfig{SkipPreflight: true})
+		}
+	}
+}
+
+// LastValidBlockHeight returns the last block height at which the blockhash is valid
+// and true if the blockhash was fetched by this client.
+func (c *Client) LastValidBlockHeight(blockhash string) (uint64, bool) {
+	c.blockhashesMu.Lock()
+	defer c.blockhashesMu.Unlock()
+
+	height, ok := c.blockhashes[blockhash]
+	return height, ok
+}
+
+// getLatestBlockhash gets the latest blockhash and records its last valid block height.
+func (c *Client) getLatestBlockhash(ctx context.Context) (rpc.GetLatestBlockhashValue, error) {
+	latest, err := c.rpcClient.GetLatestBlockhash(ctx)
+	if err != nil {
+		return latest, err
+	}
+
+	c.blockhashesMu.Lock()
+	defer c.blockhashesMu.Unlock()
+
+	if c.blockhashes == nil {
+		c.blockhashes = make(map[string]uint64)
+	}
+	if len(c.blockhashes) >= maxTrackedBlockhashes {
+		for blockhash, height := range c.blockhashes {
+			if height+blockhashValidity < latest.LatestValidBlockHeight {
+				delete(c.blockhashes, blockhash)
+			}
+		}
+	}
+	c.blockhashes[latest.Blockhash] = latest.LatestValidBlockHeight
+
+	return latest, nil
+}
+
+// trackSentTransaction records the transaction sent by the client,
+// so the wait for its confirmation knows its blockhash and the accounts to invalidate.
+func (c *Client) trackSentTransaction(txhash string, tx sdktypes.Transaction, writable []string) {
+	sent := sentTransaction{writable: writable, sentAt: time.Now()}
+	if !isDurableNonceTransaction(tx) {
+		sent.blockhash = tx.Message.RecentBlockHash
+	}
+
+	c.sentTransactionsMu.Lock()
+	defer c.sentTransactionsMu.Unlock()
+
+	if c.sentTransactions == nil {
+		c.sentTransactions = make(map[string]sentTransaction)
+	}
+	if len(c.sentTransactions) >= maxTrackedTransactions {
+		for signature, tracked := range c.sentTransactions {
+			if sent.sentAt.Sub(tracked.sentAt) > sentTransactionTTL {
+				delete(c.sentTransactions, signature)
+			}
+		}
+	}
+	c.sentTransactions[txhash] = sent
+}
+
+// sentTransaction returns the transaction sent by the client and true if it is tracked.
+func (c *Client) sentTransaction(txhash string) (sentTransaction, bool) {
+	c.sentTransactionsMu.Lock()
+	defer c.sentTransactionsMu.Unlock()
+
+	sent, ok := c.sentTransactions[txhash]
+	return sent, ok
+}
+
+// confirmSentTransaction stops tracking the landed transaction and invalidates the cached values of its writable accounts,
+// since the values may be read while the transaction was pending.
+func (c *Client) confirmSentTransaction(txhash string) {
+	c.sentTransactionsMu.Lock()
+	sent, ok := c.sentTransactions[txhash]
+	delete(c.sentTransactions, txhash)
+	c.sentTransactionsMu.Unlock()
+
+	if ok {
+		c.InvalidateCache(sent.writable...)
+	}
+}
+
+// isDurableNonceTransaction returns true if the first instruction of the transaction advances a nonce account.
+func isDurableNonceTransaction(tx sdktypes.Transaction) bool {
+	if len(tx.Message.Instructions) == 0 {
+		return false
+	}
+
+	instr := tx.Message.Instructions[0]
+	if instr.ProgramIDIndex >= len(tx.Message.Accounts) || tx.Message.Accounts[instr.ProgramIDIndex] != common.SystemProgramID {
+		return false
+	}
+
+	// 4 is the index of the AdvanceNonceAccount instruction of the system program
+	return len(instr.Data) >= 4 && binary.LittleEndian.Uint32(instr.Data) == 4
+}
@@ -0,0 +1,198 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/EntySquare/solana-go-sdk/program/system"
+	"github.com/EntySquare/solana-go-sdk/rpc"
+	sdktypes "github.com/EntySquare/solana-go-sdk/types"
+	"github.com/EntySquare/solana/client"
+	"github.com/EntySquare/solana/types"
+	"github.com/stretchr/testify/require"
+)
+
+// senderServer is a json rpc server which confirms the transaction after the given number of sends.
+type senderServer struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	sends       int
+	blockhashes int
+	blockHeight uint64
+	confirmed   func(sends, blockhashes int) bool
+	stale       int // the transactions are rejected with BlockhashNotFound until more blockhashes are fetched
+}
+
+func newSenderServer(t *testing.T, blockHeight uint64, confirmed func(sends, blockhashes int) bool) *senderServer {
+	s := &senderServer{blockHeight: blockHeight, confirmed: confirmed}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64 `json:"id"`
+			Method string `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		var result, rpcErr any
+		switch req.Method {
+		case "getLatestBlockhash":
+			s.blockhashes++
+			blockhashes := []string{
+				"FuQhSmAT6kAmmzCMiiYbzFcTQJFuu6raXAdCFibz4YPR",
+				"9wFFyRfZBsuAha4YcuxcXLKwMxJR43S7fPfQLusDBzvT",
+			}
+			result = map[string]any{
+				"context": map[string]any{"slot": 1},
+				"value": map[string]any{
+					"blockhash":            blockhashes[(s.blockhashes-1)%len(blockhashes)],
+					"lastValidBlockHeight": 100 * uint64(s.blockhashes),
+				},
+			}
+		case "sendTransaction":
+			s.sends++
+			result = testTxSignature
+			if s.blockhashes <= s.stale {
+				rpcErr = map[string]any{
+					"code":    -32002,
+					"message": "Transaction simulation failed: Blockhash not found",
+					"data":    map[string]any{"err": "BlockhashNotFound", "logs": []string{}},
+				}
+			}
+		case "getSignatureStatuses":
+			var status any
+			if s.confirmed(s.sends, s.blockhashes) {
+				status = map[string]any{"slot": 1, "confirmations": nil, "err": nil, "confirmationStatus": "confirmed"}
+			}
+			result = map[string]any{"context": map[string]any{"slot": 1}, "value": []any{status}}
+		case "getBlockHeight":
+			result = s.blockHeight
+		default:
+			t.Errorf("unexpected method %s", req.Method)
+		}
+		if rpcErr != nil {
+			json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "error": rpcErr})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *senderServer) stats() (sends, blockhashes int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sends, s.blockhashes
+}
+
+func newTestTransaction(t *testing.T, c *client.Client) string {
+	payer := sdktypes.NewAccount()
+	txSource, err := c.NewTransaction(context.Background(), client.NewTransactionParams{
+		FeePayer: payer.PublicKey,
+		Instructions: []sdktypes.Instruction{
+			system.Transfer(system.TransferParam{From: payer.PublicKey, To: sdktypes.NewAccount().PublicKey, Amount: 1}),
+		},
+		Signers: []sdktypes.Account{payer},
+	})
+	require.NoError(t, err)
+
+	return txSource
+}
+
+func TestSendAndConfirmTransaction_Rebroadcast(t *testing.T) {
+	server := newSenderServer(t, 1, func(sends, _ int) bool { return sends >= 3 })
+	c := client.New(client.SetSolanaEndpoint(server.URL))
+
+	txSource := newTestTransaction(t, c)
+	height, ok := c.LastValidBlockHeight("FuQhSmAT6kAmmzCMiiYbzFcTQJFuu6raXAdCFibz4YPR")
+	require.True(t, ok)
+	require.EqualValues(t, 100, height)
+
+	txhash, status, err := c.SendAndConfirmTransaction(context.Background(), client.SendAndConfirmParams{
+		Transaction:    txSource,
+		Commitment:     rpc.CommitmentConfirmed,
+		ResendInterval: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	require.Equal(t, testTxSignature, txhash)
+	require.Equal(t, types.TransactionStatusSuccess, status)
+
+	sends, _ := server.stats()
+	require.GreaterOrEqual(t, sends, 3)
+}
+
+func TestSendAndConfirmTransaction_Rebuild(t *testing.T) {
+	// the block height is past the first blockhash only, the rebuilt transaction is confirmed
+	server := newSenderServer(t, 150, func(_, blockhashes int) bool { return blockhashes >= 2 })
+	c := client.New(client.SetSolanaEndpoint(server.URL))
+
+	rebuilds := 0
+	_, status, err := c.SendAndConfirmTransaction(context.Background(), client.SendAndConfirmParams{
+		Transaction:    newTestTransaction(t, c),
+		Commitment:     rpc.CommitmentConfirmed,
+		ResendInterval: 10 * time.Millisecond,
+		Rebuild: func(ctx context.Context) (string, error) {
+			rebuilds++
+			return newTestTransaction(t, c), nil
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, types.TransactionStatusSuccess, status)
+	require.Equal(t, 1, rebuilds)
+}
+
+func TestSendAndConfirmTransaction_RebuildStale(t *testing.T) {
+	// the first blockhash is unknown to the node, the transaction is rebuilt without waiting for the expiry
+	server := newSenderServer(t, 1, func(int, int) bool { return true })
+	server.stale = 1
+	c := client.New(client.SetSolanaEndpoint(server.URL))
+
+	rebuilds := 0
+	_, status, err := c.SendAndConfirmTransaction(context.Background(), client.SendAndConfirmParams{
+		Transaction:    newTestTransaction(t, c),
+		Commitment:     rpc.CommitmentConfirmed,
+		ResendInterval: 10 * time.Millisecond,
+		Rebuild: func(ctx context.Context) (string, error) {
+			rebuilds++
+			return newTestTransaction(t, c), nil
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, types.TransactionStatusSuccess, status)
+	require.Equal(t, 1, rebuilds)
+
+	// without the rebuild the stale transaction fails as expired
+	server = newSenderServer(t, 1, func(int, int) bool { return true })
+	server.stale = 1
+	c = client.New(client.SetSolanaEndpoint(server.URL))
+	_, _, err = c.SendAndConfirmTransaction(context.Background(), client.SendAndConfirmParams{
+		Transaction:    newTestTransaction(t, c),
+		ResendInterval: 10 * time.Millisecond,
+	})
+	require.ErrorIs(t, err, client.ErrBlockhashExpired)
+	require.ErrorIs(t, err, client.ErrBlockhashNotFound)
+}
+
+func TestSendAndConfirmTransaction_Expired(t *testing.T) {
+	server := newSenderServer(t, 1000, func(int, int) bool { return false })
+	c := client.New(client.SetSolanaEndpoint(server.URL))
+
+	_, status, err := c.SendAndConfirmTransaction(context.Background(), client.SendAndConfirmParams{
+		Transaction:    newTestTransaction(t, c),
+		ResendInterval: 10 * time.Millisecond,
+	})
+	require.ErrorIs(t, err, client.ErrBlockhashExpired)
+	require.Equal(t, types.TransactionStatusUnknown, status)
+}
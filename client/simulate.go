@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/EntySquare/solana-go-sdk/client"
+	"github.com/EntySquare/solana-go-sdk/rpc"
+	"github.com/EntySquare/solana/utils"
+)
+
+type (
+	// SimulateTransactionOptions is the options for SimulateTransaction.
+	SimulateTransactionOptions struct {
+		SigVerify              bool           // optional; verify the transaction signatures; can not be used with ReplaceRecentBlockhash
+		ReplaceRecentBlockhash bool           // optional; replace the transaction blockhash with the latest one
+		Commitment             rpc.Commitment // optional; default is the node default commitment
+		Accounts               []string       // optional; base58 encoded accounts to return the diff for
+	}
+
+	// SimulateTransactionResult is the result of the transaction simulation.
+	SimulateTransactionResult struct {
+		Slot          uint64            // slot the transaction was simulated at
+		Logs          []string          // program logs
+		UnitsConsumed uint64            // compute units consumed by the transaction
+		Accounts      []AccountDiff     // diffs of the requested accounts, in the same order
+		Err           *TransactionError // decoded transaction error; nil if the simulation succeeded
+	}
+
+	// AccountDiff is the state of an account before and after the simulated transaction.
+	AccountDiff struct {
+		Address string
+		Pre     *client.AccountInfo // nil if the account does not exist before the transaction
+		Post    *client.AccountInfo // nil if the account does not exist after the transaction
+	}
+
+	simulateTransactionValue struct {
+		Err           any               `json:"err"`
+		Logs          []string          `json:"logs"`
+		Accounts      []*encodedAccount `json:"accounts"`
+		UnitsConsumed *uint64           `json:"unitsConsumed"`
+	}
+)
+
+// LamportsDelta returns the change of the account balance in lamports.
+func (d AccountDiff) LamportsDelta() int64 {
+	var pre, post int64
+	if d.Pre != nil {
+		pre = int64(d.Pre.Lamports)
+	}
+	if d.Post != nil {
+		post = int64(d.Post.Lamports)
+	}
+	return post - pre
+}
+
+// SimulateTransaction simulates the base64 encoded transaction.
+// The transaction may be partially signed unless opts.SigVerify is set.
+// Returns the simulation result or an error if the simulation could not be performed.
+// A failed transaction is not an error: check the Err field of the result.
+func (c *Client) SimulateTransaction(ctx context.Context, txSource string, opts SimulateTransactionOptions) (SimulateTransactionResult, error) {
+	if opts.SigVerify && opts.ReplaceRecentBlockhash {
+		return SimulateTransactionResult{}, utils.StackErrors(
+			ErrSimulateTransaction,
+			fmt.Errorf("sig verify can not be used with replace recent blockhash"),
+		)
+	}
+
+	tx, err := utils.DecodeTransaction(txSource)
+	if err != nil {
+		return SimulateTransactionResult{}, utils.StackErrors(ErrSimulateTransaction, ErrDeserializeTransaction, err)
+	}
+
+	var pre []client.AccountInfo
+	if len(opts.Accounts) > 0 {
+		accounts, err := c.rpcClient.GetMultipleAccounts(ctx, opts.Accounts)
+		if err != nil {
+			return SimulateTransactionResult{}, utils.StackErrors(ErrSimulateTransaction, err)
+		}
+		pre = accounts
+	}
+
+	cfg := map[string]any{
+		"encoding":               "base64",
+		"sigVerify":              opts.SigVerify,
+		"replaceRecentBlockhash": opts.ReplaceRecentBlockhash,
+	}
+	if opts.Commitment != "" {
+		cfg["commitment"] = opts.Commitment
+	}
+	if len(opts.Accounts) > 0 {
+		cfg["accounts"] = map[string]any{
+			"encoding":  "base64",
+			"addresses": opts.Accounts,
+		}
+	}
+
+	resp, err := callRPC[rpc.ValueWithContext[simulateTransactionValue]](ctx, c, "simulateTransaction", txSource, cfg)
+	if err != nil {
+		return SimulateTransactionResult{}, utils.StackErrors(ErrSimulateTransaction, err)
+	}
+
+	result := SimulateTransactionResult{
+		Slot: resp.Context.Slot,
+		Logs: resp.Value.Logs,
+		Err:  DecodeTransactionError(resp.Value.Err, tx),
+	}
+	if resp.Value.UnitsConsumed != nil {
+		result.UnitsConsumed = *resp.Value.UnitsConsumed
+	}
+
+	if len(opts.Accounts) > 0 {
+		result.Accounts = make([]AccountDiff, len(opts.Accounts))
+		for i, addr := range opts.Accounts {
+			result.Accounts[i].Address = addr
+			// an existing account holds lamports, the system account is owned by the zero public key
+			if i < len(pre) && pre[i].Lamports > 0 {
+				result.Accounts[i].Pre = &pre[i]
+			}
+			if i < len(resp.Value.Accounts) && resp.Value.Accounts[i] != nil {
+				post, err := resp.Value.Accounts[i].accountInfo()
+				if err != nil {
+					return SimulateTransactionResult{}, utils.StackErrors(ErrSimulateTransaction, err)
+				}
+				result.Accounts[i].Post = &post
+			}
+		}
+	}
+
+	return result, nil
+}
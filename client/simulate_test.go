@@ -0,0 +1,108 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/program/system"
+	sdktypes "github.com/EntySquare/solana-go-sdk/types"
+	"github.com/EntySquare/solana/client"
+	"github.com/EntySquare/solana/tests/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newSimulatedTransfer builds the transfer from the payer stored by the fake server.
+func newSimulatedTransfer(t *testing.T, c *client.Client, payer sdktypes.Account, recipient common.PublicKey) string {
+	txSource, err := c.NewTransaction(context.Background(), client.NewTransactionParams{
+		FeePayer: payer.PublicKey,
+		Instructions: []sdktypes.Instruction{
+			system.Transfer(system.TransferParam{From: payer.PublicKey, To: recipient, Amount: 4000}),
+		},
+		Signers: []sdktypes.Account{payer},
+	})
+	require.NoError(t, err)
+	return txSource
+}
+
+func TestSimulateTransaction(t *testing.T) {
+	ctx := context.Background()
+	fake := mock.NewRPCServer()
+	defer fake.Close()
+
+	payer := sdktypes.NewAccount()
+	recipient := sdktypes.NewAccount().PublicKey
+	fake.SetBalance(payer.PublicKey.ToBase58(), 10000)
+	fake.SetSimulation(mock.Simulation{
+		Logs:          []string{"Program 11111111111111111111111111111111 invoke [1]", "Program 11111111111111111111111111111111 success"},
+		UnitsConsumed: 150,
+		Accounts: map[string]mock.RPCAccount{
+			payer.PublicKey.ToBase58(): {Lamports: 1000, Owner: common.SystemProgramID},
+			recipient.ToBase58():       {Lamports: 4000, Owner: common.SystemProgramID},
+		},
+	})
+
+	c := client.New(client.SetSolanaEndpoint(fake.URL))
+	result, err := c.SimulateTransaction(ctx, newSimulatedTransfer(t, c, payer, recipient), client.SimulateTransactionOptions{
+		ReplaceRecentBlockhash: true,
+		Accounts:               []string{payer.PublicKey.ToBase58(), recipient.ToBase58()},
+	})
+	require.NoError(t, err)
+	require.Nil(t, result.Err)
+	require.EqualValues(t, mock.DefaultSlot, result.Slot)
+	require.Equal(t, []string{"Program 11111111111111111111111111111111 invoke [1]", "Program 11111111111111111111111111111111 success"}, result.Logs)
+	require.EqualValues(t, 150, result.UnitsConsumed)
+
+	require.Len(t, result.Accounts, 2)
+	require.Equal(t, payer.PublicKey.ToBase58(), result.Accounts[0].Address)
+	require.NotNil(t, result.Accounts[0].Pre)
+	require.EqualValues(t, 10000, result.Accounts[0].Pre.Lamports)
+	require.EqualValues(t, 1000, result.Accounts[0].Post.Lamports)
+	require.EqualValues(t, -9000, result.Accounts[0].LamportsDelta())
+	// the recipient is created by the transaction
+	require.Nil(t, result.Accounts[1].Pre)
+	require.EqualValues(t, 4000, result.Accounts[1].Post.Lamports)
+	require.EqualValues(t, 4000, result.Accounts[1].LamportsDelta())
+
+	requests := fake.RequestsOf("simulateTransaction")
+	require.Len(t, requests, 1)
+	var cfg map[string]any
+	require.NoError(t, json.Unmarshal(requests[0].Params[1], &cfg))
+	require.Equal(t, true, cfg["replaceRecentBlockhash"])
+	require.Equal(t, false, cfg["sigVerify"])
+}
+
+func TestSimulateTransaction_Failed(t *testing.T) {
+	ctx := context.Background()
+	fake := mock.NewRPCServer()
+	defer fake.Close()
+
+	payer := sdktypes.NewAccount()
+	fake.SetBalance(payer.PublicKey.ToBase58(), 10000)
+	fake.SetSimulation(mock.Simulation{
+		Err:           map[string]any{"InstructionError": []any{0, map[string]any{"Custom": 1}}},
+		Logs:          []string{"Transfer: insufficient lamports 10000, need 40000"},
+		UnitsConsumed: 150,
+	})
+
+	c := client.New(client.SetSolanaEndpoint(fake.URL))
+	txSource := newSimulatedTransfer(t, c, payer, sdktypes.NewAccount().PublicKey)
+
+	// the failed transaction is not an error of the simulation
+	result, err := c.SimulateTransaction(ctx, txSource, client.SimulateTransactionOptions{SigVerify: true})
+	require.NoError(t, err)
+	require.NotNil(t, result.Err)
+	require.ErrorIs(t, result.Err, client.ErrTransactionFailed)
+	require.ErrorIs(t, result.Err, client.ErrCustomProgramError)
+	require.Equal(t, []string{"Transfer: insufficient lamports 10000, need 40000"}, result.Logs)
+	require.EqualValues(t, 150, result.UnitsConsumed)
+	require.Empty(t, result.Accounts)
+
+	_, err = c.SimulateTransaction(ctx, txSource, client.SimulateTransactionOptions{SigVerify: true, ReplaceRecentBlockhash: true})
+	require.ErrorIs(t, err, client.ErrSimulateTransaction)
+
+	fake.SetError("simulateTransaction", mock.RPCErrorInvalidParams, "invalid transaction")
+	_, err = c.SimulateTransaction(ctx, txSource, client.SimulateTransactionOptions{})
+	require.ErrorIs(t, err, client.ErrSimulateTransaction)
+}
@@ -8,9 +8,11 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/EntySquare/solana-go-sdk/client"
 	"github.com/EntySquare/solana-go-sdk/common"
 	metaplex_token_metadata "github.com/EntySquare/solana-go-sdk/program/metaplex/token_metadata"
 	"github.com/EntySquare/solana-go-sdk/program/token"
+	commonx "github.com/EntySquare/solana/common"
 	"github.com/EntySquare/solana/metadata"
 	"github.com/EntySquare/solana/token_metadata"
 	"github.com/EntySquare/solana/types"
@@ -22,7 +24,12 @@ import (
 // base58AtaAddr is the base58 encoded address of the associated token account.
 // The function returns the token account information or an error.
 func (c *Client) GetTokenAccountInfo(ctx context.Context, base58AtaAddr string) (token.TokenAccount, error) {
-	ta, err := c.rpcClient.GetTokenAccount(ctx, base58AtaAddr)
+	accountInfo, err := c.getAccountInfo(ctx, base58AtaAddr)
+	if err != nil {
+		return token.TokenAccount{}, utils.StackErrors(ErrGetTokenAccount, err)
+	}
+
+	ta, err := unpackTokenAccount(accountInfo.Data, accountInfo.Owner)
 	if err != nil {
 		return token.TokenAccount{}, utils.StackErrors(ErrGetTokenAccount, err)
 	}
@@ -31,18 +38,44 @@ func (c *Client) GetTokenAccountInfo(ctx context.Context, base58AtaAddr string)
 }
 
 // GetMintInfo returns the token mint information for a given mint address.
+// The mint is cached if the client has a cache.
 func (c *Client) GetMintInfo(ctx context.Context, base58MintAddr string) (token.MintAccount, error) {
-	accInfo, err := c.rpcClient.GetAccountInfo(ctx, base58MintAddr)
-	if err != nil {
-		return token.MintAccount{}, utils.StackErrors(ErrGetMintInfo, err)
-	}
+	key := CacheKey{Kind: cacheKindMint, Account: base58MintAddr, Commitment: c.Commitment(ctx)}
+	return cached(c, key, func() (token.MintAccount, []string, error) {
+		accInfo, err := c.getAccountInfo(ctx, base58MintAddr)
+		if err != nil {
+			return token.MintAccount{}, nil, utils.StackErrors(ErrGetMintInfo, err)
+		}
 
-	mintInfo, err := token.MintAccountFromData(accInfo.Data)
-	if err != nil {
-		return token.MintAccount{}, utils.StackErrors(ErrGetMintInfo, err)
-	}
+		mintInfo, err := unpackMint(accInfo.Data, accInfo.Owner)
+		if err != nil {
+			return token.MintAccount{}, nil, utils.StackErrors(ErrGetMintInfo, err)
+		}
+
+		return mintInfo, nil, nil
+	})
+}
+
+// GetTokenProgramID returns the token program owning the given mint or token account:
+// the SPL Token or the Token-2022 program.
+// The program is cached if the client has a cache.
+func (c *Client) GetTokenProgramID(ctx context.Context, base58Addr string) (common.PublicKey, error) {
+	key := CacheKey{Kind: cacheKindTokenProgram, Account: base58Addr, Commitment: c.Commitment(ctx)}
+	return cached(c, key, func() (common.PublicKey, []string, error) {
+		accInfo, err := c.getAccountInfo(ctx, base58Addr)
+		if err != nil {
+			return common.PublicKey{}, nil, utils.StackErrors(ErrGetTokenProgram, err)
+		}
+		if !commonx.IsTokenProgram(accInfo.Owner) {
+			return common.PublicKey{}, nil, utils.StackErrors(
+				ErrGetTokenProgram,
+				ErrUnknownTokenProgram,
+				fmt.Errorf("account %s is owned by %s", base58Addr, accInfo.Owner.ToBase58()),
+			)
+		}
 
-	return mintInfo, nil
+		return accInfo.Owner, nil, nil
+	})
 }
 
 // GetTokenSupply returns the token supply for a given mint address.
@@ -50,7 +83,9 @@ func (c *Client) GetMintInfo(ctx context.Context, base58MintAddr string) (token.
 // base58MintAddr is the base58 encoded address of the token mint.
 // The function returns the token supply and decimals or an error.
 func (c *Client) GetTokenSupply(ctx context.Context, base58MintAddr string) (types.TokenAmount, error) {
-	result, err := c.rpcClient.GetTokenSupply(ctx, base58MintAddr)
+	result, err := c.rpcClient.GetTokenSupplyWithConfig(ctx, base58MintAddr, client.GetTokenSupplyConfig{
+		Commitment: c.Commitment(ctx),
+	})
 	if err != nil {
 		return types.TokenAmount{}, utils.StackErrors(ErrGetTokenSupply, err)
 	}
@@ -84,6 +119,7 @@ func (c *Client) GetMasterEditionSupply(ctx context.Context, masterMint common.P
 }
 
 // GetTokenMetadata returns the metadata of a token
+// The metadata is cached if the client has a cache, it is invalidated with the metadata and edition accounts.
 func (c *Client) GetTokenMetadata(ctx context.Context, base58MintAddr string) (*token_metadata.Metadata, error) {
 	if base58MintAddr == "" {
 		return nil, utils.StackErrors(
@@ -99,49 +135,106 @@ func (c *Client) GetTokenMetadata(ctx context.Context, base58MintAddr string) (*
 		return nil, utils.StackErrors(ErrGetTokenMetadata, err)
 	}
 
-	metadataAccountInfo, err := c.rpcClient.GetAccountInfo(ctx, metadataAccount.ToBase58())
+	key := CacheKey{Kind: cacheKindTokenMetadata, Account: metadataAccount.ToBase58(), Commitment: c.Commitment(ctx)}
+	metadata, err := cached(c, key, func() (*token_metadata.Metadata, []string, error) {
+		return c.getTokenMetadata(ctx, mintPubkey, metadataAccount)
+	})
 	if err != nil {
-		return nil, utils.StackErrors(ErrGetTokenMetadata, err)
+		return nil, err
 	}
 
-	// utils.PrettyPrint(metadataAccountInfo)
+	// the cached value is shared
+	return copyTokenMetadata(metadata), nil
+}
+
+// copyTokenMetadata returns the deep copy of the metadata.
+func copyTokenMetadata(m *token_metadata.Metadata) *token_metadata.Metadata {
+	result := *m
+	if m.EditionNonce != nil {
+		nonce := *m.EditionNonce
+		result.EditionNonce = &nonce
+	}
+	if m.Collection != nil {
+		collection := *m.Collection
+		result.Collection = &collection
+	}
+	if m.Uses != nil {
+		uses := *m.Uses
+		result.Uses = &uses
+	}
+	if m.Edition != nil {
+		edition := *m.Edition
+		result.Edition = &edition
+	}
+	if m.Creators != nil {
+		result.Creators = append([]token_metadata.Creator(nil), m.Creators...)
+	}
+	if m.Data != nil {
+		// the off-chain metadata is decoded from JSON, so the round trip copies its nested values
+		data := *m.Data
+		if b, err := m.Data.ToJSON(); err == nil {
+			if decoded, err := metadata.MetadataFromJSON(b); err == nil {
+				data = *decoded
+			}
+		}
+		result.Data = &data
+	}
+
+	return &result
+}
+
+// getTokenMetadata loads the metadata of a token.
+// Returns the metadata and the edition accounts it depends on or an error.
+func (c *Client) getTokenMetadata(ctx context.Context, mintPubkey, metadataAccount common.PublicKey) (*token_metadata.Metadata, []string, error) {
+	metadataAccountInfo, err := c.getAccountInfo(ctx, metadataAccount.ToBase58())
+	if err != nil {
+		return nil, nil, utils.StackErrors(ErrGetTokenMetadata, err)
+	}
 
 	if metadataAccountInfo.Data == nil {
-		return nil, utils.StackErrors(
+		return nil, nil, utils.StackErrors(
 			ErrGetTokenMetadata,
 			errors.New("no metadata found"),
 		)
 	}
 	metadata, err := token_metadata.DeserializeMetadata(metadataAccountInfo.Data)
 	if err != nil {
-		return nil, utils.StackErrors(ErrGetTokenMetadata, err)
+		return nil, nil, utils.StackErrors(ErrGetTokenMetadata, err)
 	}
 
+	var dependsOn []string
 	if metadata.TokenStandard == token_metadata.TokenStandardNonFungible.String() ||
 		metadata.TokenStandard == token_metadata.TokenStandardNonFungibleEdition.String() {
 
 		editionPubkey, err := token_metadata.DeriveEditionPubkey(mintPubkey)
 		if err != nil {
-			return nil, utils.StackErrors(ErrGetTokenMetadata, err)
+			return nil, nil, utils.StackErrors(ErrGetTokenMetadata, err)
 		}
+		dependsOn = append(dependsOn, editionPubkey.ToBase58())
 
-		editionAccountInfo, err := c.rpcClient.GetAccountInfo(ctx, editionPubkey.ToBase58())
+		editionAccountInfo, err := c.getAccountInfo(ctx, editionPubkey.ToBase58())
 		if err != nil {
-			return nil, utils.StackErrors(ErrGetTokenMetadata, err)
+			return nil, nil, utils.StackErrors(ErrGetTokenMetadata, err)
 		}
 
-		edition, err := token_metadata.DeserializeEdition(editionAccountInfo.Data, c.rpcClient.GetAccountInfo)
+		// the supply of a print edition is read from the parent master edition
+		getParent := func(_ context.Context, base58Addr string) (client.AccountInfo, error) {
+			dependsOn = append(dependsOn, base58Addr)
+			return c.getAccountInfo(ctx, base58Addr)
+		}
+		edition, err := token_metadata.DeserializeEdition(editionAccountInfo.Data, getParent)
 		if err != nil {
-			return nil, utils.StackErrors(ErrGetTokenMetadata, err)
+			return nil, nil, utils.StackErrors(ErrGetTokenMetadata, err)
 		}
 
 		metadata.Edition = edition
 	}
 
-	return metadata, nil
+	return metadata, dependsOn, nil
 }
 
 // GetMasterEditionInfo returns the master edition info of a token
+// The edition is cached if the client has a cache.
 func (c *Client) GetMasterEditionInfo(ctx context.Context, base58MintAddr string) (*token_metadata.Edition, error) {
 	mint := common.PublicKeyFromString(base58MintAddr)
 	masterEditionPubKey, err := token_metadata.DeriveEditionPubkey(mint)
@@ -152,23 +245,33 @@ func (c *Client) GetMasterEditionInfo(ctx context.Context, base58MintAddr string
 		)
 	}
 
-	masterEdition, err := c.rpcClient.GetAccountInfo(ctx, masterEditionPubKey.String())
-	if err != nil {
-		return nil, utils.StackErrors(
-			ErrGetMasterEditionInfo,
-			err,
-		)
-	}
+	key := CacheKey{Kind: cacheKindMasterEdition, Account: masterEditionPubKey.String(), Commitment: c.Commitment(ctx)}
+	edition, err := cached(c, key, func() (*token_metadata.Edition, []string, error) {
+		masterEdition, err := c.getAccountInfo(ctx, masterEditionPubKey.String())
+		if err != nil {
+			return nil, nil, utils.StackErrors(
+				ErrGetMasterEditionInfo,
+				err,
+			)
+		}
 
-	edition, err := token_metadata.DeserializeMasterEdition(masterEdition.Data)
+		edition, err := token_metadata.DeserializeMasterEdition(masterEdition.Data)
+		if err != nil {
+			return nil, nil, utils.StackErrors(
+				ErrGetMasterEditionInfo,
+				err,
+			)
+		}
+
+		return edition, nil, nil
+	})
 	if err != nil {
-		return nil, utils.StackErrors(
-			ErrGetMasterEditionInfo,
-			err,
-		)
+		return nil, err
 	}
 
-	return edition, nil
+	// the cached value is shared
+	result := *edition
+	return &result, nil
 }
 
 // GetEditionInfo returns the edition info of a token
@@ -182,7 +285,7 @@ func (c *Client) GetEditionInfo(ctx context.Context, base58MintAddr string) (*to
 		)
 	}
 
-	editionData, err := c.rpcClient.GetAccountInfo(ctx, editionPubKey.String())
+	editionData, err := c.getAccountInfo(ctx, editionPubKey.String())
 	if err != nil {
 		return nil, utils.StackErrors(
 			ErrGetEditionInfo,
@@ -190,7 +293,10 @@ func (c *Client) GetEditionInfo(ctx context.Context, base58MintAddr string) (*to
 		)
 	}
 
-	edition, err := token_metadata.DeserializeEdition(editionData.Data, c.rpcClient.GetAccountInfo)
+	getParent := func(_ context.Context, base58Addr string) (client.AccountInfo, error) {
+		return c.getAccountInfo(ctx, base58Addr)
+	}
+	edition, err := token_metadata.DeserializeEdition(editionData.Data, getParent)
 	if err != nil {
 		return nil, utils.StackErrors(
 			ErrGetEditionInfo,
@@ -236,7 +342,7 @@ func (c *Client) GetFungibleTokenMetadata(ctx context.Context, base58MintAddr st
 		return result, fmt.Errorf("failed to get token metadata account: %w", err)
 	}
 
-	accountInfo, err := c.rpcClient.GetAccountInfo(ctx, metadataAccount.ToBase58())
+	accountInfo, err := c.getAccountInfo(ctx, metadataAccount.ToBase58())
 	if err != nil {
 		return result, fmt.Errorf("failed to get account info: %w", err)
 	}
@@ -270,30 +376,18 @@ func (c *Client) GetFungibleTokenMetadata(ctx context.Context, base58MintAddr st
 // This is a temporary solution to support the deprecated metadata format.
 // Returns the token metadata or an error.
 // Works only with mainnet.
-func (c *Client) getDeprecatedTokenMetadata(_ context.Context, base58MintAddr string) (*metadata.Metadata, error) {
+func (c *Client) getDeprecatedTokenMetadata(ctx context.Context, base58MintAddr string) (*metadata.Metadata, error) {
 	if c.tokenListPath == "" || base58MintAddr == "" {
 		return nil, fmt.Errorf("failed to get token metadata: token list path or mint address is empty")
 	}
 
-	resp, err := http.Get(c.tokenListPath)
+	tokens, err := c.getTokenList(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to download token list from uri: %w", err)
-	}
-	defer resp.Body.Close()
-
-	var tokenList metadata.TokenList
-	if err := json.NewDecoder(resp.Body).Decode(&tokenList); err != nil {
-		return nil, fmt.Errorf("failed to decode token list from uri: %w", err)
+		return nil, err
 	}
 
 	// Find token metadata.
-	var tokenMeta metadata.TokenListToken
-	for _, token := range tokenList.Tokens {
-		if token.Address == base58MintAddr && token.ChainID == metadata.ChainIdMainnet {
-			tokenMeta = token
-			break
-		}
-	}
+	tokenMeta := tokens[base58MintAddr]
 
 	result := metadata.Metadata{
 		Name:   tokenMeta.Name,
@@ -316,3 +410,52 @@ func (c *Client) getDeprecatedTokenMetadata(_ context.Context, base58MintAddr st
 
 	return &result, nil
 }
+
+// getTokenList downloads the deprecated token list and returns the mainnet tokens by the mint address.
+// The token list is cached if the client has a cache.
+func (c *Client) getTokenList(_ context.Context) (map[string]metadata.TokenListToken, error) {
+	return cached(c, CacheKey{Kind: cacheKindTokenList, Account: c.tokenListPath}, func() (map[string]metadata.TokenListToken, []string, error) {
+		resp, err := http.Get(c.tokenListPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to download token list from uri: %w", err)
+		}
+		defer resp.Body.Close()
+
+		var tokenList metadata.TokenList
+		if err := json.NewDecoder(resp.Body).Decode(&tokenList); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode token list from uri: %w", err)
+		}
+
+		tokens := make(map[string]metadata.TokenListToken, len(tokenList.Tokens))
+		for _, token := range tokenList.Tokens {
+			if _, ok := tokens[token.Address]; !ok && token.ChainID == metadata.ChainIdMainnet {
+				tokens[token.Address] = token
+			}
+		}
+
+		return tokens, nil, nil
+	})
+}
+
+// unpackMint decodes the mint of the SPL Token or the Token-2022 program.
+// The Token-2022 extensions follow the SPL Token layout and are skipped.
+func unpackMint(data []byte, owner common.PublicKey) (token.MintAccount, error) {
+	if owner == commonx.Token2022ProgramID && len(data) > token.MintAccountSize {
+		data = data[:token.MintAccountSize]
+	}
+
+	return token.MintAccountFromData(data)
+}
+
+// unpackTokenAccount decodes the token account of the SPL Token or the Token-2022 program.
+// The Token-2022 extensions follow the SPL Token layout and are skipped.
+func unpackTokenAccount(data []byte, owner common.PublicKey) (token.TokenAccount, error) {
+	if !commonx.IsTokenProgram(owner) {
+		return token.TokenAccount{}, token.ErrInvalidAccountOwner
+	}
+	if owner == commonx.Token2022ProgramID && len(data) > token.TokenAccountSize {
+		data = data[:token.TokenAccountSize]
+	}
+
+	return token.TokenAccountFromData(data)
+}
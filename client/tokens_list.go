@@ -22,31 +22,52 @@ func (c *Client) GetNonFungibleTokensList(ctx context.Context, walletAddr string
 	return c.getTokensList(ctx, walletAddr, false)
 }
 
-// GetFungibleTokensList gets the list of fungible tokens for the given wallet address.
+// getTokensList gets the list of fungible or non-fungible tokens for the given wallet address.
+// The token accounts of the SPL Token program are followed by the Token-2022 ones.
 func (c *Client) getTokensList(ctx context.Context, walletAddr string, fungible bool) ([]types.TokenAccount, error) {
 	if err := commonx.ValidateSolanaWalletAddr(walletAddr); err != nil {
 		return nil, err
 	}
 
+	var tokenAccounts []types.TokenAccount
+	for _, programID := range []common.PublicKey{common.TokenProgramID, commonx.Token2022ProgramID} {
+		accounts, err := c.getTokenAccountsByOwner(ctx, walletAddr, programID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, acc := range accounts {
+			if !acc.IsEmpty() && acc.IsFungibleToken() == fungible {
+				tokenAccounts = append(tokenAccounts, acc)
+			}
+		}
+	}
+
+	return tokenAccounts, nil
+}
+
+// getTokenAccountsByOwner gets the token accounts of the token program for the given wallet address.
+func (c *Client) getTokenAccountsByOwner(ctx context.Context, walletAddr string, programID common.PublicKey) ([]types.TokenAccount, error) {
 	getTokenAccountsByOwnerResponse, err := c.rpcClient.RpcClient.GetTokenAccountsByOwnerWithConfig(
 		ctx,
 		walletAddr,
 		rpc.GetTokenAccountsByOwnerConfigFilter{
-			ProgramId: common.TokenProgramID.ToBase58(),
+			ProgramId: programID.ToBase58(),
 		},
 		rpc.GetTokenAccountsByOwnerConfig{
-			Encoding: rpc.AccountEncodingJsonParsed,
+			Encoding:   rpc.AccountEncodingJsonParsed,
+			Commitment: c.Commitment(ctx),
 		},
 	)
 	if err != nil {
-		return nil, fmt.Errorf("could not get fungible tokens list: %w", err)
+		return nil, fmt.Errorf("could not get tokens list: %w", err)
 	}
 
 	if getTokenAccountsByOwnerResponse.Error != nil {
-		return nil, fmt.Errorf("could not get fungible tokens list: %s", getTokenAccountsByOwnerResponse.Error.Message)
+		return nil, fmt.Errorf("could not get tokens list: %s", getTokenAccountsByOwnerResponse.Error.Message)
 	}
 
-	var tokenAccounts []types.TokenAccount
+	tokenAccounts := make([]types.TokenAccount, 0, len(getTokenAccountsByOwnerResponse.Result.Value))
 	for _, v := range getTokenAccountsByOwnerResponse.Result.Value {
 		b, err := json.Marshal(v)
 		if err != nil {
@@ -58,9 +79,7 @@ func (c *Client) getTokensList(ctx context.Context, walletAddr string, fungible
 			return nil, fmt.Errorf("NewTokenAccount: %w", err)
 		}
 
-		if !acc.IsEmpty() && acc.IsFungibleToken() == fungible {
-			tokenAccounts = append(tokenAccounts, acc)
-		}
+		tokenAccounts = append(tokenAccounts, acc)
 	}
 
 	return tokenAccounts, nil
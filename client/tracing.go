@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+)
+
+type (
+	// Tracer starts the spans of the RPC calls.
+	// It mirrors the OpenTelemetry tracer, so an OpenTelemetry adapter is a thin wrapper.
+	Tracer interface {
+		// Start starts the span as a child of the span of the context, if any.
+		// Returns the context carrying the new span and the span.
+		Start(ctx context.Context, spanName string) (context.Context, Span)
+	}
+
+	// Span is the span of a single RPC call.
+	Span interface {
+		SetAttribute(key string, value any)
+		RecordError(err error)
+		End()
+	}
+)
+
+// TracingInterceptor returns the interceptor which wraps every RPC call in a span.
+// The span is a child of the span of the caller's context and is passed to the inner interceptors.
+func TracingInterceptor(tracer Tracer) RPCInterceptor {
+	return func(ctx context.Context, call *RPCCall, invoke RPCInvoker) error {
+		ctx, span := tracer.Start(ctx, "solana.rpc/"+call.Method)
+		defer span.End()
+
+		span.SetAttribute("rpc.system", "jsonrpc")
+		span.SetAttribute("rpc.method", call.Method)
+		if call.BatchSize > 1 {
+			span.SetAttribute("rpc.batch_size", call.BatchSize)
+		}
+
+		err := invoke(ctx, call)
+
+		span.SetAttribute("server.address", call.Endpoint)
+		span.SetAttribute("http.response.status_code", call.StatusCode)
+		span.SetAttribute("rpc.retries", call.Retries)
+		if err != nil {
+			span.SetAttribute("error.type", string(call.ErrorClass))
+			span.RecordError(err)
+		}
+
+		return err
+	}
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -17,15 +18,21 @@ import (
 
 // NewTransactionParams is the params for NewTransaction function.
 type NewTransactionParams struct {
-	FeePayer     common.PublicKey       // transaction fee payer
-	Instructions []sdktypes.Instruction // transaction instructions
-	Signers      []sdktypes.Account     // transaction signers
+	FeePayer            common.PublicKey                     // transaction fee payer
+	Instructions        []sdktypes.Instruction               // transaction instructions
+	Signers             []sdktypes.Account                   // transaction signers
+	AddressLookupTables []sdktypes.AddressLookupTableAccount // optional; if set, the transaction is compiled into a v0 message
 }
 
 // NewTransaction creates a new transaction.
+// If address lookup tables are provided, the transaction is compiled into a v0 message.
 // Returns the transaction or an error.
 func (c *Client) NewTransaction(ctx context.Context, params NewTransactionParams) (string, error) {
-	latestBlockhash, err := c.rpcClient.GetLatestBlockhash(ctx)
+	if err := validateLookupTables(params.AddressLookupTables); err != nil {
+		return "", utils.StackErrors(ErrNewTransaction, err)
+	}
+
+	latestBlockhash, err := c.getLatestBlockhash(ctx)
 	if err != nil {
 		return "", utils.StackErrors(
 			ErrNewTransaction,
@@ -36,9 +43,10 @@ func (c *Client) NewTransaction(ctx context.Context, params NewTransactionParams
 
 	tx, err := sdktypes.NewTransaction(sdktypes.NewTransactionParam{
 		Message: sdktypes.NewMessage(sdktypes.NewMessageParam{
-			FeePayer:        params.FeePayer,
-			RecentBlockhash: latestBlockhash.Blockhash,
-			Instructions:    params.Instructions,
+			FeePayer:                   params.FeePayer,
+			RecentBlockhash:            latestBlockhash.Blockhash,
+			Instructions:               params.Instructions,
+			AddressLookupTableAccounts: params.AddressLookupTables,
 		}),
 		Signers: params.Signers,
 	})
@@ -46,13 +54,9 @@ func (c *Client) NewTransaction(ctx context.Context, params NewTransactionParams
 		return "", utils.StackErrors(ErrNewTransaction, err)
 	}
 
-	txb, err := utils.EncodeTransaction(tx)
+	txb, err := encodeTransaction(tx)
 	if err != nil {
-		return "", utils.StackErrors(
-			ErrNewTransaction,
-			ErrSerializeTransaction,
-			err,
-		)
+		return "", utils.StackErrors(ErrNewTransaction, err)
 	}
 
 	return txb, nil
@@ -60,16 +64,21 @@ func (c *Client) NewTransaction(ctx context.Context, params NewTransactionParams
 
 // NewDurableTransactionParams are the parameters for NewDurableTransaction function.
 type NewDurableTransactionParams struct {
-	FeePayer     *common.PublicKey      // optional; if not provided, the fee payer will be the durable nonce
-	NonceAuth    common.PublicKey       // required; the nonce authority
-	DurableNonce common.PublicKey       // required; the durable nonce
-	Instructions []sdktypes.Instruction // required; the transaction instructions
-	Signers      []sdktypes.Account     // transaction signers
+	FeePayer            *common.PublicKey                    // optional; if not provided, the fee payer will be the durable nonce
+	NonceAuth           common.PublicKey                     // required; the nonce authority
+	DurableNonce        common.PublicKey                     // required; the durable nonce
+	Instructions        []sdktypes.Instruction               // required; the transaction instructions
+	Signers             []sdktypes.Account                   // transaction signers
+	AddressLookupTables []sdktypes.AddressLookupTableAccount // optional; if set, the transaction is compiled into a v0 message
 }
 
 // NewDurableTransaction creates a new durable transaction.
 // Returns the serialized transaction or an error.
 func (c *Client) NewDurableTransaction(ctx context.Context, params NewDurableTransactionParams) (string, error) {
+	if err := validateLookupTables(params.AddressLookupTables); err != nil {
+		return "", utils.StackErrors(ErrNewDurableTransaction, err)
+	}
+
 	nonce, err := c.rpcClient.GetNonceFromNonceAccount(ctx, params.DurableNonce.ToBase58())
 	if err != nil {
 		return "", utils.StackErrors(
@@ -90,6 +99,19 @@ func (c *Client) NewDurableTransaction(ctx context.Context, params NewDurableTra
 		params.FeePayer = &params.NonceAuth
 	}
 
+	// the runtime reads the nonce account from the static account keys only
+	for _, table := range params.AddressLookupTables {
+		for _, addr := range table.Addresses {
+			if addr == params.DurableNonce {
+				return "", utils.StackErrors(
+					ErrNewDurableTransaction,
+					ErrInvalidLookupTable,
+					fmt.Errorf("durable nonce account must not be resolved through lookup table %s", table.Key.ToBase58()),
+				)
+			}
+		}
+	}
+
 	instr := []sdktypes.Instruction{
 		system.AdvanceNonceAccount(system.AdvanceNonceAccountParam{
 			Nonce: params.DurableNonce,
@@ -100,9 +122,10 @@ func (c *Client) NewDurableTransaction(ctx context.Context, params NewDurableTra
 
 	tx, err := sdktypes.NewTransaction(sdktypes.NewTransactionParam{
 		Message: sdktypes.NewMessage(sdktypes.NewMessageParam{
-			FeePayer:        *params.FeePayer,
-			RecentBlockhash: nonce,
-			Instructions:    instr,
+			FeePayer:                   *params.FeePayer,
+			RecentBlockhash:            nonce,
+			Instructions:               instr,
+			AddressLookupTableAccounts: params.AddressLookupTables,
 		}),
 		Signers: params.Signers,
 	})
@@ -114,13 +137,9 @@ func (c *Client) NewDurableTransaction(ctx context.Context, params NewDurableTra
 		)
 	}
 
-	txb, err := utils.EncodeTransaction(tx)
+	txb, err := encodeTransaction(tx)
 	if err != nil {
-		return "", utils.StackErrors(
-			ErrNewDurableTransaction,
-			ErrSerializeTransaction,
-			err,
-		)
+		return "", utils.StackErrors(ErrNewDurableTransaction, err)
 	}
 
 	return txb, nil
@@ -182,22 +201,32 @@ func (c *Client) SendTransaction(ctx context.Context, txSource string, i ...uint
 
 	txhash, err := c.rpcClient.SendTransaction(ctx, tx)
 	if err != nil {
-		if strings.Contains(err.Error(), "without insufficient funds for rent") {
+		// the transport errors and the JSON-RPC errors without data have no transaction error
+		txErr := decodeRPCError(err, tx)
+
+		if (txErr != nil && errors.Is(txErr, ErrInsufficientFundsForRent)) ||
+			strings.Contains(err.Error(), "without insufficient funds for rent") {
 			return "", utils.StackErrors(ErrSendTransaction, ErrWithoutInsufficientFound, err)
 		}
 
 		// retry if blockhash not found
-		if strings.Contains(err.Error(), "BlockhashNotFound") && tryN < 3 {
+		if ((txErr != nil && errors.Is(txErr, ErrBlockhashNotFound)) || strings.Contains(err.Error(), "BlockhashNotFound")) && tryN < 3 {
 			return c.SendTransaction(ctx, txSource, tryN+1)
 		}
 
+		if txErr != nil {
+			return "", utils.StackErrors(ErrSendTransaction, txErr)
+		}
+
 		return "", utils.StackErrors(ErrSendTransaction, err)
 	}
+	c.trackSentTransaction(txhash, tx, c.invalidateWritableAccounts(ctx, tx))
 
 	return txhash, nil
 }
 
 // GetTransactionStatus gets the transaction status.
+// The status is success once the transaction reached the commitment of the context and in progress before.
 // Returns the transaction status or an error.
 func (c *Client) GetTransactionStatus(ctx context.Context, txhash string) (types.TransactionStatus, error) {
 	status, err := c.rpcClient.GetSignatureStatus(ctx, txhash)
@@ -208,7 +237,7 @@ func (c *Client) GetTransactionStatus(ctx context.Context, txhash string) (types
 		return types.TransactionStatusUnknown, nil
 	}
 	if status.Err != nil {
-		return types.TransactionStatusFailure, fmt.Errorf("transaction failed: %v", status.Err)
+		return types.TransactionStatusFailure, c.decodeStatusError(ctx, txhash, status.Err)
 	}
 
 	result := types.TransactionStatusUnknown
@@ -216,7 +245,7 @@ func (c *Client) GetTransactionStatus(ctx context.Context, txhash string) (types
 		result = types.TransactionStatusInProgress
 	}
 	if status.ConfirmationStatus != nil {
-		result = types.ParseTransactionStatus(*status.ConfirmationStatus)
+		result = transactionStatus(*status.ConfirmationStatus, c.Commitment(ctx))
 	}
 
 	return result, nil
@@ -233,38 +262,8 @@ func (c *Client) GetMinimumBalanceForRentExemption(ctx context.Context, size uin
 	return mintAccountRent, nil
 }
 
-// WaitForTransactionConfirmed waits for a transaction to be confirmed.
-// Returns the transaction status or an error.
-func (c *Client) WaitForTransactionConfirmed(ctx context.Context, txhash string, maxDuration time.Duration) (types.TransactionStatus, error) {
-	tick := time.NewTicker(5 * time.Second)
-	defer tick.Stop()
-
-	if maxDuration == 0 {
-		maxDuration = 5 * time.Minute
-	}
-	ctx, cancel := context.WithTimeout(ctx, maxDuration)
-	defer cancel()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return types.TransactionStatusUnknown, utils.StackErrors(ErrWaitForTransaction, ErrContextDone)
-		case <-tick.C:
-			status, err := c.GetTransactionStatus(ctx, txhash)
-			if err != nil {
-				return types.TransactionStatusUnknown, utils.StackErrors(ErrWaitForTransaction, err)
-			}
-			if status == types.TransactionStatusInProgress || status == types.TransactionStatusUnknown {
-				continue
-			}
-			if status == types.TransactionStatusFailure || status == types.TransactionStatusSuccess {
-				return status, nil
-			}
-		}
-	}
-}
-
 // GetOldestTransactionForWallet returns the oldest transaction by the given base58 encoded public key.
+// The signature pages are paced by the getSignaturesForAddress budget of the rate limit, if it is set.
 // Returns the transaction or an error.
 func (c *Client) GetOldestTransactionForWallet(
 	ctx context.Context,
@@ -275,7 +274,7 @@ func (c *Client) GetOldestTransactionForWallet(
 	result, err := c.rpcClient.GetSignaturesForAddressWithConfig(ctx, base58Addr, client.GetSignaturesForAddressConfig{
 		Limit:      limit,
 		Before:     offsetTxSignature,
-		Commitment: rpc.CommitmentFinalized,
+		Commitment: c.historyCommitment(ctx),
 	})
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to get signatures for address: %s: %w", base58Addr, err)
@@ -286,7 +285,7 @@ func (c *Client) GetOldestTransactionForWallet(
 	} else if l < limit {
 		tx := result[l-1]
 		if tx.Err != nil {
-			return "", nil, fmt.Errorf("transaction failed: %v", tx.Err)
+			return "", nil, ParseTransactionError(tx.Err)
 		}
 		if tx.Signature == "" {
 			return "", nil, ErrNoTransactionsFound
@@ -309,7 +308,9 @@ func (c *Client) GetOldestTransactionForWallet(
 // GetTransaction returns the transaction by the given base58 encoded transaction signature.
 // Returns the transaction or an error.
 func (c *Client) GetTransaction(ctx context.Context, txSignature string) (*client.Transaction, error) {
-	tx, err := c.rpcClient.GetTransaction(ctx, txSignature)
+	tx, err := c.rpcClient.GetTransactionWithConfig(ctx, txSignature, client.GetTransactionConfig{
+		Commitment: c.historyCommitment(ctx),
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get transaction: %w", err)
 	}
@@ -317,30 +318,100 @@ func (c *Client) GetTransaction(ctx context.Context, txSignature string) (*clien
 		return nil, ErrTransactionNotFound
 	}
 	if tx.Meta.Err != nil {
-		return nil, fmt.Errorf("transaction failed: %v", tx.Meta.Err)
+		return nil, DecodeTransactionError(tx.Meta.Err, tx.Transaction)
 	}
 
 	return tx, nil
 }
 
-// ValidateTransactionByReference returns the transaction by the given reference.
-// Returns transaction signature or an error if the transaction is not found or the transaction failed.
+// decodeStatusError decodes the transaction error from the signature status.
+// The transaction is fetched to resolve the custom program error, if any.
+func (c *Client) decodeStatusError(ctx context.Context, txhash string, statusErr any) error {
+	txErr := ParseTransactionError(statusErr)
+	if txErr.CustomCode == nil {
+		return txErr
+	}
+
+	// the failed transaction may not be finalized yet
+	tx, err := c.rpcClient.GetTransactionWithConfig(ctx, txhash, client.GetTransactionConfig{
+		Commitment: rpc.CommitmentConfirmed,
+	})
+	if err != nil || tx == nil {
+		return txErr
+	}
+
+	return DecodeTransactionError(statusErr, tx.Transaction)
+}
+
+// ValidateTransactionByReference returns the payment transaction by the given reference.
+// The mint "", "SOL" or wrapped SOL means a SOL payment, the destination is the recipient wallet.
+// Returns transaction signature or an error if no transaction of the reference is the payment,
+// or the payment is duplicated; see ValidatePayment for the verdict.
 func (c *Client) ValidateTransactionByReference(ctx context.Context, reference, destination string, amount uint64, mint string) (string, error) {
-	txSign, tx, err := c.GetOldestTransactionForWallet(ctx, reference, "")
+	req := types.PaymentRequest{
+		Recipient: common.PublicKeyFromString(destination),
+		Amount:    amount,
+		Reference: common.PublicKeyFromString(reference),
+	}
+	if mint != "" && mint != "SOL" && mint != "So11111111111111111111111111111111111111112" {
+		splToken := common.PublicKeyFromString(mint)
+		req.SPLToken = &splToken
+	}
+
+	verdict, err := c.ValidatePayment(ctx, req)
 	if err != nil {
 		return "", fmt.Errorf("failed to validate transaction for reference %s: %w", reference, err)
 	}
 
-	if mint == "" || mint == "SOL" || mint == "So11111111111111111111111111111111111111112" {
-		if err := CheckSolTransferTransaction(tx.Meta, tx.Transaction, destination, amount); err != nil {
-			return "", fmt.Errorf("failed to validate transaction for reference %s: %w", reference, err)
+	switch verdict.Status {
+	case types.PaymentStatusValid:
+		return verdict.Signature, nil
+	case types.PaymentStatusNotFound:
+		return "", fmt.Errorf("failed to validate transaction for reference %s: %w", reference, ErrNoTransactionsFound)
+	case types.PaymentStatusDuplicate:
+		return "", fmt.Errorf("failed to validate transaction for reference %s: %w", reference, utils.StackErrors(
+			ErrPaymentDuplicate,
+			fmt.Errorf("payment %s, duplicates %s", verdict.Signature, strings.Join(verdict.Duplicates, ", ")),
+		))
+	default:
+		// the most recent rejection explains the latest attempt to pay
+		return "", fmt.Errorf("failed to validate transaction for reference %s: %w", reference, verdict.Candidates[len(verdict.Candidates)-1].Err)
+	}
+}
+
+// validateLookupTables checks that the given address lookup tables can be used to compile a v0 message.
+func validateLookupTables(tables []sdktypes.AddressLookupTableAccount) error {
+	for _, table := range tables {
+		if table.Key == (common.PublicKey{}) {
+			return utils.StackErrors(ErrInvalidLookupTable, fmt.Errorf("missing lookup table public key"))
+		}
+		if len(table.Addresses) == 0 {
+			return utils.StackErrors(ErrInvalidLookupTable, fmt.Errorf("lookup table %s has no addresses", table.Key.ToBase58()))
+		}
+		if uint(len(table.Addresses)) > types.LookupTableMaxAddresses {
+			return utils.StackErrors(ErrInvalidLookupTable, fmt.Errorf(
+				"lookup table %s has %d addresses, max is %d",
+				table.Key.ToBase58(), len(table.Addresses), types.LookupTableMaxAddresses,
+			))
 		}
-		return txSign, nil
 	}
 
-	if err := CheckTokenTransferTransaction(tx.Meta, tx.Transaction, mint, destination, amount); err != nil {
-		return "", fmt.Errorf("failed to validate transaction for reference %s: %w", reference, err)
+	return nil
+}
+
+// encodeTransaction serializes the transaction and checks that it fits into a single packet.
+// Returns the base64 encoded transaction or an error.
+func encodeTransaction(tx sdktypes.Transaction) (string, error) {
+	txb, err := tx.Serialize()
+	if err != nil {
+		return "", utils.StackErrors(ErrSerializeTransaction, err)
+	}
+	if len(txb) > types.MaxTransactionSize {
+		return "", utils.StackErrors(
+			ErrTransactionTooLarge,
+			fmt.Errorf("%d bytes, max is %d bytes", len(txb), types.MaxTransactionSize),
+		)
 	}
 
-	return txSign, nil
+	return utils.BytesToBase64(txb), nil
 }
@@ -0,0 +1,160 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/rpc"
+	sdktypes "github.com/EntySquare/solana-go-sdk/types"
+)
+
+// TransactionError is the decoded transaction error returned by the RPC node.
+// Works with errors.Is: the error matches ErrTransactionFailed, ErrInstructionFailed for instruction errors
+// and the named sentinel error of the transaction, instruction or program error if it is known.
+type TransactionError struct {
+	Name             string           // error name, e.g. "InstructionError", "InsufficientFundsForFee"
+	InstructionIndex int              // index of the failed instruction; -1 if the error is not related to an instruction
+	InstructionError string           // instruction error name, e.g. "Custom", "InvalidAccountData"
+	CustomCode       *uint32          // custom program error code
+	ProgramID        common.PublicKey // program of the failed instruction; zero if unknown
+	Raw              any              // raw error as returned by the RPC node
+
+	err error // named sentinel error
+}
+
+// Error returns the error message.
+// Implements the error interface.
+func (e *TransactionError) Error() string {
+	var msg string
+	switch {
+	case e.InstructionIndex < 0:
+		msg = fmt.Sprintf("transaction error: %s", e.Name)
+	case e.CustomCode != nil:
+		msg = fmt.Sprintf("instruction %d failed: custom program error: 0x%x", e.InstructionIndex, *e.CustomCode)
+	default:
+		msg = fmt.Sprintf("instruction %d failed: %s", e.InstructionIndex, e.InstructionError)
+	}
+
+	if e.err != nil {
+		msg = fmt.Sprintf("%s: %s", msg, e.err.Error())
+	}
+
+	return msg
+}
+
+// Unwrap returns the named sentinel error.
+// Implements the errors.Unwrap interface.
+func (e *TransactionError) Unwrap() error {
+	return e.err
+}
+
+// Is returns true if the target is ErrTransactionFailed,
+// or ErrInstructionFailed/ErrCustomProgramError for the related errors.
+func (e *TransactionError) Is(target error) bool {
+	switch target {
+	case ErrTransactionFailed:
+		return true
+	case ErrInstructionFailed:
+		return e.InstructionIndex >= 0
+	case ErrCustomProgramError:
+		return e.CustomCode != nil
+	}
+
+	return false
+}
+
+// ParseTransactionError decodes the transaction error from the RPC response,
+// e.g. {"InstructionError":[0,{"Custom":1}]} or "AccountNotFound".
+// Custom program errors are not resolved since the program is unknown, use DecodeTransactionError instead.
+// Returns nil if raw is nil.
+func ParseTransactionError(raw any) *TransactionError {
+	if raw == nil {
+		return nil
+	}
+
+	result := &TransactionError{InstructionIndex: -1, Raw: raw}
+
+	switch v := raw.(type) {
+	case string:
+		result.Name = v
+	case map[string]any:
+		for name, value := range v {
+			result.Name = name
+			if name != "InstructionError" {
+				break
+			}
+			args, ok := value.([]any)
+			if !ok || len(args) != 2 {
+				break
+			}
+			if idx, ok := args[0].(float64); ok {
+				result.InstructionIndex = int(idx)
+			}
+			switch ixErr := args[1].(type) {
+			case string:
+				result.InstructionError = ixErr
+			case map[string]any:
+				for ixErrName, ixErrValue := range ixErr {
+					result.InstructionError = ixErrName
+					if code, ok := ixErrValue.(float64); ok && ixErrName == "Custom" {
+						customCode := uint32(code)
+						result.CustomCode = &customCode
+					}
+				}
+			}
+		}
+	default:
+		b, _ := json.Marshal(raw)
+		result.Name = string(b)
+	}
+
+	if result.InstructionIndex < 0 {
+		result.err = transactionErrors[result.Name]
+	} else {
+		result.err = instructionErrors[result.InstructionError]
+	}
+
+	return result
+}
+
+// DecodeTransactionError decodes the transaction error from the RPC response
+// and resolves the custom program error using the program of the failed instruction in the given transaction.
+// Returns nil if raw is nil.
+func DecodeTransactionError(raw any, tx sdktypes.Transaction) *TransactionError {
+	result := ParseTransactionError(raw)
+	if result == nil || result.InstructionIndex < 0 {
+		return result
+	}
+
+	// program ids can not be loaded from the lookup tables, so the static account keys are enough
+	if result.InstructionIndex < len(tx.Message.Instructions) {
+		programIdx := tx.Message.Instructions[result.InstructionIndex].ProgramIDIndex
+		if programIdx < len(tx.Message.Accounts) {
+			result.ProgramID = tx.Message.Accounts[programIdx]
+		}
+	}
+
+	if result.CustomCode != nil {
+		result.err = ProgramError(result.ProgramID, *result.CustomCode)
+	}
+
+	return result
+}
+
+// decodeRPCError extracts the transaction error from the preflight failure returned by the RPC node.
+// Returns nil if the error does not contain a transaction error.
+func decodeRPCError(err error, tx sdktypes.Transaction) *TransactionError {
+	var rpcErr *rpc.JsonRpcError
+	if !errors.As(err, &rpcErr) {
+		return nil
+	}
+
+	data, ok := rpcErr.Data.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	return DecodeTransactionError(data["err"], tx)
+}
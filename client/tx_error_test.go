@@ -0,0 +1,161 @@
+package client_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/program/system"
+	"github.com/EntySquare/solana-go-sdk/program/token"
+	"github.com/EntySquare/solana-go-sdk/types"
+	"github.com/EntySquare/solana/client"
+	"github.com/EntySquare/solana/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTransactionError(t *testing.T) {
+	tests := []struct {
+		name             string
+		raw              string
+		wantName         string
+		wantIndex        int
+		wantInstrErr     string
+		wantCustomCode   *uint32
+		wantErrorMessage string
+	}{
+		{
+			name:             "transaction error",
+			raw:              `"AccountNotFound"`,
+			wantName:         "AccountNotFound",
+			wantIndex:        -1,
+			wantErrorMessage: "transaction error: AccountNotFound: attempt to debit an account but found no record of a prior credit",
+		},
+		{
+			name:             "instruction error",
+			raw:              `{"InstructionError":[1,"InvalidAccountData"]}`,
+			wantName:         "InstructionError",
+			wantIndex:        1,
+			wantInstrErr:     "InvalidAccountData",
+			wantErrorMessage: "instruction 1 failed: InvalidAccountData: invalid account data for instruction",
+		},
+		{
+			name:             "custom program error",
+			raw:              `{"InstructionError":[2,{"Custom":17}]}`,
+			wantName:         "InstructionError",
+			wantIndex:        2,
+			wantInstrErr:     "Custom",
+			wantCustomCode:   func() *uint32 { v := uint32(17); return &v }(),
+			wantErrorMessage: "instruction 2 failed: custom program error: 0x11",
+		},
+		{
+			name:             "transaction error with fields",
+			raw:              `{"InsufficientFundsForRent":{"account_index":0}}`,
+			wantName:         "InsufficientFundsForRent",
+			wantIndex:        -1,
+			wantErrorMessage: "transaction error: InsufficientFundsForRent: insufficient funds for rent",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var raw any
+			require.NoError(t, json.Unmarshal([]byte(tt.raw), &raw))
+
+			txErr := client.ParseTransactionError(raw)
+			require.NotNil(t, txErr)
+			require.Equal(t, tt.wantName, txErr.Name)
+			require.Equal(t, tt.wantIndex, txErr.InstructionIndex)
+			require.Equal(t, tt.wantInstrErr, txErr.InstructionError)
+			require.Equal(t, tt.wantCustomCode, txErr.CustomCode)
+			require.Equal(t, tt.wantErrorMessage, txErr.Error())
+		})
+	}
+
+	require.Nil(t, client.ParseTransactionError(nil))
+}
+
+func TestDecodeTransactionError(t *testing.T) {
+	feePayer := types.NewAccount()
+	mint := types.NewAccount().PublicKey
+	tx, err := types.NewTransaction(types.NewTransactionParam{
+		Message: types.NewMessage(types.NewMessageParam{
+			FeePayer:        feePayer.PublicKey,
+			RecentBlockhash: "9rAtxuhtKn8qagc3UtZFyhLrw5zgh6Fk6ExQsV5xKzto",
+			Instructions: []types.Instruction{
+				system.Transfer(system.TransferParam{
+					From:   feePayer.PublicKey,
+					To:     mint,
+					Amount: 1,
+				}),
+				token.MintTo(token.MintToParam{
+					Mint:   mint,
+					To:     feePayer.PublicKey,
+					Auth:   feePayer.PublicKey,
+					Amount: 1,
+				}),
+			},
+		}),
+		Signers: []types.Account{feePayer},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		raw         string
+		wantIs      []error
+		wantIsNot   []error
+		wantIndex   int
+		wantProgram common.PublicKey
+	}{
+		{
+			name:        "system program error",
+			raw:         `{"InstructionError":[0,{"Custom":1}]}`,
+			wantIs:      []error{client.ErrTransactionFailed, client.ErrInstructionFailed, client.ErrCustomProgramError, client.ErrSystemResultWithNegativeLamports},
+			wantIsNot:   []error{client.ErrTokenInsufficientFunds},
+			wantIndex:   0,
+			wantProgram: common.SystemProgramID,
+		},
+		{
+			name:        "token program error",
+			raw:         `{"InstructionError":[1,{"Custom":1}]}`,
+			wantIs:      []error{client.ErrTransactionFailed, client.ErrInstructionFailed, client.ErrTokenInsufficientFunds},
+			wantIsNot:   []error{client.ErrSystemResultWithNegativeLamports},
+			wantIndex:   1,
+			wantProgram: common.TokenProgramID,
+		},
+		{
+			name:        "unknown custom code",
+			raw:         `{"InstructionError":[1,{"Custom":1000}]}`,
+			wantIs:      []error{client.ErrCustomProgramError},
+			wantIsNot:   []error{client.ErrTokenInsufficientFunds},
+			wantIndex:   1,
+			wantProgram: common.TokenProgramID,
+		},
+		{
+			name:      "transaction error",
+			raw:       `"BlockhashNotFound"`,
+			wantIs:    []error{client.ErrTransactionFailed, client.ErrBlockhashNotFound},
+			wantIsNot: []error{client.ErrInstructionFailed},
+			wantIndex: -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var raw any
+			require.NoError(t, json.Unmarshal([]byte(tt.raw), &raw))
+
+			txErr := client.DecodeTransactionError(raw, tx)
+			require.NotNil(t, txErr)
+			require.Equal(t, tt.wantIndex, txErr.InstructionIndex)
+			require.Equal(t, tt.wantProgram, txErr.ProgramID)
+
+			// wrapped errors must still match
+			err := utils.StackErrors(client.ErrSendTransaction, txErr)
+			for _, target := range tt.wantIs {
+				require.ErrorIs(t, err, target)
+			}
+			for _, target := range tt.wantIsNot {
+				require.NotErrorIs(t, err, target)
+			}
+		})
+	}
+}
@@ -35,14 +35,20 @@ func SignTransaction(txSource string, signer types.Account) (string, error) {
 
 // CheckSolTransferTransaction checks if a transaction is a SOL transfer transaction.
 // Verifies that destination account has been credited with the correct amount.
+//
+// Deprecated: the balance change of the destination can not tell the payment from other credits;
+// use CheckPaymentTransaction.
 func CheckSolTransferTransaction(meta *client.TransactionMeta, tx types.Transaction, destination string, amount uint64) error {
-	var destIdx int
+	destIdx := -1
 	for i, acc := range tx.Message.Accounts {
 		if acc.ToBase58() == destination {
 			destIdx = i
 			break
 		}
 	}
+	if destIdx < 0 || destIdx >= len(meta.PreBalances) || destIdx >= len(meta.PostBalances) {
+		return fmt.Errorf("destination %s is not in the transaction", destination)
+	}
 
 	txAmount := meta.PostBalances[destIdx] - meta.PreBalances[destIdx]
 	if txAmount != int64(amount) {
@@ -54,6 +60,8 @@ func CheckSolTransferTransaction(meta *client.TransactionMeta, tx types.Transact
 
 // CheckTokenTransferTransaction checks if a transaction is a token transfer transaction.
 // Verifies that destination account has been credited with the correct amount of the token.
+//
+// Deprecated: use CheckPaymentTransaction, which checks the token transfer instructions.
 func CheckTokenTransferTransaction(meta *client.TransactionMeta, tx types.Transaction, mint, destination string, amount uint64) error {
 	var preBalance uint64
 	var postBalance uint64
@@ -80,6 +88,9 @@ func CheckTokenTransferTransaction(meta *client.TransactionMeta, tx types.Transa
 		}
 	}
 
+	if postBalance < preBalance {
+		return fmt.Errorf("destination balance decreased in the transaction: %d < %d", postBalance, preBalance)
+	}
 	if postBalance-preBalance != amount {
 		return fmt.Errorf("amount is not equal to the amount in the transaction: %d != %d", amount, postBalance-preBalance)
 	}
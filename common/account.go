@@ -6,6 +6,7 @@ import (
 	"filippo.io/edwards25519"
 	"github.com/EntySquare/solana-go-sdk/common"
 	"github.com/EntySquare/solana-go-sdk/pkg/hdwallet"
+	"github.com/EntySquare/solana-go-sdk/program/address_lookup_table"
 	"github.com/EntySquare/solana-go-sdk/types"
 	"github.com/EntySquare/solana/utils"
 	"github.com/mr-tron/base58"
@@ -21,6 +22,14 @@ const (
 // Mnemonic length type
 type MnemonicLength int
 
+// Token2022ProgramID is the id of the Token-2022 program, the SPL Token program with the mint and account extensions.
+var Token2022ProgramID = common.PublicKeyFromString("TokenzQdBNbLqP5VEhdkAS6EPFLC1PHnBqCXEpPxuEb")
+
+// IsTokenProgram reports whether the program is the SPL Token or the Token-2022 program.
+func IsTokenProgram(programID common.PublicKey) bool {
+	return programID == common.TokenProgramID || programID == Token2022ProgramID
+}
+
 // NewMnemonic generates a new mnemonic phrase
 func NewMnemonic(len MnemonicLength) (string, error) {
 	entropy, err := bip39.NewEntropy(int(len))
@@ -169,6 +178,20 @@ func DeriveTokenAccountPubkey(wallet, mint common.PublicKey) (common.PublicKey,
 	return ata, nil
 }
 
+// DeriveTokenAccountPubkeyWithProgram derives an associated token account from a Solana account and a mint address
+// owned by the token program. The associated token accounts of the Token-2022 mints differ from the SPL Token ones.
+func DeriveTokenAccountPubkeyWithProgram(wallet, mint, tokenProgram common.PublicKey) (common.PublicKey, error) {
+	ata, _, err := common.FindProgramAddress(
+		[][]byte{wallet.Bytes(), tokenProgram.Bytes(), mint.Bytes()},
+		common.SPLAssociatedTokenAccountProgramID,
+	)
+	if err != nil {
+		return common.PublicKey{}, utils.StackErrors(ErrDeriveTokenAccount, err)
+	}
+
+	return ata, nil
+}
+
 // DeriveTokenLockAccount derives an associated token holder account from a Solana account and a mint address.
 func DeriveTokenLockAccount(walletAddress, tokenMintAddress common.PublicKey) (common.PublicKey, error) {
 	seeds := [][]byte{}
@@ -214,3 +237,10 @@ func FindBurnerPubkey() (common.PublicKey, error) {
 	)
 	return pubkey, err
 }
+
+// DeriveLookupTableAddress derives an address lookup table address from the authority and a recent slot.
+// This is a wrapper around the DeriveLookupTableAddress function from the solana-go-sdk.
+func DeriveLookupTableAddress(authority common.PublicKey, recentSlot uint64) common.PublicKey {
+	pubkey, _ := address_lookup_table.DeriveLookupTableAddress(authority, recentSlot)
+	return pubkey
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"testing"
 
+	sdkcommon "github.com/EntySquare/solana-go-sdk/common"
 	"github.com/EntySquare/solana-go-sdk/types"
 	"github.com/EntySquare/solana/common"
 	"github.com/stretchr/testify/require"
@@ -150,3 +151,22 @@ func TestValidateSolanaWalletAddr(t *testing.T) {
 		})
 	}
 }
+
+func TestDeriveTokenAccountPubkeyWithProgram(t *testing.T) {
+	wallet := types.NewAccount().PublicKey
+	mint := types.NewAccount().PublicKey
+
+	ata, err := common.DeriveTokenAccountPubkey(wallet, mint)
+	require.NoError(t, err)
+	tokenAta, err := common.DeriveTokenAccountPubkeyWithProgram(wallet, mint, sdkcommon.TokenProgramID)
+	require.NoError(t, err)
+	require.Equal(t, ata, tokenAta)
+
+	token2022Ata, err := common.DeriveTokenAccountPubkeyWithProgram(wallet, mint, common.Token2022ProgramID)
+	require.NoError(t, err)
+	require.NotEqual(t, ata, token2022Ata)
+
+	require.True(t, common.IsTokenProgram(sdkcommon.TokenProgramID))
+	require.True(t, common.IsTokenProgram(common.Token2022ProgramID))
+	require.False(t, common.IsTokenProgram(sdkcommon.SystemProgramID))
+}
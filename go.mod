@@ -14,7 +14,10 @@ require (
 	github.com/tyler-smith/go-bip39 v1.1.0
 )
 
-require github.com/EntySquare/solana-go-sdk v1.23.8
+require (
+	github.com/EntySquare/solana-go-sdk v1.23.8
+	github.com/gorilla/websocket v1.5.0
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
@@ -8,31 +8,43 @@ import (
 	"github.com/EntySquare/solana-go-sdk/program/associated_token_account"
 	"github.com/EntySquare/solana-go-sdk/program/token"
 	"github.com/EntySquare/solana-go-sdk/types"
+	commonx "github.com/EntySquare/solana/common"
 )
 
 // CreateAssociatedTokenAccountParam defines the parameters for creating an associated token account.
 type CreateAssociatedTokenAccountParam struct {
-	Funder common.PublicKey
-	Owner  common.PublicKey
-	Mint   common.PublicKey
+	Funder       common.PublicKey
+	Owner        common.PublicKey
+	Mint         common.PublicKey
+	TokenProgram *common.PublicKey // optional; the SPL Token or the Token-2022 program; detected by the mint if not set
 }
 
 // CreateAssociatedTokenAccount creates an associated token account for the given owner and mint.
 func CreateAssociatedTokenAccount(params CreateAssociatedTokenAccountParam) InstructionFunc {
 	return func(ctx context.Context, c Client) ([]types.Instruction, error) {
-		ata, _, err := common.FindAssociatedTokenAddress(params.Owner, params.Mint)
+		if err := validateTokenProgram(params.TokenProgram); err != nil {
+			return nil, err
+		}
+
+		tokenProgram, err := tokenProgramID(ctx, c, params.Mint, params.TokenProgram)
+		if err != nil {
+			return nil, err
+		}
+
+		ata, err := commonx.DeriveTokenAccountPubkeyWithProgram(params.Owner, params.Mint, tokenProgram)
 		if err != nil {
 			return nil, fmt.Errorf("failed to find associated token address: %w", err)
 		}
 
 		return []types.Instruction{
-			associated_token_account.CreateAssociatedTokenAccount(
+			createAssociatedTokenAccount(
 				associated_token_account.CreateAssociatedTokenAccountParam{
 					Funder:                 params.Funder,
 					Owner:                  params.Owner,
 					Mint:                   params.Mint,
 					AssociatedTokenAccount: ata,
 				},
+				tokenProgram,
 			),
 		}, nil
 	}
@@ -42,7 +54,16 @@ func CreateAssociatedTokenAccount(params CreateAssociatedTokenAccountParam) Inst
 // the given owner and mint if it does not exist.
 func CreateAssociatedTokenAccountIfNotExists(params CreateAssociatedTokenAccountParam) InstructionFunc {
 	return func(ctx context.Context, c Client) ([]types.Instruction, error) {
-		ata, _, err := common.FindAssociatedTokenAddress(params.Owner, params.Mint)
+		if err := validateTokenProgram(params.TokenProgram); err != nil {
+			return nil, err
+		}
+
+		tokenProgram, err := tokenProgramID(ctx, c, params.Mint, params.TokenProgram)
+		if err != nil {
+			return nil, err
+		}
+
+		ata, err := commonx.DeriveTokenAccountPubkeyWithProgram(params.Owner, params.Mint, tokenProgram)
 		if err != nil {
 			return nil, fmt.Errorf("failed to find associated token address: %w", err)
 		}
@@ -54,13 +75,14 @@ func CreateAssociatedTokenAccountIfNotExists(params CreateAssociatedTokenAccount
 		}
 
 		return []types.Instruction{
-			associated_token_account.CreateAssociatedTokenAccount(
+			createAssociatedTokenAccount(
 				associated_token_account.CreateAssociatedTokenAccountParam{
 					Funder:                 params.Funder,
 					Owner:                  params.Owner,
 					Mint:                   params.Mint,
 					AssociatedTokenAccount: ata,
 				},
+				tokenProgram,
 			),
 		}, nil
 	}
@@ -72,6 +94,7 @@ type CloseTokenAccountParams struct {
 	CloseTokenAccount *common.PublicKey // required if Mint is empty; the public key of account to close
 	Mint              *common.PublicKey // required if CloseTokenAccount is empty; the mint of the token account
 	FeePayer          *common.PublicKey // optional; the fee payer of the transaction, if not set, the owner will be used; if set, the rent exemption balance will be transferred to it.
+	TokenProgram      *common.PublicKey // optional; the SPL Token or the Token-2022 program; detected by the mint or the token account if not set
 }
 
 // Validate checks that the required fields of the params are set.
@@ -91,7 +114,7 @@ func (p CloseTokenAccountParams) Validate() error {
 	if p.FeePayer != nil && *p.FeePayer == (common.PublicKey{}) {
 		return fmt.Errorf("invalid fee payer public key")
 	}
-	return nil
+	return validateTokenProgram(p.TokenProgram)
 }
 
 // CloseTokenAccount closes the specified token account.
@@ -101,8 +124,18 @@ func CloseTokenAccount(params CloseTokenAccountParams) InstructionFunc {
 			return nil, fmt.Errorf("failed to validate params: %w", err)
 		}
 
+		// the token program owns both the token account and its mint
+		programAccount := params.CloseTokenAccount
+		if programAccount == nil {
+			programAccount = params.Mint
+		}
+		tokenProgram, err := tokenProgramID(ctx, c, *programAccount, params.TokenProgram)
+		if err != nil {
+			return nil, err
+		}
+
 		if params.CloseTokenAccount == nil && params.Mint != nil {
-			ata, _, err := common.FindAssociatedTokenAddress(params.Owner, *params.Mint)
+			ata, err := commonx.DeriveTokenAccountPubkeyWithProgram(params.Owner, *params.Mint, tokenProgram)
 			if err != nil {
 				return nil, fmt.Errorf("failed to find associated token address: %w", err)
 			}
@@ -114,11 +147,11 @@ func CloseTokenAccount(params CloseTokenAccountParams) InstructionFunc {
 		}
 
 		return []types.Instruction{
-			token.CloseAccount(token.CloseAccountParam{
+			withTokenProgram(token.CloseAccount(token.CloseAccountParam{
 				Account: *params.CloseTokenAccount,
 				Auth:    params.Owner,
 				To:      *params.FeePayer,
-			}),
+			}), tokenProgram),
 		}, nil
 	}
 }
@@ -129,6 +162,7 @@ type FreezeTokenAccountParams struct {
 	Mint              common.PublicKey  // required; the mint of the token account
 	TokenAccount      *common.PublicKey // optional; the public key of account to freeze; if not set, the associated token account will be derived from the mint and token account owner.
 	TokenAccountOwner *common.PublicKey // optional; the owner of the token account;
+	TokenProgram      *common.PublicKey // optional; the SPL Token or the Token-2022 program; detected by the mint if not set
 }
 
 // Validate checks that the required fields of the params are set.
@@ -148,7 +182,7 @@ func (p FreezeTokenAccountParams) Validate() error {
 	if p.TokenAccount == nil && p.TokenAccountOwner == nil {
 		return fmt.Errorf("must be set at least one of token account or token account owner")
 	}
-	return nil
+	return validateTokenProgram(p.TokenProgram)
 }
 
 // FreezeTokenAccount freezes the specified token account.
@@ -158,8 +192,13 @@ func FreezeTokenAccount(params FreezeTokenAccountParams) InstructionFunc {
 			return nil, fmt.Errorf("failed to validate params: %w", err)
 		}
 
+		tokenProgram, err := tokenProgramID(ctx, c, params.Mint, params.TokenProgram)
+		if err != nil {
+			return nil, err
+		}
+
 		if params.TokenAccount == nil && params.TokenAccountOwner != nil {
-			ata, _, err := common.FindAssociatedTokenAddress(*params.TokenAccountOwner, params.Mint)
+			ata, err := commonx.DeriveTokenAccountPubkeyWithProgram(*params.TokenAccountOwner, params.Mint, tokenProgram)
 			if err != nil {
 				return nil, fmt.Errorf("failed to find associated token address: %w", err)
 			}
@@ -167,11 +206,11 @@ func FreezeTokenAccount(params FreezeTokenAccountParams) InstructionFunc {
 		}
 
 		return []types.Instruction{
-			token.FreezeAccount(token.FreezeAccountParam{
+			withTokenProgram(token.FreezeAccount(token.FreezeAccountParam{
 				Account: *params.TokenAccount,
 				Mint:    params.Mint,
 				Auth:    params.FreezeAuth,
-			}),
+			}), tokenProgram),
 		}, nil
 	}
 }
@@ -182,6 +221,7 @@ type UnfreezeTokenAccountParams struct {
 	Mint              common.PublicKey  // required; the mint of the token account
 	TokenAccount      *common.PublicKey // optional; the public key of account to freeze; if not set, the associated token account will be derived from the mint and token account owner.
 	TokenAccountOwner *common.PublicKey // optional; the owner of the token account;
+	TokenProgram      *common.PublicKey // optional; the SPL Token or the Token-2022 program; detected by the mint if not set
 }
 
 // Validate checks that the required fields of the params are set.
@@ -201,7 +241,7 @@ func (p UnfreezeTokenAccountParams) Validate() error {
 	if p.TokenAccount == nil && p.TokenAccountOwner == nil {
 		return fmt.Errorf("must be set at least one of token account or token account owner")
 	}
-	return nil
+	return validateTokenProgram(p.TokenProgram)
 }
 
 // UnfreezeTokenAccount unfreezes the specified token account.
@@ -211,8 +251,13 @@ func UnfreezeTokenAccount(params UnfreezeTokenAccountParams) InstructionFunc {
 			return nil, fmt.Errorf("failed to validate params: %w", err)
 		}
 
+		tokenProgram, err := tokenProgramID(ctx, c, params.Mint, params.TokenProgram)
+		if err != nil {
+			return nil, err
+		}
+
 		if params.TokenAccount == nil && params.TokenAccountOwner != nil {
-			ata, _, err := common.FindAssociatedTokenAddress(*params.TokenAccountOwner, params.Mint)
+			ata, err := commonx.DeriveTokenAccountPubkeyWithProgram(*params.TokenAccountOwner, params.Mint, tokenProgram)
 			if err != nil {
 				return nil, fmt.Errorf("failed to find associated token address: %w", err)
 			}
@@ -220,11 +265,11 @@ func UnfreezeTokenAccount(params UnfreezeTokenAccountParams) InstructionFunc {
 		}
 
 		return []types.Instruction{
-			token.ThawAccount(token.ThawAccountParam{
+			withTokenProgram(token.ThawAccount(token.ThawAccountParam{
 				Account: *params.TokenAccount,
 				Mint:    params.Mint,
 				Auth:    params.FreezeAuth,
-			}),
+			}), tokenProgram),
 		}, nil
 	}
 }
@@ -8,6 +8,7 @@ import (
 	metaplex_token_metadata "github.com/EntySquare/solana-go-sdk/program/metaplex/token_metadata"
 	"github.com/EntySquare/solana-go-sdk/program/token"
 	"github.com/EntySquare/solana-go-sdk/types"
+	commonx "github.com/EntySquare/solana/common"
 	"github.com/EntySquare/solana/token_metadata"
 )
 
@@ -164,6 +165,8 @@ type BurnTokenParams struct {
 	Mint              common.PublicKey // optional; the mint to burn
 	TokenAccountOwner common.PublicKey // optional; the token account owner
 	Amount            uint64           // optional; the amount to burn in token units
+
+	TokenProgram *common.PublicKey // optional; the SPL Token or the Token-2022 program; detected by the mint if not set
 }
 
 // Validate checks that the required fields of the params are set.
@@ -174,7 +177,7 @@ func (p BurnTokenParams) Validate() error {
 	if p.TokenAccountOwner == (common.PublicKey{}) {
 		return fmt.Errorf("token account owner is required")
 	}
-	return nil
+	return validateTokenProgram(p.TokenProgram)
 }
 
 // BurnToken burns the specified token.
@@ -184,18 +187,23 @@ func BurnToken(params BurnTokenParams) InstructionFunc {
 			return nil, fmt.Errorf("failed to validate params: %w", err)
 		}
 
-		ata, _, err := common.FindAssociatedTokenAddress(params.TokenAccountOwner, params.Mint)
+		tokenProgram, err := tokenProgramID(ctx, c, params.Mint, params.TokenProgram)
+		if err != nil {
+			return nil, err
+		}
+
+		ata, err := commonx.DeriveTokenAccountPubkeyWithProgram(params.TokenAccountOwner, params.Mint, tokenProgram)
 		if err != nil {
 			return nil, fmt.Errorf("failed to find associated token address: %w", err)
 		}
 
 		return []types.Instruction{
-			token.Burn(token.BurnParam{
+			withTokenProgram(token.Burn(token.BurnParam{
 				Account: ata,
 				Mint:    params.Mint,
 				Auth:    params.TokenAccountOwner,
 				Amount:  params.Amount,
-			}),
+			}), tokenProgram),
 		}, nil
 	}
 }
@@ -0,0 +1,37 @@
+package instructions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/EntySquare/solana-go-sdk/program/compute_budget"
+	"github.com/EntySquare/solana-go-sdk/types"
+	typesx "github.com/EntySquare/solana/types"
+)
+
+// SetComputeUnitLimit sets the max compute units the transaction is allowed to consume.
+// Units must be greater than 0 and less or equal typesx.MaxComputeUnitLimit.
+func SetComputeUnitLimit(units uint32) InstructionFunc {
+	return func(ctx context.Context, c Client) ([]types.Instruction, error) {
+		if units == 0 || units > typesx.MaxComputeUnitLimit {
+			return nil, fmt.Errorf("compute unit limit must be greater than 0 and less or equal %d", typesx.MaxComputeUnitLimit)
+		}
+
+		return []types.Instruction{
+			compute_budget.SetComputeUnitLimit(compute_budget.SetComputeUnitLimitParam{
+				Units: units,
+			}),
+		}, nil
+	}
+}
+
+// SetComputeUnitPrice sets the price in micro-lamports paid per compute unit to prioritize the transaction.
+func SetComputeUnitPrice(microLamports uint64) InstructionFunc {
+	return func(ctx context.Context, c Client) ([]types.Instruction, error) {
+		return []types.Instruction{
+			compute_budget.SetComputeUnitPrice(compute_budget.SetComputeUnitPriceParam{
+				MicroLamports: microLamports,
+			}),
+		}, nil
+	}
+}
@@ -0,0 +1,245 @@
+package instructions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/program/address_lookup_table"
+	"github.com/EntySquare/solana-go-sdk/types"
+	"github.com/EntySquare/solana/client"
+	typesx "github.com/EntySquare/solana/types"
+)
+
+// CreateLookupTableParams is the params for CreateLookupTable.
+type CreateLookupTableParams struct {
+	Authority  common.PublicKey  // required; The authority of the lookup table
+	FeePayer   *common.PublicKey // optional; The fee payer of the transaction; default is authority
+	RecentSlot uint64            // optional; The recent slot used to derive the table address; default is the current slot
+}
+
+// Validate validates the params.
+func (p CreateLookupTableParams) Validate() error {
+	if p.Authority == (common.PublicKey{}) {
+		return fmt.Errorf("authority is required")
+	}
+	if p.FeePayer != nil && *p.FeePayer == (common.PublicKey{}) {
+		return fmt.Errorf("invalid fee payer public key")
+	}
+	return nil
+}
+
+// CreateLookupTable creates a new address lookup table.
+// The table address is derived from the authority and the recent slot,
+// use common.DeriveLookupTableAddress with the same slot to get it.
+func CreateLookupTable(params CreateLookupTableParams) InstructionFunc {
+	return func(ctx context.Context, c Client) ([]types.Instruction, error) {
+		if err := params.Validate(); err != nil {
+			return nil, fmt.Errorf("validate create lookup table: %w", err)
+		}
+
+		if params.FeePayer == nil {
+			params.FeePayer = &params.Authority
+		}
+
+		if params.RecentSlot == 0 {
+			slot, err := c.GetSlot(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get recent slot: %w", err)
+			}
+			params.RecentSlot = slot
+		}
+
+		lookupTable, bump := address_lookup_table.DeriveLookupTableAddress(params.Authority, params.RecentSlot)
+
+		return []types.Instruction{
+			address_lookup_table.CreateLookupTable(address_lookup_table.CreateLookupTableParams{
+				LookupTable: lookupTable,
+				Authority:   params.Authority,
+				Payer:       *params.FeePayer,
+				RecentSlot:  params.RecentSlot,
+				BumpSeed:    bump,
+			}),
+		}, nil
+	}
+}
+
+// ExtendLookupTableParams is the params for ExtendLookupTable.
+type ExtendLookupTableParams struct {
+	LookupTable common.PublicKey   // required; The lookup table to extend
+	Authority   common.PublicKey   // required; The authority of the lookup table
+	FeePayer    *common.PublicKey  // optional; The fee payer of the transaction; default is authority
+	Addresses   []common.PublicKey // required; The addresses to add to the lookup table
+}
+
+// Validate validates the params.
+func (p ExtendLookupTableParams) Validate() error {
+	if p.LookupTable == (common.PublicKey{}) {
+		return fmt.Errorf("lookup table is required")
+	}
+	if p.Authority == (common.PublicKey{}) {
+		return fmt.Errorf("authority is required")
+	}
+	if p.FeePayer != nil && *p.FeePayer == (common.PublicKey{}) {
+		return fmt.Errorf("invalid fee payer public key")
+	}
+	if len(p.Addresses) == 0 {
+		return fmt.Errorf("at least one address is required")
+	}
+	if uint(len(p.Addresses)) > typesx.LookupTableMaxAddresses {
+		return fmt.Errorf("lookup table can hold at most %d addresses", typesx.LookupTableMaxAddresses)
+	}
+	for _, addr := range p.Addresses {
+		if addr == (common.PublicKey{}) {
+			return fmt.Errorf("invalid address public key")
+		}
+	}
+	return nil
+}
+
+// ExtendLookupTable adds addresses to the lookup table.
+// Fails if the table would hold more than typesx.LookupTableMaxAddresses addresses.
+// If the table does not exist yet (e.g. it is created in the same transaction), only the given addresses are counted,
+// any other error of loading the table is returned.
+func ExtendLookupTable(params ExtendLookupTableParams) InstructionFunc {
+	return func(ctx context.Context, c Client) ([]types.Instruction, error) {
+		if err := params.Validate(); err != nil {
+			return nil, fmt.Errorf("validate extend lookup table: %w", err)
+		}
+
+		if params.FeePayer == nil {
+			params.FeePayer = &params.Authority
+		}
+
+		existing, err := c.GetLookupTableAddresses(ctx, params.LookupTable.ToBase58())
+		if err != nil && !errors.Is(err, client.ErrLookupTableNotFound) {
+			return nil, fmt.Errorf("failed to get lookup table addresses: %w", err)
+		}
+		if total := uint(len(existing) + len(params.Addresses)); total > typesx.LookupTableMaxAddresses {
+			return nil, fmt.Errorf(
+				"lookup table %s would hold %d addresses, max is %d",
+				params.LookupTable.ToBase58(), total, typesx.LookupTableMaxAddresses,
+			)
+		}
+
+		return []types.Instruction{
+			address_lookup_table.ExtendLookupTable(address_lookup_table.ExtendLookupTableParams{
+				LookupTable: params.LookupTable,
+				Authority:   params.Authority,
+				Payer:       params.FeePayer,
+				Addresses:   params.Addresses,
+			}),
+		}, nil
+	}
+}
+
+// FreezeLookupTableParams is the params for FreezeLookupTable.
+type FreezeLookupTableParams struct {
+	LookupTable common.PublicKey // required; The lookup table to freeze
+	Authority   common.PublicKey // required; The authority of the lookup table
+}
+
+// Validate validates the params.
+func (p FreezeLookupTableParams) Validate() error {
+	if p.LookupTable == (common.PublicKey{}) {
+		return fmt.Errorf("lookup table is required")
+	}
+	if p.Authority == (common.PublicKey{}) {
+		return fmt.Errorf("authority is required")
+	}
+	return nil
+}
+
+// FreezeLookupTable freezes the lookup table.
+// A frozen table can not be extended, deactivated or closed anymore.
+func FreezeLookupTable(params FreezeLookupTableParams) InstructionFunc {
+	return func(ctx context.Context, c Client) ([]types.Instruction, error) {
+		if err := params.Validate(); err != nil {
+			return nil, fmt.Errorf("validate freeze lookup table: %w", err)
+		}
+
+		return []types.Instruction{
+			address_lookup_table.FreezeLookupTable(address_lookup_table.FreezeLookupTableParams{
+				LookupTable: params.LookupTable,
+				Authority:   params.Authority,
+			}),
+		}, nil
+	}
+}
+
+// DeactivateLookupTableParams is the params for DeactivateLookupTable.
+type DeactivateLookupTableParams struct {
+	LookupTable common.PublicKey // required; The lookup table to deactivate
+	Authority   common.PublicKey // required; The authority of the lookup table
+}
+
+// Validate validates the params.
+func (p DeactivateLookupTableParams) Validate() error {
+	if p.LookupTable == (common.PublicKey{}) {
+		return fmt.Errorf("lookup table is required")
+	}
+	if p.Authority == (common.PublicKey{}) {
+		return fmt.Errorf("authority is required")
+	}
+	return nil
+}
+
+// DeactivateLookupTable deactivates the lookup table.
+// A deactivated table can not be used in new transactions and can be closed after the cool-down period.
+func DeactivateLookupTable(params DeactivateLookupTableParams) InstructionFunc {
+	return func(ctx context.Context, c Client) ([]types.Instruction, error) {
+		if err := params.Validate(); err != nil {
+			return nil, fmt.Errorf("validate deactivate lookup table: %w", err)
+		}
+
+		return []types.Instruction{
+			address_lookup_table.DeactivateLookupTable(address_lookup_table.DeactivateLookupTableParams{
+				LookupTable: params.LookupTable,
+				Authority:   params.Authority,
+			}),
+		}, nil
+	}
+}
+
+// CloseLookupTableParams is the params for CloseLookupTable.
+type CloseLookupTableParams struct {
+	LookupTable common.PublicKey  // required; The lookup table to close
+	Authority   common.PublicKey  // required; The authority of the lookup table
+	Recipient   *common.PublicKey // optional; The account to receive the rent; default is authority
+}
+
+// Validate validates the params.
+func (p CloseLookupTableParams) Validate() error {
+	if p.LookupTable == (common.PublicKey{}) {
+		return fmt.Errorf("lookup table is required")
+	}
+	if p.Authority == (common.PublicKey{}) {
+		return fmt.Errorf("authority is required")
+	}
+	if p.Recipient != nil && *p.Recipient == (common.PublicKey{}) {
+		return fmt.Errorf("invalid recipient public key")
+	}
+	return nil
+}
+
+// CloseLookupTable closes the deactivated lookup table and transfers its rent to the recipient.
+func CloseLookupTable(params CloseLookupTableParams) InstructionFunc {
+	return func(ctx context.Context, c Client) ([]types.Instruction, error) {
+		if err := params.Validate(); err != nil {
+			return nil, fmt.Errorf("validate close lookup table: %w", err)
+		}
+
+		if params.Recipient == nil {
+			params.Recipient = &params.Authority
+		}
+
+		return []types.Instruction{
+			address_lookup_table.CloseLookupTable(address_lookup_table.CloseLookupTableParams{
+				LookupTable: params.LookupTable,
+				Authority:   params.Authority,
+				Recipient:   *params.Recipient,
+			}),
+		}, nil
+	}
+}
@@ -0,0 +1,211 @@
+package instructions_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/program/address_lookup_table"
+	"github.com/EntySquare/solana-go-sdk/types"
+	"github.com/EntySquare/solana/client"
+	"github.com/EntySquare/solana/instructions"
+	"github.com/EntySquare/solana/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// lookupTableClient serves the slot and the lookup table addresses, other methods are not used by the lookup table instructions.
+type lookupTableClient struct {
+	instructions.Client
+	slot      uint64
+	addresses []common.PublicKey
+	err       error
+}
+
+func (c lookupTableClient) GetSlot(context.Context) (uint64, error) {
+	return c.slot, nil
+}
+
+func (c lookupTableClient) GetLookupTableAddresses(context.Context, string) ([]common.PublicKey, error) {
+	return c.addresses, c.err
+}
+
+func newAddresses(n int) []common.PublicKey {
+	addresses := make([]common.PublicKey, n)
+	for i := range addresses {
+		addresses[i] = types.NewAccount().PublicKey
+	}
+	return addresses
+}
+
+func TestCreateLookupTable(t *testing.T) {
+	ctx := context.Background()
+	authority := types.NewAccount().PublicKey
+	payer := types.NewAccount().PublicKey
+	c := lookupTableClient{slot: 42}
+
+	ixs, err := instructions.CreateLookupTable(instructions.CreateLookupTableParams{
+		Authority: authority,
+		FeePayer:  &payer,
+	})(ctx, c)
+	require.NoError(t, err)
+
+	table, bump := address_lookup_table.DeriveLookupTableAddress(authority, 42)
+	require.Equal(t, []types.Instruction{
+		address_lookup_table.CreateLookupTable(address_lookup_table.CreateLookupTableParams{
+			LookupTable: table,
+			Authority:   authority,
+			Payer:       payer,
+			RecentSlot:  42,
+			BumpSeed:    bump,
+		}),
+	}, ixs)
+
+	// the given slot takes precedence over the current one
+	ixs, err = instructions.CreateLookupTable(instructions.CreateLookupTableParams{
+		Authority:  authority,
+		RecentSlot: 7,
+	})(ctx, c)
+	require.NoError(t, err)
+	table, _ = address_lookup_table.DeriveLookupTableAddress(authority, 7)
+	require.Equal(t, table, ixs[0].Accounts[0].PubKey)
+	require.Equal(t, authority, ixs[0].Accounts[2].PubKey)
+
+	_, err = instructions.CreateLookupTable(instructions.CreateLookupTableParams{})(ctx, c)
+	require.Error(t, err)
+}
+
+func TestExtendLookupTable(t *testing.T) {
+	ctx := context.Background()
+	table := types.NewAccount().PublicKey
+	authority := types.NewAccount().PublicKey
+	addresses := newAddresses(2)
+
+	ixs, err := instructions.ExtendLookupTable(instructions.ExtendLookupTableParams{
+		LookupTable: table,
+		Authority:   authority,
+		Addresses:   addresses,
+	})(ctx, lookupTableClient{addresses: newAddresses(10)})
+	require.NoError(t, err)
+	require.Equal(t, []types.Instruction{
+		address_lookup_table.ExtendLookupTable(address_lookup_table.ExtendLookupTableParams{
+			LookupTable: table,
+			Authority:   authority,
+			Payer:       &authority,
+			Addresses:   addresses,
+		}),
+	}, ixs)
+
+	// the table is created in the same transaction
+	notFound := utils.StackErrors(client.ErrGetLookupTable, client.ErrLookupTableNotFound)
+	_, err = instructions.ExtendLookupTable(instructions.ExtendLookupTableParams{
+		LookupTable: table,
+		Authority:   authority,
+		Addresses:   addresses,
+	})(ctx, lookupTableClient{err: notFound})
+	require.NoError(t, err)
+
+	rpcErr := errors.New("rpc unavailable")
+	_, err = instructions.ExtendLookupTable(instructions.ExtendLookupTableParams{
+		LookupTable: table,
+		Authority:   authority,
+		Addresses:   addresses,
+	})(ctx, lookupTableClient{err: rpcErr})
+	require.ErrorIs(t, err, rpcErr)
+}
+
+func TestExtendLookupTable_MaxAddresses(t *testing.T) {
+	ctx := context.Background()
+	params := instructions.ExtendLookupTableParams{
+		LookupTable: types.NewAccount().PublicKey,
+		Authority:   types.NewAccount().PublicKey,
+	}
+
+	// the table is filled up to the cap
+	params.Addresses = newAddresses(6)
+	_, err := instructions.ExtendLookupTable(params)(ctx, lookupTableClient{addresses: newAddresses(250)})
+	require.NoError(t, err)
+
+	params.Addresses = newAddresses(7)
+	_, err = instructions.ExtendLookupTable(params)(ctx, lookupTableClient{addresses: newAddresses(250)})
+	require.ErrorContains(t, err, "would hold 257 addresses, max is 256")
+
+	params.Addresses = newAddresses(257)
+	_, err = instructions.ExtendLookupTable(params)(ctx, lookupTableClient{})
+	require.ErrorContains(t, err, "at most 256 addresses")
+}
+
+func TestFreezeLookupTable(t *testing.T) {
+	table := types.NewAccount().PublicKey
+	authority := types.NewAccount().PublicKey
+
+	ixs, err := instructions.FreezeLookupTable(instructions.FreezeLookupTableParams{
+		LookupTable: table,
+		Authority:   authority,
+	})(context.Background(), lookupTableClient{})
+	require.NoError(t, err)
+	require.Equal(t, []types.Instruction{
+		address_lookup_table.FreezeLookupTable(address_lookup_table.FreezeLookupTableParams{
+			LookupTable: table,
+			Authority:   authority,
+		}),
+	}, ixs)
+
+	_, err = instructions.FreezeLookupTable(instructions.FreezeLookupTableParams{LookupTable: table})(context.Background(), lookupTableClient{})
+	require.Error(t, err)
+}
+
+func TestDeactivateLookupTable(t *testing.T) {
+	table := types.NewAccount().PublicKey
+	authority := types.NewAccount().PublicKey
+
+	ixs, err := instructions.DeactivateLookupTable(instructions.DeactivateLookupTableParams{
+		LookupTable: table,
+		Authority:   authority,
+	})(context.Background(), lookupTableClient{})
+	require.NoError(t, err)
+	require.Equal(t, []types.Instruction{
+		address_lookup_table.DeactivateLookupTable(address_lookup_table.DeactivateLookupTableParams{
+			LookupTable: table,
+			Authority:   authority,
+		}),
+	}, ixs)
+
+	_, err = instructions.DeactivateLookupTable(instructions.DeactivateLookupTableParams{Authority: authority})(context.Background(), lookupTableClient{})
+	require.Error(t, err)
+}
+
+func TestCloseLookupTable(t *testing.T) {
+	table := types.NewAccount().PublicKey
+	authority := types.NewAccount().PublicKey
+	recipient := types.NewAccount().PublicKey
+
+	ixs, err := instructions.CloseLookupTable(instructions.CloseLookupTableParams{
+		LookupTable: table,
+		Authority:   authority,
+	})(context.Background(), lookupTableClient{})
+	require.NoError(t, err)
+	require.Equal(t, []types.Instruction{
+		address_lookup_table.CloseLookupTable(address_lookup_table.CloseLookupTableParams{
+			LookupTable: table,
+			Authority:   authority,
+			Recipient:   authority,
+		}),
+	}, ixs)
+
+	ixs, err = instructions.CloseLookupTable(instructions.CloseLookupTableParams{
+		LookupTable: table,
+		Authority:   authority,
+		Recipient:   &recipient,
+	})(context.Background(), lookupTableClient{})
+	require.NoError(t, err)
+	require.Equal(t, recipient, ixs[0].Accounts[2].PubKey)
+
+	invalid := common.PublicKey{}
+	_, err = instructions.CloseLookupTable(instructions.CloseLookupTableParams{
+		LookupTable: table,
+		Authority:   authority,
+		Recipient:   &invalid,
+	})(context.Background(), lookupTableClient{})
+	require.Error(t, err)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/EntySquare/solana-go-sdk/program/system"
 	"github.com/EntySquare/solana-go-sdk/program/token"
 	"github.com/EntySquare/solana-go-sdk/types"
+	commonx "github.com/EntySquare/solana/common"
 	"github.com/EntySquare/solana/metadata"
 	"github.com/EntySquare/solana/token_metadata"
 	"github.com/EntySquare/solana/utils"
@@ -28,6 +29,8 @@ type MintFungibleParam struct {
 	MetadataURI   string // optional; URI of the token metadata; can be set later
 	TokenName     string // optional; Name of the token; used for the token metadata if MetadataURI is not set.
 	TokenSymbol   string // optional; Symbol of the token; used for the token metadata if MetadataURI is not set.
+
+	TokenProgram *common.PublicKey // optional; the program of the new mint, the SPL Token or the Token-2022 program; default is the SPL Token program
 }
 
 // Validate checks that the required fields of the params are set.
@@ -53,7 +56,7 @@ func (p MintFungibleParam) Validate() error {
 	if p.TokenSymbol != "" && (len(p.TokenSymbol) < 3 || len(p.TokenSymbol) > 10) {
 		return fmt.Errorf("token symbol must be between 3 and 10 characters")
 	}
-	return nil
+	return validateTokenProgram(p.TokenProgram)
 }
 
 // MintFungible creates instructions for minting fungible tokens or assets.
@@ -69,6 +72,10 @@ func MintFungible(params MintFungibleParam) InstructionFunc {
 		if params.FeePayer == nil {
 			params.FeePayer = &params.MintTo
 		}
+		tokenProgram := common.TokenProgramID
+		if params.TokenProgram != nil {
+			tokenProgram = *params.TokenProgram
+		}
 
 		metaPubkey, err := token_metadata.DeriveTokenMetadataPubkey(params.Mint)
 		if err != nil {
@@ -110,16 +117,16 @@ func MintFungible(params MintFungibleParam) InstructionFunc {
 			system.CreateAccount(system.CreateAccountParam{
 				From:     *params.FeePayer,
 				New:      params.Mint,
-				Owner:    common.TokenProgramID,
+				Owner:    tokenProgram,
 				Lamports: rentExemption,
 				Space:    token.MintAccountSize,
 			}),
-			token.InitializeMint2(token.InitializeMint2Param{
+			withTokenProgram(token.InitializeMint2(token.InitializeMint2Param{
 				Decimals:   params.Decimals,
 				Mint:       params.Mint,
 				MintAuth:   params.MintTo,
 				FreezeAuth: utils.Pointer(params.MintTo),
-			}),
+			}), tokenProgram),
 			metaplex_token_metadata.CreateMetadataAccountV3(metaplex_token_metadata.CreateMetadataAccountV3Param{
 				Metadata:                metaPubkey,
 				Mint:                    params.Mint,
@@ -133,40 +140,41 @@ func MintFungible(params MintFungibleParam) InstructionFunc {
 		}
 
 		if params.SupplyAmount > 0 {
-			ownerAta, _, err := common.FindAssociatedTokenAddress(params.MintTo, params.Mint)
+			ownerAta, err := commonx.DeriveTokenAccountPubkeyWithProgram(params.MintTo, params.Mint, tokenProgram)
 			if err != nil {
 				return nil, fmt.Errorf("failed to find associated token address: %w", err)
 			}
 
 			instructions = append(
 				instructions,
-				associated_token_account.CreateAssociatedTokenAccount(
+				createAssociatedTokenAccount(
 					associated_token_account.CreateAssociatedTokenAccountParam{
 						Funder:                 *params.FeePayer,
 						Owner:                  params.MintTo,
 						Mint:                   params.Mint,
 						AssociatedTokenAccount: ownerAta,
 					},
+					tokenProgram,
 				),
-				token.MintToChecked(token.MintToCheckedParam{
+				withTokenProgram(token.MintToChecked(token.MintToCheckedParam{
 					Mint:     params.Mint,
 					Auth:     params.MintTo,
 					Signers:  []common.PublicKey{},
 					To:       ownerAta,
 					Amount:   params.SupplyAmount,
 					Decimals: params.Decimals,
-				}),
+				}), tokenProgram),
 			)
 		}
 
 		if params.IsFixedSupply && params.SupplyAmount > 0 {
-			instructions = append(instructions, token.SetAuthority(token.SetAuthorityParam{
+			instructions = append(instructions, withTokenProgram(token.SetAuthority(token.SetAuthorityParam{
 				Account:  params.Mint,
 				AuthType: token.AuthorityTypeMintTokens,
 				Auth:     params.MintTo,
 				NewAuth:  nil,
 				Signers:  []common.PublicKey{},
-			}))
+			}), tokenProgram))
 		}
 
 		return instructions, nil
@@ -179,6 +187,7 @@ type MintExistedFungibleParam struct {
 	MintTo       common.PublicKey  // required; The wallet to mint tokens to
 	FeePayer     *common.PublicKey // optional; The wallet to pay the fees from; default is MintTo
 	SupplyAmount uint64            // required; The init supply of the token (in token minimal units), e.g: if you want to mint 10 tokens and decimals=9, amount=10*1e9/amount=10000000000; default is 0, then no tokens will be minted
+	TokenProgram *common.PublicKey // optional; the SPL Token or the Token-2022 program; detected by the mint if not set
 }
 
 // Validate validates the parameter.
@@ -195,7 +204,7 @@ func (p MintExistedFungibleParam) Validate() error {
 	if p.SupplyAmount == 0 {
 		return fmt.Errorf("supply amount is required")
 	}
-	return nil
+	return validateTokenProgram(p.TokenProgram)
 }
 
 // Mint existed fingible token.
@@ -206,18 +215,25 @@ func MintExistedFungible(params MintExistedFungibleParam) InstructionFunc {
 		if params.FeePayer == nil {
 			params.FeePayer = &params.MintTo
 		}
-		ownerAta, _, err := common.FindAssociatedTokenAddress(params.MintTo, params.Mint)
+		if err := validateTokenProgram(params.TokenProgram); err != nil {
+			return nil, err
+		}
+		tokenProgram, err := tokenProgramID(ctx, c, params.Mint, params.TokenProgram)
+		if err != nil {
+			return nil, err
+		}
+		ownerAta, err := commonx.DeriveTokenAccountPubkeyWithProgram(params.MintTo, params.Mint, tokenProgram)
 		if err != nil {
 			return nil, fmt.Errorf("failed to find associated token address: %w", err)
 		}
 		instructions := []types.Instruction{
-			token.MintTo(token.MintToParam{
+			withTokenProgram(token.MintTo(token.MintToParam{
 				Mint:    params.Mint,
 				Auth:    params.MintTo,
 				Signers: []common.PublicKey{},
 				To:      ownerAta,
 				Amount:  params.SupplyAmount,
-			}),
+			}), tokenProgram),
 		}
 		return instructions, nil
 	}
@@ -228,6 +244,8 @@ type DisableFungibleTokenMintingParam struct {
 	Mint     common.PublicKey  // required; The token mint public key
 	MintAuth common.PublicKey  // required; The mint authority
 	FeePayer *common.PublicKey // optional; The wallet to pay the fees from; default is MintAuth
+
+	TokenProgram *common.PublicKey // optional; the SPL Token or the Token-2022 program; detected by the mint if not set
 }
 
 // Validate validates the parameter.
@@ -241,7 +259,7 @@ func (p DisableFungibleTokenMintingParam) Validate() error {
 	if p.FeePayer != nil && *p.FeePayer == (common.PublicKey{}) {
 		return fmt.Errorf("fee payer public key is invalid")
 	}
-	return nil
+	return validateTokenProgram(p.TokenProgram)
 }
 
 // DisableFungibleTokenMinting freezes the token mint.
@@ -251,14 +269,21 @@ func DisableFungibleTokenMinting(params DisableFungibleTokenMintingParam) Instru
 		if params.FeePayer == nil {
 			params.FeePayer = &params.MintAuth
 		}
+		if err := validateTokenProgram(params.TokenProgram); err != nil {
+			return nil, err
+		}
+		tokenProgram, err := tokenProgramID(ctx, c, params.Mint, params.TokenProgram)
+		if err != nil {
+			return nil, err
+		}
 		instructions := []types.Instruction{
-			token.SetAuthority(token.SetAuthorityParam{
+			withTokenProgram(token.SetAuthority(token.SetAuthorityParam{
 				Account:  params.Mint,
 				AuthType: token.AuthorityTypeMintTokens,
 				Auth:     params.MintAuth,
 				NewAuth:  nil,
 				Signers:  []common.PublicKey{},
-			}),
+			}), tokenProgram),
 		}
 		return instructions, nil
 	}
@@ -0,0 +1,50 @@
+package instructions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/program/associated_token_account"
+	"github.com/EntySquare/solana-go-sdk/types"
+	commonx "github.com/EntySquare/solana/common"
+)
+
+// ataTokenProgramIndex is the index of the token program in the accounts of the Associated Token Account program Create instruction.
+const ataTokenProgramIndex = 5
+
+// validateTokenProgram checks that the optional token program is the SPL Token or the Token-2022 program.
+func validateTokenProgram(tokenProgram *common.PublicKey) error {
+	if tokenProgram != nil && !commonx.IsTokenProgram(*tokenProgram) {
+		return fmt.Errorf("token program must be the SPL Token or the Token-2022 program")
+	}
+	return nil
+}
+
+// tokenProgramID returns the given token program, or detects it by the owner of the account, e.g. the mint.
+func tokenProgramID(ctx context.Context, c Client, account common.PublicKey, tokenProgram *common.PublicKey) (common.PublicKey, error) {
+	if tokenProgram != nil {
+		return *tokenProgram, nil
+	}
+
+	programID, err := c.GetTokenProgramID(ctx, account.ToBase58())
+	if err != nil {
+		return common.PublicKey{}, fmt.Errorf("failed to detect token program: %w", err)
+	}
+
+	return programID, nil
+}
+
+// withTokenProgram sets the program of the token instruction built by the solana-go-sdk for the SPL Token program.
+// The Token-2022 program accepts the same instructions.
+func withTokenProgram(instruction types.Instruction, tokenProgram common.PublicKey) types.Instruction {
+	instruction.ProgramID = tokenProgram
+	return instruction
+}
+
+// createAssociatedTokenAccount builds the Associated Token Account program Create instruction for the token program.
+func createAssociatedTokenAccount(params associated_token_account.CreateAssociatedTokenAccountParam, tokenProgram common.PublicKey) types.Instruction {
+	instruction := associated_token_account.CreateAssociatedTokenAccount(params)
+	instruction.Accounts[ataTokenProgramIndex].PubKey = tokenProgram
+	return instruction
+}
@@ -11,10 +11,11 @@ import (
 
 // TransferSOLParams defines the parameters for transferring SOL.
 type TransferSOLParams struct {
-	Sender    common.PublicKey  // required; The wallet to send SOL from
-	Recipient common.PublicKey  // required; The wallet to send SOL to
-	Amount    uint64            // required; The amount of SOL to send (in lamports)
-	Reference *common.PublicKey // optional; public key to use as a reference for the transaction.
+	Sender     common.PublicKey   // required; The wallet to send SOL from
+	Recipient  common.PublicKey   // required; The wallet to send SOL to
+	Amount     uint64             // required; The amount of SOL to send (in lamports)
+	Reference  *common.PublicKey  // optional; public key to use as a reference for the transaction.
+	References []common.PublicKey // optional; more reference public keys, e.g. of a Solana Pay transfer request.
 }
 
 // TransferSOL transfers SOL from one wallet to another.
@@ -44,6 +45,16 @@ func TransferSOL(params TransferSOLParams) InstructionFunc {
 				IsWritable: false,
 			})
 		}
+		for _, reference := range params.References {
+			if reference == (common.PublicKey{}) {
+				return nil, fmt.Errorf("invalid reference public key")
+			}
+			instruction.Accounts = append(instruction.Accounts, types.AccountMeta{
+				PubKey:     reference,
+				IsSigner:   false,
+				IsWritable: false,
+			})
+		}
 
 		return []types.Instruction{instruction}, nil
 	}
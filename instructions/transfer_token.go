@@ -7,15 +7,19 @@ import (
 	"github.com/EntySquare/solana-go-sdk/common"
 	"github.com/EntySquare/solana-go-sdk/program/token"
 	"github.com/EntySquare/solana-go-sdk/types"
+	commonx "github.com/EntySquare/solana/common"
 )
 
 // TransferTokenParam defines the parameters for transferring tokens.
 type TransferTokenParam struct {
-	Sender    common.PublicKey  // required if SenderAta is empty; The wallet to send tokens from
-	Recipient common.PublicKey  // required if RecipientAta is empty; The wallet to send tokens to
-	Mint      common.PublicKey  // required; The token mint to send
-	Amount    uint64            // required; The amount of tokens to send (in token minimal units)
-	Reference *common.PublicKey // optional; public key to use as a reference for the transaction.
+	Sender     common.PublicKey   // required if SenderAta is empty; The wallet to send tokens from
+	Recipient  common.PublicKey   // required if RecipientAta is empty; The wallet to send tokens to
+	Mint       common.PublicKey   // required; The token mint to send
+	Amount     uint64             // required; The amount of tokens to send (in token minimal units)
+	Reference  *common.PublicKey  // optional; public key to use as a reference for the transaction.
+	References []common.PublicKey // optional; more reference public keys, e.g. of a Solana Pay transfer request.
+
+	TokenProgram *common.PublicKey // optional; the SPL Token or the Token-2022 program; detected by the mint if not set
 }
 
 // Validate validates the parameters.
@@ -35,7 +39,12 @@ func (p TransferTokenParam) Validate() error {
 	if p.Reference != nil && *p.Reference == (common.PublicKey{}) {
 		return fmt.Errorf("invalid reference public key")
 	}
-	return nil
+	for _, reference := range p.References {
+		if reference == (common.PublicKey{}) {
+			return fmt.Errorf("invalid reference public key")
+		}
+	}
+	return validateTokenProgram(p.TokenProgram)
 }
 
 // TransferToken transfers tokens from one wallet to another.
@@ -48,22 +57,45 @@ func TransferToken(params TransferTokenParam) InstructionFunc {
 			return nil, fmt.Errorf("invalid given data: %w", err)
 		}
 
-		senderAta, _, err := common.FindAssociatedTokenAddress(params.Sender, params.Mint)
+		tokenProgram, err := tokenProgramID(ctx, c, params.Mint, params.TokenProgram)
+		if err != nil {
+			return nil, err
+		}
+
+		senderAta, err := commonx.DeriveTokenAccountPubkeyWithProgram(params.Sender, params.Mint, tokenProgram)
 		if err != nil {
 			return nil, fmt.Errorf("failed to find associated token address for sender wallet: %w", err)
 		}
 
-		recipientAta, _, err := common.FindAssociatedTokenAddress(params.Recipient, params.Mint)
+		recipientAta, err := commonx.DeriveTokenAccountPubkeyWithProgram(params.Recipient, params.Mint, tokenProgram)
 		if err != nil {
 			return nil, fmt.Errorf("failed to find associated token address for recipient wallet: %w", err)
 		}
 
-		instruction := token.Transfer(token.TransferParam{
-			From:   senderAta,
-			To:     recipientAta,
-			Auth:   params.Sender,
-			Amount: params.Amount,
-		})
+		var instruction types.Instruction
+		if tokenProgram == common.TokenProgramID {
+			instruction = token.Transfer(token.TransferParam{
+				From:   senderAta,
+				To:     recipientAta,
+				Auth:   params.Sender,
+				Amount: params.Amount,
+			})
+		} else {
+			// the Token-2022 mints with the transfer extensions accept only the checked transfer
+			mint, err := c.GetMintInfo(ctx, params.Mint.ToBase58())
+			if err != nil {
+				return nil, fmt.Errorf("failed to get mint info: %w", err)
+			}
+
+			instruction = withTokenProgram(token.TransferChecked(token.TransferCheckedParam{
+				From:     senderAta,
+				To:       recipientAta,
+				Mint:     params.Mint,
+				Auth:     params.Sender,
+				Amount:   params.Amount,
+				Decimals: mint.Decimals,
+			}), tokenProgram)
+		}
 
 		if params.Reference != nil {
 			instruction.Accounts = append(instruction.Accounts, types.AccountMeta{
@@ -72,6 +104,13 @@ func TransferToken(params TransferTokenParam) InstructionFunc {
 				IsWritable: false,
 			})
 		}
+		for _, reference := range params.References {
+			instruction.Accounts = append(instruction.Accounts, types.AccountMeta{
+				PubKey:     reference,
+				IsSigner:   false,
+				IsWritable: false,
+			})
+		}
 
 		return []types.Instruction{instruction}, nil
 	}
@@ -24,8 +24,12 @@ type (
 		DefaultDecimals() uint8
 		GetMinimumBalanceForRentExemption(ctx context.Context, size uint64) (uint64, error)
 		GetTokenAccountInfo(ctx context.Context, base58AtaAddr string) (token.TokenAccount, error)
+		GetMintInfo(ctx context.Context, base58MintAddr string) (token.MintAccount, error)
+		GetTokenProgramID(ctx context.Context, base58Addr string) (common.PublicKey, error)
 		GetTokenMetadata(ctx context.Context, base58MintAddr string) (*token_metadata.Metadata, error)
 		GetMasterEditionSupply(ctx context.Context, masterMint common.PublicKey) (current, max uint64, err error)
 		GetEditionInfo(ctx context.Context, base58MintAddr string) (*token_metadata.Edition, error)
+		GetSlot(ctx context.Context) (uint64, error)
+		GetLookupTableAddresses(ctx context.Context, base58Addr string) ([]common.PublicKey, error)
 	}
 )
@@ -0,0 +1,42 @@
+package parser
+
+import (
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/program/associated_token_account"
+)
+
+// CreateAssociatedTokenAccount is the Associated Token Account program Create and CreateIdempotent instruction.
+type CreateAssociatedTokenAccount struct {
+	Funder                 common.PublicKey
+	AssociatedTokenAccount common.PublicKey
+	Owner                  common.PublicKey
+	Mint                   common.PublicKey
+	TokenProgramID         common.PublicKey
+	Idempotent             bool // the instruction does not fail if the account exists
+}
+
+// DecodeAssociatedTokenAccountInstruction decodes the Associated Token Account program instruction.
+// The instruction without data is the legacy Create instruction.
+func DecodeAssociatedTokenAccountInstruction(accounts []common.PublicKey, data []byte) (any, error) {
+	instruction := associated_token_account.InstructionCreate
+	if len(data) > 0 {
+		instruction = associated_token_account.Instruction(data[0])
+	}
+
+	switch instruction {
+	case associated_token_account.InstructionCreate, associated_token_account.InstructionCreateIdempotent:
+		if err := requireAccounts(accounts, 6); err != nil {
+			return nil, err
+		}
+		return CreateAssociatedTokenAccount{
+			Funder:                 accounts[0],
+			AssociatedTokenAccount: accounts[1],
+			Owner:                  accounts[2],
+			Mint:                   accounts[3],
+			TokenProgramID:         accounts[5],
+			Idempotent:             instruction == associated_token_account.InstructionCreateIdempotent,
+		}, nil
+	}
+
+	return nil, ErrUnknownInstruction
+}
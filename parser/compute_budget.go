@@ -0,0 +1,43 @@
+package parser
+
+import (
+	"encoding/binary"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/program/compute_budget"
+)
+
+type (
+	// SetComputeUnitLimit is the Compute Budget program SetComputeUnitLimit instruction.
+	SetComputeUnitLimit struct {
+		Units uint32
+	}
+
+	// SetComputeUnitPrice is the Compute Budget program SetComputeUnitPrice instruction.
+	SetComputeUnitPrice struct {
+		MicroLamports uint64 // price of a compute unit
+	}
+)
+
+// DecodeComputeBudgetInstruction decodes the Compute Budget program instruction.
+func DecodeComputeBudgetInstruction(_ []common.PublicKey, data []byte) (any, error) {
+	if err := requireData(data, 1); err != nil {
+		return nil, err
+	}
+
+	switch compute_budget.Instruction(data[0]) {
+	case compute_budget.InstructionSetComputeUnitLimit:
+		if err := requireData(data, 5); err != nil {
+			return nil, err
+		}
+		return SetComputeUnitLimit{Units: binary.LittleEndian.Uint32(data[1:5])}, nil
+
+	case compute_budget.InstructionSetComputeUnitPrice:
+		if err := requireData(data, 9); err != nil {
+			return nil, err
+		}
+		return SetComputeUnitPrice{MicroLamports: binary.LittleEndian.Uint64(data[1:9])}, nil
+	}
+
+	return nil, ErrUnknownInstruction
+}
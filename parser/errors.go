@@ -0,0 +1,14 @@
+package parser
+
+import "errors"
+
+// Predefined errors
+var (
+	ErrParseTransaction        = errors.New("failed to parse transaction")
+	ErrParseInstruction        = errors.New("failed to parse instruction")
+	ErrUnknownInstruction      = errors.New("unknown instruction")
+	ErrInvalidInstructionData  = errors.New("invalid instruction data")
+	ErrNotEnoughAccounts       = errors.New("not enough instruction accounts")
+	ErrInvalidAccountIndex     = errors.New("invalid account index")
+	ErrInvalidInnerInstruction = errors.New("inner instructions of unknown top-level instruction")
+)
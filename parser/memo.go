@@ -0,0 +1,26 @@
+package parser
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+)
+
+// MemoV1ProgramID is the id of the legacy Memo program, its instructions have no signers.
+var MemoV1ProgramID = common.PublicKeyFromString("Memo1UhkJRfHyvLMcVucJwxXeuD728EqVDDwQDxFMNo")
+
+// Memo is the Memo program instruction.
+type Memo struct {
+	Text    string
+	Signers []common.PublicKey
+}
+
+// DecodeMemoInstruction decodes the Memo program instruction, the memo must be a valid UTF-8 string.
+func DecodeMemoInstruction(accounts []common.PublicKey, data []byte) (any, error) {
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("%w: memo is not a valid UTF-8 string", ErrInvalidInstructionData)
+	}
+
+	return Memo{Text: string(data), Signers: accounts}, nil
+}
@@ -0,0 +1,205 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/EntySquare/solana-go-sdk/client"
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/types"
+	commonx "github.com/EntySquare/solana/common"
+	"github.com/EntySquare/solana/utils"
+)
+
+// DefaultRegistry is the registry of the package-level functions, it decodes the builtin programs.
+var DefaultRegistry = NewRegistry()
+
+type (
+	// Instruction is the instruction of the transaction with its typed value.
+	Instruction struct {
+		ProgramID common.PublicKey
+		Accounts  []common.PublicKey
+		Data      []byte
+		Parsed    any           // typed instruction, e.g. TokenTransferChecked; nil if the program or the instruction is unknown
+		Inner     []Instruction // instructions invoked by the top-level instruction
+	}
+
+	// Decoder decodes the instruction of its program into a typed value.
+	// It returns ErrUnknownInstruction if the instruction is not supported, the instruction is kept undecoded then.
+	Decoder func(accounts []common.PublicKey, data []byte) (any, error)
+
+	// Registry is the set of the instruction decoders by the program id.
+	Registry struct {
+		mu       sync.RWMutex
+		decoders map[common.PublicKey]Decoder
+	}
+)
+
+// NewRegistry creates a new registry of the builtin decoders:
+// System, SPL Token, Token-2022, Associated Token Account, Memo, Compute Budget and Metaplex Token Metadata.
+func NewRegistry() *Registry {
+	return &Registry{
+		decoders: map[common.PublicKey]Decoder{
+			common.SystemProgramID:                    DecodeSystemInstruction,
+			common.TokenProgramID:                     DecodeTokenInstruction,
+			commonx.Token2022ProgramID:                DecodeTokenInstruction,
+			common.SPLAssociatedTokenAccountProgramID: DecodeAssociatedTokenAccountInstruction,
+			common.MemoProgramID:                      DecodeMemoInstruction,
+			MemoV1ProgramID:                           DecodeMemoInstruction,
+			common.ComputeBudgetProgramID:             DecodeComputeBudgetInstruction,
+			common.MetaplexTokenMetaProgramID:         DecodeTokenMetadataInstruction,
+		},
+	}
+}
+
+// Register sets the decoder of the program instructions, it replaces the previous decoder of the program.
+func (r *Registry) Register(programID common.PublicKey, decoder Decoder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.decoders[programID] = decoder
+}
+
+// Register sets the decoder of the program instructions in the default registry.
+func Register(programID common.PublicKey, decoder Decoder) {
+	DefaultRegistry.Register(programID, decoder)
+}
+
+// ParseInstruction decodes the instruction of the program.
+// Returns the instruction or an error if the data of a known instruction is invalid.
+func (r *Registry) ParseInstruction(programID common.PublicKey, accounts []common.PublicKey, data []byte) (Instruction, error) {
+	ix := Instruction{ProgramID: programID, Accounts: accounts, Data: data}
+
+	r.mu.RLock()
+	decoder, ok := r.decoders[programID]
+	r.mu.RUnlock()
+	if !ok {
+		return ix, nil
+	}
+
+	parsed, err := decoder(accounts, data)
+	if errors.Is(err, ErrUnknownInstruction) {
+		return ix, nil
+	}
+	if err != nil {
+		return ix, utils.StackErrors(ErrParseInstruction, fmt.Errorf("program %s", programID.ToBase58()), err)
+	}
+	ix.Parsed = parsed
+
+	return ix, nil
+}
+
+// ParseInstruction decodes the instruction of the program with the default registry.
+func ParseInstruction(programID common.PublicKey, accounts []common.PublicKey, data []byte) (Instruction, error) {
+	return DefaultRegistry.ParseInstruction(programID, accounts, data)
+}
+
+// ParseTransaction decodes the instructions of the fetched transaction, e.g. returned by client.GetTransaction.
+// The inner instructions are attached to their top-level instructions.
+// Returns the top-level instructions or an error.
+func (r *Registry) ParseTransaction(tx *client.Transaction) ([]Instruction, error) {
+	accountKeys := tx.AccountKeys
+	if len(accountKeys) == 0 {
+		accountKeys = tx.Transaction.Message.Accounts
+	}
+
+	result, err := r.parseCompiled(accountKeys, tx.Transaction.Message.Instructions)
+	if err != nil {
+		return nil, utils.StackErrors(ErrParseTransaction, err)
+	}
+	if tx.Meta == nil {
+		return result, nil
+	}
+
+	for _, inner := range tx.Meta.InnerInstructions {
+		if inner.Index >= uint64(len(result)) {
+			return nil, utils.StackErrors(ErrParseTransaction, ErrInvalidInnerInstruction, fmt.Errorf("index %d", inner.Index))
+		}
+		instructions, err := r.parseCompiled(accountKeys, inner.Instructions)
+		if err != nil {
+			return nil, utils.StackErrors(ErrParseTransaction, err)
+		}
+		result[inner.Index].Inner = append(result[inner.Index].Inner, instructions...)
+	}
+
+	return result, nil
+}
+
+// ParseTransaction decodes the instructions of the fetched transaction with the default registry.
+func ParseTransaction(tx *client.Transaction) ([]Instruction, error) {
+	return DefaultRegistry.ParseTransaction(tx)
+}
+
+// ParseMessage decodes the instructions of the message, e.g. of a transaction that is not sent yet.
+// The accounts loaded from the address lookup tables can not be resolved from the message alone,
+// the message referring them fails with ErrInvalidAccountIndex.
+// Returns the instructions or an error.
+func (r *Registry) ParseMessage(message types.Message) ([]Instruction, error) {
+	result, err := r.parseCompiled(message.Accounts, message.Instructions)
+	if err != nil {
+		return nil, utils.StackErrors(ErrParseTransaction, err)
+	}
+
+	return result, nil
+}
+
+// ParseMessage decodes the instructions of the message with the default registry.
+func ParseMessage(message types.Message) ([]Instruction, error) {
+	return DefaultRegistry.ParseMessage(message)
+}
+
+// parseCompiled decodes the compiled instructions referring the account keys by index.
+func (r *Registry) parseCompiled(accountKeys []common.PublicKey, compiled []types.CompiledInstruction) ([]Instruction, error) {
+	result := make([]Instruction, 0, len(compiled))
+	for i, ci := range compiled {
+		if ci.ProgramIDIndex < 0 || ci.ProgramIDIndex >= len(accountKeys) {
+			return nil, utils.StackErrors(ErrInvalidAccountIndex, fmt.Errorf("instruction %d: program index %d", i, ci.ProgramIDIndex))
+		}
+
+		accounts := make([]common.PublicKey, len(ci.Accounts))
+		for j, index := range ci.Accounts {
+			if index < 0 || index >= len(accountKeys) {
+				return nil, utils.StackErrors(ErrInvalidAccountIndex, fmt.Errorf("instruction %d: account index %d", i, index))
+			}
+			accounts[j] = accountKeys[index]
+		}
+
+		ix, err := r.ParseInstruction(accountKeys[ci.ProgramIDIndex], accounts, ci.Data)
+		if err != nil {
+			return nil, utils.StackErrors(fmt.Errorf("instruction %d", i), err)
+		}
+		result = append(result, ix)
+	}
+
+	return result, nil
+}
+
+// Flatten returns the instructions followed by their inner instructions in the execution order.
+func Flatten(instructions []Instruction) []Instruction {
+	result := make([]Instruction, 0, len(instructions))
+	for _, ix := range instructions {
+		inner := ix.Inner
+		ix.Inner = nil
+		result = append(result, ix)
+		result = append(result, Flatten(inner)...)
+	}
+
+	return result
+}
+
+// requireAccounts checks that the instruction has at least n accounts.
+func requireAccounts(accounts []common.PublicKey, n int) error {
+	if len(accounts) < n {
+		return fmt.Errorf("%w: %d, expected %d", ErrNotEnoughAccounts, len(accounts), n)
+	}
+	return nil
+}
+
+// requireData checks that the instruction data has at least n bytes.
+func requireData(data []byte, n int) error {
+	if len(data) < n {
+		return fmt.Errorf("%w: %d bytes, expected %d", ErrInvalidInstructionData, len(data), n)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/EntySquare/solana-go-sdk/client"
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/program/associated_token_account"
+	"github.com/EntySquare/solana-go-sdk/program/memo"
+	metaplex_token_metadata "github.com/EntySquare/solana-go-sdk/program/metaplex/token_metadata"
+	"github.com/EntySquare/solana-go-sdk/program/system"
+	"github.com/EntySquare/solana-go-sdk/program/token"
+	"github.com/EntySquare/solana-go-sdk/types"
+	"github.com/EntySquare/solana/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func newMessage(payer common.PublicKey, instructions ...types.Instruction) types.Message {
+	return types.NewMessage(types.NewMessageParam{
+		FeePayer:        payer,
+		RecentBlockhash: "EkSnNWid2cvwEVnVx9aBqawnmiCNiDgp3gUdkDPTKN1N",
+		Instructions:    instructions,
+	})
+}
+
+func TestParseMessage(t *testing.T) {
+	payer := types.NewAccount().PublicKey
+	recipient := types.NewAccount().PublicKey
+	mint := types.NewAccount().PublicKey
+	source, _, err := common.FindAssociatedTokenAddress(payer, mint)
+	require.NoError(t, err)
+	destination, _, err := common.FindAssociatedTokenAddress(recipient, mint)
+	require.NoError(t, err)
+	metadata, err := metaplex_token_metadata.GetTokenMetaPubkey(mint)
+	require.NoError(t, err)
+
+	instructions, err := parser.ParseMessage(newMessage(payer,
+		system.Transfer(system.TransferParam{From: payer, To: recipient, Amount: 1000}),
+		associated_token_account.CreateIdempotent(associated_token_account.CreateIdempotentParam{
+			Funder:                 payer,
+			Owner:                  recipient,
+			Mint:                   mint,
+			AssociatedTokenAccount: destination,
+		}),
+		token.TransferChecked(token.TransferCheckedParam{
+			From: source, To: destination, Mint: mint, Auth: payer, Amount: 500, Decimals: 6,
+		}),
+		memo.BuildMemo(memo.BuildMemoParam{SignerPubkeys: []common.PublicKey{payer}, Memo: []byte("order 42")}),
+		metaplex_token_metadata.CreateMetadataAccountV3(metaplex_token_metadata.CreateMetadataAccountV3Param{
+			Metadata:        metadata,
+			Mint:            mint,
+			MintAuthority:   payer,
+			Payer:           payer,
+			UpdateAuthority: payer,
+			IsMutable:       true,
+			Data:            metaplex_token_metadata.DataV2{Name: "Test Token", Symbol: "TEST", Uri: "https://example.com"},
+		}),
+	))
+	require.NoError(t, err)
+	require.Len(t, instructions, 5)
+
+	require.Equal(t, parser.SystemTransfer{From: payer, To: recipient, Lamports: 1000}, instructions[0].Parsed)
+	require.Equal(t, parser.CreateAssociatedTokenAccount{
+		Funder:                 payer,
+		AssociatedTokenAccount: destination,
+		Owner:                  recipient,
+		Mint:                   mint,
+		TokenProgramID:         common.TokenProgramID,
+		Idempotent:             true,
+	}, instructions[1].Parsed)
+	require.Equal(t, parser.TokenTransferChecked{
+		Source: source, Mint: mint, Destination: destination, Authority: payer, Amount: 500, Decimals: 6,
+	}, instructions[2].Parsed)
+	require.Equal(t, parser.Memo{Text: "order 42", Signers: []common.PublicKey{payer}}, instructions[3].Parsed)
+
+	createMetadata, ok := instructions[4].Parsed.(parser.CreateMetadataAccountV3)
+	require.True(t, ok)
+	require.Equal(t, metadata, createMetadata.Metadata)
+	require.Equal(t, "Test Token", createMetadata.Data.Name)
+	require.True(t, createMetadata.IsMutable)
+	require.Nil(t, createMetadata.CollectionDetails)
+}
+
+func TestParseTransaction_InnerInstructions(t *testing.T) {
+	payer := types.NewAccount().PublicKey
+	mint := types.NewAccount().PublicKey
+	ata, _, err := common.FindAssociatedTokenAddress(payer, mint)
+	require.NoError(t, err)
+
+	message := newMessage(payer, associated_token_account.Create(associated_token_account.CreateParam{
+		Funder:                 payer,
+		Owner:                  payer,
+		Mint:                   mint,
+		AssociatedTokenAccount: ata,
+	}))
+	index := func(key common.PublicKey) int {
+		for i, account := range message.Accounts {
+			if account == key {
+				return i
+			}
+		}
+		t.Fatalf("account %s is not in the message", key.ToBase58())
+		return -1
+	}
+
+	tx := &client.Transaction{
+		Transaction: types.Transaction{Message: message},
+		Meta: &client.TransactionMeta{
+			InnerInstructions: []client.InnerInstruction{{
+				Index: 0,
+				Instructions: []types.CompiledInstruction{
+					{
+						ProgramIDIndex: index(common.SystemProgramID),
+						Accounts:       []int{index(payer), index(ata)},
+						Data:           system.Transfer(system.TransferParam{From: payer, To: ata, Amount: 2039280}).Data,
+					},
+					{
+						ProgramIDIndex: index(common.TokenProgramID),
+						Accounts:       []int{index(ata), index(mint)},
+						Data:           token.InitializeAccount3(token.InitializeAccount3Param{Account: ata, Mint: mint, Owner: payer}).Data,
+					},
+				},
+			}},
+		},
+	}
+
+	instructions, err := parser.ParseTransaction(tx)
+	require.NoError(t, err)
+	require.Len(t, instructions, 1)
+	require.IsType(t, parser.CreateAssociatedTokenAccount{}, instructions[0].Parsed)
+	require.Len(t, instructions[0].Inner, 2)
+	require.Equal(t, parser.SystemTransfer{From: payer, To: ata, Lamports: 2039280}, instructions[0].Inner[0].Parsed)
+	require.Equal(t, parser.TokenInitializeAccount{Account: ata, Mint: mint, Owner: payer}, instructions[0].Inner[1].Parsed)
+
+	flat := parser.Flatten(instructions)
+	require.Len(t, flat, 3)
+	require.Empty(t, flat[0].Inner)
+
+	tx.Meta.InnerInstructions[0].Index = 1
+	_, err = parser.ParseTransaction(tx)
+	require.ErrorIs(t, err, parser.ErrInvalidInnerInstruction)
+}
+
+func TestRegistry_Register(t *testing.T) {
+	type ping struct{ Count uint8 }
+	programID := types.NewAccount().PublicKey
+	payer := types.NewAccount().PublicKey
+	message := newMessage(payer, types.Instruction{ProgramID: programID, Data: []byte{7}})
+
+	registry := parser.NewRegistry()
+	instructions, err := registry.ParseMessage(message)
+	require.NoError(t, err)
+	require.Nil(t, instructions[0].Parsed)
+	require.Equal(t, []byte{7}, instructions[0].Data)
+
+	registry.Register(programID, func(_ []common.PublicKey, data []byte) (any, error) {
+		if len(data) != 1 {
+			return nil, parser.ErrInvalidInstructionData
+		}
+		return ping{Count: data[0]}, nil
+	})
+	instructions, err = registry.ParseMessage(message)
+	require.NoError(t, err)
+	require.Equal(t, ping{Count: 7}, instructions[0].Parsed)
+
+	_, err = registry.ParseMessage(newMessage(payer, types.Instruction{ProgramID: programID}))
+	require.ErrorIs(t, err, parser.ErrInvalidInstructionData)
+	require.ErrorIs(t, err, parser.ErrParseInstruction)
+
+	// the unknown instruction of a builtin program is kept undecoded
+	instructions, err = registry.ParseMessage(newMessage(payer, types.Instruction{
+		ProgramID: common.TokenProgramID,
+		Data:      []byte{255},
+	}))
+	require.NoError(t, err)
+	require.Nil(t, instructions[0].Parsed)
+}
@@ -0,0 +1,107 @@
+package parser
+
+import (
+	"encoding/binary"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/program/system"
+)
+
+type (
+	// SystemCreateAccount is the System program CreateAccount instruction.
+	SystemCreateAccount struct {
+		From     common.PublicKey // funding account
+		New      common.PublicKey // created account
+		Lamports uint64
+		Space    uint64
+		Owner    common.PublicKey // program owning the created account
+	}
+
+	// SystemAssign is the System program Assign instruction.
+	SystemAssign struct {
+		Account common.PublicKey
+		Owner   common.PublicKey
+	}
+
+	// SystemTransfer is the System program Transfer instruction.
+	SystemTransfer struct {
+		From     common.PublicKey
+		To       common.PublicKey
+		Lamports uint64
+	}
+
+	// SystemAdvanceNonceAccount is the System program AdvanceNonceAccount instruction of the durable transactions.
+	SystemAdvanceNonceAccount struct {
+		Nonce     common.PublicKey
+		Authority common.PublicKey
+	}
+
+	// SystemAllocate is the System program Allocate instruction.
+	SystemAllocate struct {
+		Account common.PublicKey
+		Space   uint64
+	}
+)
+
+// DecodeSystemInstruction decodes the System program instruction.
+func DecodeSystemInstruction(accounts []common.PublicKey, data []byte) (any, error) {
+	if err := requireData(data, 4); err != nil {
+		return nil, err
+	}
+
+	switch system.Instruction(binary.LittleEndian.Uint32(data)) {
+	case system.InstructionCreateAccount:
+		if err := requireData(data, 52); err != nil {
+			return nil, err
+		}
+		if err := requireAccounts(accounts, 2); err != nil {
+			return nil, err
+		}
+		return SystemCreateAccount{
+			From:     accounts[0],
+			New:      accounts[1],
+			Lamports: binary.LittleEndian.Uint64(data[4:12]),
+			Space:    binary.LittleEndian.Uint64(data[12:20]),
+			Owner:    common.PublicKeyFromBytes(data[20:52]),
+		}, nil
+
+	case system.InstructionAssign:
+		if err := requireData(data, 36); err != nil {
+			return nil, err
+		}
+		if err := requireAccounts(accounts, 1); err != nil {
+			return nil, err
+		}
+		return SystemAssign{Account: accounts[0], Owner: common.PublicKeyFromBytes(data[4:36])}, nil
+
+	case system.InstructionTransfer:
+		if err := requireData(data, 12); err != nil {
+			return nil, err
+		}
+		if err := requireAccounts(accounts, 2); err != nil {
+			return nil, err
+		}
+		return SystemTransfer{
+			From:     accounts[0],
+			To:       accounts[1],
+			Lamports: binary.LittleEndian.Uint64(data[4:12]),
+		}, nil
+
+	case system.InstructionAdvanceNonceAccount:
+		if err := requireAccounts(accounts, 3); err != nil {
+			return nil, err
+		}
+		return SystemAdvanceNonceAccount{Nonce: accounts[0], Authority: accounts[2]}, nil
+
+	case system.InstructionAllocate:
+		if err := requireData(data, 12); err != nil {
+			return nil, err
+		}
+		if err := requireAccounts(accounts, 1); err != nil {
+			return nil, err
+		}
+		return SystemAllocate{Account: accounts[0], Space: binary.LittleEndian.Uint64(data[4:12])}, nil
+	}
+
+	return nil, ErrUnknownInstruction
+}
@@ -0,0 +1,327 @@
+package parser
+
+import (
+	"encoding/binary"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/program/token"
+)
+
+type (
+	// TokenInitializeMint is the SPL Token InitializeMint and InitializeMint2 instruction.
+	TokenInitializeMint struct {
+		Mint            common.PublicKey
+		Decimals        uint8
+		MintAuthority   common.PublicKey
+		FreezeAuthority *common.PublicKey
+	}
+
+	// TokenInitializeAccount is the SPL Token InitializeAccount, InitializeAccount2 and InitializeAccount3 instruction.
+	TokenInitializeAccount struct {
+		Account common.PublicKey
+		Mint    common.PublicKey
+		Owner   common.PublicKey
+	}
+
+	// TokenTransfer is the SPL Token Transfer instruction.
+	TokenTransfer struct {
+		Source      common.PublicKey // source token account
+		Destination common.PublicKey // destination token account
+		Authority   common.PublicKey // owner or delegate of the source account
+		Amount      uint64
+	}
+
+	// TokenTransferChecked is the SPL Token TransferChecked instruction.
+	TokenTransferChecked struct {
+		Source      common.PublicKey // source token account
+		Mint        common.PublicKey
+		Destination common.PublicKey // destination token account
+		Authority   common.PublicKey // owner or delegate of the source account
+		Amount      uint64
+		Decimals    uint8
+	}
+
+	// TokenApprove is the SPL Token Approve and ApproveChecked instruction; Mint and Decimals are set by ApproveChecked only.
+	TokenApprove struct {
+		Source   common.PublicKey
+		Mint     *common.PublicKey
+		Delegate common.PublicKey
+		Owner    common.PublicKey
+		Amount   uint64
+		Decimals *uint8
+	}
+
+	// TokenRevoke is the SPL Token Revoke instruction.
+	TokenRevoke struct {
+		Source common.PublicKey
+		Owner  common.PublicKey
+	}
+
+	// TokenSetAuthority is the SPL Token SetAuthority instruction.
+	TokenSetAuthority struct {
+		Account          common.PublicKey // mint or token account
+		CurrentAuthority common.PublicKey
+		AuthorityType    token.AuthorityType
+		NewAuthority     *common.PublicKey // nil if the authority is removed
+	}
+
+	// TokenMintTo is the SPL Token MintTo and MintToChecked instruction; Decimals is set by MintToChecked only.
+	TokenMintTo struct {
+		Mint          common.PublicKey
+		Account       common.PublicKey // destination token account
+		MintAuthority common.PublicKey
+		Amount        uint64
+		Decimals      *uint8
+	}
+
+	// TokenBurn is the SPL Token Burn and BurnChecked instruction; Decimals is set by BurnChecked only.
+	TokenBurn struct {
+		Account   common.PublicKey // token account to burn from
+		Mint      common.PublicKey
+		Authority common.PublicKey
+		Amount    uint64
+		Decimals  *uint8
+	}
+
+	// TokenCloseAccount is the SPL Token CloseAccount instruction.
+	TokenCloseAccount struct {
+		Account     common.PublicKey
+		Destination common.PublicKey // receives the rent of the closed account
+		Authority   common.PublicKey
+	}
+
+	// TokenFreezeAccount is the SPL Token FreezeAccount instruction.
+	TokenFreezeAccount struct {
+		Account         common.PublicKey
+		Mint            common.PublicKey
+		FreezeAuthority common.PublicKey
+	}
+
+	// TokenThawAccount is the SPL Token ThawAccount instruction.
+	TokenThawAccount struct {
+		Account         common.PublicKey
+		Mint            common.PublicKey
+		FreezeAuthority common.PublicKey
+	}
+
+	// TokenSyncNative is the SPL Token SyncNative instruction of the wrapped SOL accounts.
+	TokenSyncNative struct {
+		Account common.PublicKey
+	}
+)
+
+// DecodeTokenInstruction decodes the SPL Token program instruction.
+// The Token-2022 program shares the instructions, its extension instructions are unknown.
+func DecodeTokenInstruction(accounts []common.PublicKey, data []byte) (any, error) {
+	if err := requireData(data, 1); err != nil {
+		return nil, err
+	}
+
+	switch instruction := token.Instruction(data[0]); instruction {
+	case token.InstructionInitializeMint, token.InstructionInitializeMint2:
+		if err := requireData(data, 35); err != nil {
+			return nil, err
+		}
+		if err := requireAccounts(accounts, 1); err != nil {
+			return nil, err
+		}
+		freezeAuthority, err := optionalKey(data[34:])
+		if err != nil {
+			return nil, err
+		}
+		return TokenInitializeMint{
+			Mint:            accounts[0],
+			Decimals:        data[1],
+			MintAuthority:   common.PublicKeyFromBytes(data[2:34]),
+			FreezeAuthority: freezeAuthority,
+		}, nil
+
+	case token.InstructionInitializeAccount:
+		if err := requireAccounts(accounts, 3); err != nil {
+			return nil, err
+		}
+		return TokenInitializeAccount{Account: accounts[0], Mint: accounts[1], Owner: accounts[2]}, nil
+
+	case token.InstructionInitializeAccount2, token.InstructionInitializeAccount3:
+		if err := requireData(data, 33); err != nil {
+			return nil, err
+		}
+		if err := requireAccounts(accounts, 2); err != nil {
+			return nil, err
+		}
+		return TokenInitializeAccount{Account: accounts[0], Mint: accounts[1], Owner: common.PublicKeyFromBytes(data[1:33])}, nil
+
+	case token.InstructionTransfer:
+		if err := requireData(data, 9); err != nil {
+			return nil, err
+		}
+		if err := requireAccounts(accounts, 3); err != nil {
+			return nil, err
+		}
+		return TokenTransfer{
+			Source:      accounts[0],
+			Destination: accounts[1],
+			Authority:   accounts[2],
+			Amount:      binary.LittleEndian.Uint64(data[1:9]),
+		}, nil
+
+	case token.InstructionTransferChecked:
+		if err := requireData(data, 10); err != nil {
+			return nil, err
+		}
+		if err := requireAccounts(accounts, 4); err != nil {
+			return nil, err
+		}
+		return TokenTransferChecked{
+			Source:      accounts[0],
+			Mint:        accounts[1],
+			Destination: accounts[2],
+			Authority:   accounts[3],
+			Amount:      binary.LittleEndian.Uint64(data[1:9]),
+			Decimals:    data[9],
+		}, nil
+
+	case token.InstructionApprove:
+		if err := requireData(data, 9); err != nil {
+			return nil, err
+		}
+		if err := requireAccounts(accounts, 3); err != nil {
+			return nil, err
+		}
+		return TokenApprove{
+			Source:   accounts[0],
+			Delegate: accounts[1],
+			Owner:    accounts[2],
+			Amount:   binary.LittleEndian.Uint64(data[1:9]),
+		}, nil
+
+	case token.InstructionApproveChecked:
+		if err := requireData(data, 10); err != nil {
+			return nil, err
+		}
+		if err := requireAccounts(accounts, 4); err != nil {
+			return nil, err
+		}
+		mint, decimals := accounts[1], data[9]
+		return TokenApprove{
+			Source:   accounts[0],
+			Mint:     &mint,
+			Delegate: accounts[2],
+			Owner:    accounts[3],
+			Amount:   binary.LittleEndian.Uint64(data[1:9]),
+			Decimals: &decimals,
+		}, nil
+
+	case token.InstructionRevoke:
+		if err := requireAccounts(accounts, 2); err != nil {
+			return nil, err
+		}
+		return TokenRevoke{Source: accounts[0], Owner: accounts[1]}, nil
+
+	case token.InstructionSetAuthority:
+		if err := requireData(data, 3); err != nil {
+			return nil, err
+		}
+		if err := requireAccounts(accounts, 2); err != nil {
+			return nil, err
+		}
+		newAuthority, err := optionalKey(data[2:])
+		if err != nil {
+			return nil, err
+		}
+		return TokenSetAuthority{
+			Account:          accounts[0],
+			CurrentAuthority: accounts[1],
+			AuthorityType:    token.AuthorityType(data[1]),
+			NewAuthority:     newAuthority,
+		}, nil
+
+	case token.InstructionMintTo, token.InstructionMintToChecked:
+		if err := requireData(data, 9); err != nil {
+			return nil, err
+		}
+		if err := requireAccounts(accounts, 3); err != nil {
+			return nil, err
+		}
+		decimals, err := checkedDecimals(instruction == token.InstructionMintToChecked, data)
+		if err != nil {
+			return nil, err
+		}
+		return TokenMintTo{
+			Mint:          accounts[0],
+			Account:       accounts[1],
+			MintAuthority: accounts[2],
+			Amount:        binary.LittleEndian.Uint64(data[1:9]),
+			Decimals:      decimals,
+		}, nil
+
+	case token.InstructionBurn, token.InstructionBurnChecked:
+		if err := requireData(data, 9); err != nil {
+			return nil, err
+		}
+		if err := requireAccounts(accounts, 3); err != nil {
+			return nil, err
+		}
+		decimals, err := checkedDecimals(instruction == token.InstructionBurnChecked, data)
+		if err != nil {
+			return nil, err
+		}
+		return TokenBurn{
+			Account:   accounts[0],
+			Mint:      accounts[1],
+			Authority: accounts[2],
+			Amount:    binary.LittleEndian.Uint64(data[1:9]),
+			Decimals:  decimals,
+		}, nil
+
+	case token.InstructionCloseAccount:
+		if err := requireAccounts(accounts, 3); err != nil {
+			return nil, err
+		}
+		return TokenCloseAccount{Account: accounts[0], Destination: accounts[1], Authority: accounts[2]}, nil
+
+	case token.InstructionFreezeAccount:
+		if err := requireAccounts(accounts, 3); err != nil {
+			return nil, err
+		}
+		return TokenFreezeAccount{Account: accounts[0], Mint: accounts[1], FreezeAuthority: accounts[2]}, nil
+
+	case token.InstructionThawAccount:
+		if err := requireAccounts(accounts, 3); err != nil {
+			return nil, err
+		}
+		return TokenThawAccount{Account: accounts[0], Mint: accounts[1], FreezeAuthority: accounts[2]}, nil
+
+	case token.InstructionSyncNative:
+		if err := requireAccounts(accounts, 1); err != nil {
+			return nil, err
+		}
+		return TokenSyncNative{Account: accounts[0]}, nil
+	}
+
+	return nil, ErrUnknownInstruction
+}
+
+// optionalKey decodes the optional public key of the token instruction: a bool tag and the key if it is set.
+func optionalKey(data []byte) (*common.PublicKey, error) {
+	if data[0] == 0 {
+		return nil, nil
+	}
+	if err := requireData(data, 33); err != nil {
+		return nil, err
+	}
+	key := common.PublicKeyFromBytes(data[1:33])
+	return &key, nil
+}
+
+// checkedDecimals returns the decimals of the checked instruction or nil.
+func checkedDecimals(checked bool, data []byte) (*uint8, error) {
+	if !checked {
+		return nil, nil
+	}
+	if err := requireData(data, 10); err != nil {
+		return nil, err
+	}
+	decimals := data[9]
+	return &decimals, nil
+}
@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	metaplex_token_metadata "github.com/EntySquare/solana-go-sdk/program/metaplex/token_metadata"
+	"github.com/near/borsh-go"
+)
+
+type (
+	// CreateMetadataAccountV3 is the Metaplex Token Metadata CreateMetadataAccountV3 instruction.
+	CreateMetadataAccountV3 struct {
+		Metadata          common.PublicKey
+		Mint              common.PublicKey
+		MintAuthority     common.PublicKey
+		Payer             common.PublicKey
+		UpdateAuthority   common.PublicKey
+		Data              metaplex_token_metadata.DataV2
+		IsMutable         bool
+		CollectionDetails *metaplex_token_metadata.CollectionDetails
+	}
+
+	// CreateMasterEditionV3 is the Metaplex Token Metadata CreateMasterEditionV3 instruction.
+	CreateMasterEditionV3 struct {
+		Edition         common.PublicKey
+		Mint            common.PublicKey
+		UpdateAuthority common.PublicKey
+		MintAuthority   common.PublicKey
+		Payer           common.PublicKey
+		Metadata        common.PublicKey
+		MaxSupply       *uint64 // nil if the supply of the editions is unlimited
+	}
+)
+
+// DecodeTokenMetadataInstruction decodes the Metaplex Token Metadata program instruction.
+func DecodeTokenMetadataInstruction(accounts []common.PublicKey, data []byte) (any, error) {
+	if err := requireData(data, 1); err != nil {
+		return nil, err
+	}
+
+	switch metaplex_token_metadata.Instruction(data[0]) {
+	case metaplex_token_metadata.InstructionCreateMetadataAccountV3:
+		if err := requireAccounts(accounts, 5); err != nil {
+			return nil, err
+		}
+		var args struct {
+			Instruction       metaplex_token_metadata.Instruction
+			Data              metaplex_token_metadata.DataV2
+			IsMutable         bool
+			CollectionDetails *metaplex_token_metadata.CollectionDetails
+		}
+		if err := borsh.Deserialize(&args, data); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidInstructionData, err)
+		}
+		return CreateMetadataAccountV3{
+			Metadata:          accounts[0],
+			Mint:              accounts[1],
+			MintAuthority:     accounts[2],
+			Payer:             accounts[3],
+			UpdateAuthority:   accounts[4],
+			Data:              args.Data,
+			IsMutable:         args.IsMutable,
+			CollectionDetails: args.CollectionDetails,
+		}, nil
+
+	case metaplex_token_metadata.InstructionCreateMasterEditionV3:
+		if err := requireAccounts(accounts, 6); err != nil {
+			return nil, err
+		}
+		var args struct {
+			Instruction metaplex_token_metadata.Instruction
+			MaxSupply   *uint64
+		}
+		if err := borsh.Deserialize(&args, data); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidInstructionData, err)
+		}
+		return CreateMasterEditionV3{
+			Edition:         accounts[0],
+			Mint:            accounts[1],
+			UpdateAuthority: accounts[2],
+			MintAuthority:   accounts[3],
+			Payer:           accounts[4],
+			Metadata:        accounts[5],
+			MaxSupply:       args.MaxSupply,
+		}, nil
+	}
+
+	return nil, ErrUnknownInstruction
+}
@@ -0,0 +1,63 @@
+package solanapay
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/EntySquare/solana/utils"
+)
+
+// AmountUnits converts the decimal amount of the request to the base units of the token with the decimals,
+// e.g. "0.5" SOL to 500000000 lamports. The amount must not have more fractional digits than the decimals.
+// Returns the amount or an error if the request has no amount or it does not fit the base units.
+func (r TransferRequest) AmountUnits(decimals uint8) (uint64, error) {
+	if r.Amount == "" {
+		return 0, ErrMissingAmount
+	}
+	return ParseAmount(r.Amount, decimals)
+}
+
+// ParseAmount converts the decimal amount in the user units to the base units of the token with the decimals.
+// The conversion is exact: the amount must not have more fractional digits than the decimals.
+func ParseAmount(amount string, decimals uint8) (uint64, error) {
+	if !amountPattern.MatchString(amount) {
+		return 0, utils.StackErrors(ErrInvalidAmount, fmt.Errorf("amount %q", amount))
+	}
+
+	integer, fraction, _ := strings.Cut(amount, ".")
+	if len(fraction) > int(decimals) {
+		return 0, utils.StackErrors(ErrTooManyDecimals, fmt.Errorf("amount %s, decimals %d", amount, decimals))
+	}
+	fraction += strings.Repeat("0", int(decimals)-len(fraction))
+
+	digits := strings.TrimLeft(integer+fraction, "0")
+	if digits == "" {
+		return 0, nil
+	}
+	units, err := strconv.ParseUint(digits, 10, 64)
+	if err != nil {
+		return 0, utils.StackErrors(ErrAmountOverflow, fmt.Errorf("amount %s, decimals %d", amount, decimals))
+	}
+
+	return units, nil
+}
+
+// FormatAmount formats the base units of the token with the decimals as the decimal amount in the user units,
+// without the trailing zeros, e.g. 500000000 lamports as "0.5" SOL.
+func FormatAmount(units uint64, decimals uint8) string {
+	digits := strconv.FormatUint(units, 10)
+	if decimals == 0 {
+		return digits
+	}
+	if len(digits) <= int(decimals) {
+		digits = strings.Repeat("0", int(decimals)-len(digits)+1) + digits
+	}
+
+	integer, fraction := digits[:len(digits)-int(decimals)], strings.TrimRight(digits[len(digits)-int(decimals):], "0")
+	if fraction == "" {
+		return integer
+	}
+
+	return integer + "." + fraction
+}
@@ -0,0 +1,25 @@
+package solanapay
+
+import "errors"
+
+// Predefined errors.
+var (
+	ErrInvalidURL             = errors.New("invalid solana pay url")
+	ErrInvalidScheme          = errors.New("invalid solana pay url scheme")
+	ErrInvalidRecipient       = errors.New("invalid recipient")
+	ErrInvalidAmount          = errors.New("invalid amount")
+	ErrInvalidSPLToken        = errors.New("invalid spl-token")
+	ErrInvalidReference       = errors.New("invalid reference")
+	ErrInvalidText            = errors.New("invalid label, message or memo")
+	ErrDuplicateParameter     = errors.New("duplicate url parameter")
+	ErrNotTransferRequest     = errors.New("url is not a transfer request")
+	ErrMissingAmount          = errors.New("transfer request has no amount")
+	ErrTooManyDecimals        = errors.New("amount has more decimals than the token")
+	ErrAmountOverflow         = errors.New("amount overflows the token base units")
+	ErrUnexpectedSPLToken     = errors.New("transfer request is a token transfer")
+	ErrMissingSPLToken        = errors.New("transfer request is a SOL transfer")
+	ErrInvalidTransferRequest = errors.New("invalid transfer request")
+	ErrInvalidLink            = errors.New("invalid transaction request link")
+	ErrInvalidAccount         = errors.New("invalid payer account")
+	ErrBuildTransaction       = errors.New("failed to build transaction for the order")
+)
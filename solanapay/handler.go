@@ -0,0 +1,221 @@
+package solanapay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/types"
+	"github.com/EntySquare/solana/client"
+	"github.com/EntySquare/solana/instructions"
+	"github.com/EntySquare/solana/transaction"
+	"github.com/EntySquare/solana/utils"
+)
+
+const (
+	// maxRequestBodySize is the max size of the POST request body; the body has only the payer account.
+	maxRequestBodySize = 1 << 12
+	// internalErrorMessage is the response error of the failed request, the details are passed to the error handler.
+	internalErrorMessage = "failed to build transaction"
+)
+
+type (
+	// TransactionRequestHandler is the http.Handler of the Solana Pay transaction request endpoint.
+	// GET returns the label and the icon of the merchant; POST builds the transaction of the order
+	// for the payer account, partially signed by the merchant.
+	TransactionRequestHandler struct {
+		client   *client.Client
+		label    string
+		icon     string
+		order    OrderFunc
+		merchant *types.Account
+		builder  func(tb *transaction.TransactionBuilder)
+		onError  func(r *http.Request, err error)
+	}
+
+	// TransactionRequestOption is a function that configures the TransactionRequestHandler.
+	TransactionRequestOption func(*TransactionRequestHandler)
+
+	// OrderFunc produces the transaction of the order for the payer.
+	// The request is the POST request of the wallet, e.g. with the order id in the query of the link.
+	// The error wrapping ErrInvalidOrder is reported to the wallet with 400 Bad Request;
+	// any other error is reported as 500 Internal Server Error with a generic message, see WithErrorHandler.
+	OrderFunc func(ctx context.Context, payer common.PublicKey, r *http.Request) (Order, error)
+
+	// Order is the transaction of the order.
+	Order struct {
+		Instructions []instructions.InstructionFunc // required; the instructions of the transaction
+		Signers      []types.Account                // optional; more signers of the transaction, e.g. a new mint account
+		Message      string                         // optional; shown by the wallet, e.g. "Thanks for your order!"
+	}
+
+	// TransactionRequestMetadata is the response of the GET request.
+	TransactionRequestMetadata struct {
+		Label string `json:"label"`
+		Icon  string `json:"icon"`
+	}
+
+	// TransactionRequestBody is the body of the POST request.
+	TransactionRequestBody struct {
+		Account string `json:"account"`
+	}
+
+	// TransactionResponse is the response of the POST request.
+	TransactionResponse struct {
+		Transaction string `json:"transaction"` // base64 encoded transaction, the payer is the fee payer
+		Message     string `json:"message,omitempty"`
+	}
+
+	// ErrorResponse is the response of the failed request.
+	ErrorResponse struct {
+		Error string `json:"error"`
+	}
+)
+
+// ErrInvalidOrder is returned by the OrderFunc if the order of the request can not be paid, e.g. it is unknown.
+var ErrInvalidOrder = errors.New("invalid order")
+
+// NewTransactionRequestHandler creates the transaction request handler.
+// The label and the icon, the absolute URL of an SVG, PNG or WebP image, describe the merchant in the wallet.
+// The client builds the transactions with the latest blockhash.
+func NewTransactionRequestHandler(c *client.Client, label, icon string, order OrderFunc, opts ...TransactionRequestOption) *TransactionRequestHandler {
+	if c == nil {
+		panic("solana client is required")
+	}
+	if label == "" || icon == "" {
+		panic("label and icon are required")
+	}
+	if order == nil {
+		panic("order func is required")
+	}
+
+	h := &TransactionRequestHandler{client: c, label: label, icon: icon, order: order}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// WithMerchantSigner sets the merchant account that partially signs every transaction.
+// The instructions of the orders must require the merchant signature.
+func WithMerchantSigner(merchant types.Account) TransactionRequestOption {
+	return func(h *TransactionRequestHandler) {
+		if h.merchant != nil {
+			panic("merchant signer is already set")
+		}
+		h.merchant = &merchant
+	}
+}
+
+// WithTransactionBuilder configures the builder of every transaction, e.g. the compute unit price.
+func WithTransactionBuilder(configure func(tb *transaction.TransactionBuilder)) TransactionRequestOption {
+	return func(h *TransactionRequestHandler) {
+		if h.builder != nil {
+			panic("transaction builder is already set")
+		}
+		h.builder = configure
+	}
+}
+
+// WithErrorHandler sets the handler of the internal errors, e.g. to log them.
+// The wallet gets only a generic error message, since the details may expose the RPC endpoint and its API key.
+func WithErrorHandler(handler func(r *http.Request, err error)) TransactionRequestOption {
+	return func(h *TransactionRequestHandler) {
+		if h.onError != nil {
+			panic("error handler is already set")
+		}
+		h.onError = handler
+	}
+}
+
+// ServeHTTP implements the http.Handler interface.
+// The wallets may call the endpoint from the browser, so CORS is allowed for any origin.
+func (h *TransactionRequestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, TransactionRequestMetadata{Label: h.label, Icon: h.icon})
+	case http.MethodPost:
+		h.servePost(w, r)
+	case http.MethodOptions:
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.Header().Set("Allow", "GET, POST, OPTIONS")
+		writeJSON(w, http.StatusMethodNotAllowed, ErrorResponse{Error: fmt.Sprintf("method %s is not allowed", r.Method)})
+	}
+}
+
+// servePost builds the transaction of the order for the payer account of the request body.
+func (h *TransactionRequestHandler) servePost(w http.ResponseWriter, r *http.Request) {
+	var body TransactionRequestBody
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize)).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: utils.StackErrors(ErrInvalidAccount, err).Error()})
+		return
+	}
+	payer, err := parsePublicKey(body.Account)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: utils.StackErrors(ErrInvalidAccount, err).Error()})
+		return
+	}
+
+	response, err := h.BuildTransaction(r.Context(), payer, r)
+	switch {
+	case errors.Is(err, ErrInvalidOrder):
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case err != nil:
+		if h.onError != nil {
+			h.onError(r, err)
+		}
+		writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: internalErrorMessage})
+	default:
+		writeJSON(w, http.StatusOK, response)
+	}
+}
+
+// BuildTransaction builds the transaction of the order for the payer, the fee payer of the transaction.
+// The transaction is partially signed by the merchant and the signers of the order; the payer signs it in the wallet.
+// Returns the response or an error.
+func (h *TransactionRequestHandler) BuildTransaction(ctx context.Context, payer common.PublicKey, r *http.Request) (TransactionResponse, error) {
+	order, err := h.order(ctx, payer, r)
+	if err != nil {
+		return TransactionResponse{}, err
+	}
+	if len(order.Instructions) == 0 {
+		return TransactionResponse{}, utils.StackErrors(ErrBuildTransaction, fmt.Errorf("order has no instructions"))
+	}
+
+	tb := transaction.NewTransactionBuilder(h.client).SetFeePayer(payer)
+	if h.builder != nil {
+		h.builder(tb)
+	}
+	for _, instruction := range order.Instructions {
+		tb.AddInstruction(instruction)
+	}
+	if h.merchant != nil {
+		tb.AddSigner(*h.merchant)
+	}
+	for _, signer := range order.Signers {
+		tb.AddSigner(signer)
+	}
+
+	tx, err := tb.Build(ctx)
+	if err != nil {
+		return TransactionResponse{}, utils.StackErrors(ErrBuildTransaction, err)
+	}
+
+	return TransactionResponse{Transaction: tx, Message: order.Message}, nil
+}
+
+// writeJSON writes the JSON response with the status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
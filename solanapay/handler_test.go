@@ -0,0 +1,170 @@
+package solanapay_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/types"
+	"github.com/EntySquare/solana/client"
+	"github.com/EntySquare/solana/instructions"
+	"github.com/EntySquare/solana/parser"
+	"github.com/EntySquare/solana/solanapay"
+	"github.com/EntySquare/solana/tests/mock"
+	"github.com/EntySquare/solana/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionRequestHandler(t *testing.T) {
+	fake := mock.NewRPCServer()
+	defer fake.Close()
+
+	merchant := types.NewAccount()
+	payer := types.NewAccount().PublicKey
+	reference := types.NewAccount().PublicKey
+
+	handler := solanapay.NewTransactionRequestHandler(
+		client.New(client.SetSolanaEndpoint(fake.URL)),
+		"Michael's Coffee",
+		"https://example.com/icon.svg",
+		func(_ context.Context, payer common.PublicKey, r *http.Request) (solanapay.Order, error) {
+			orderID := r.URL.Query().Get("order")
+			if orderID == "" {
+				return solanapay.Order{}, fmt.Errorf("%w: missing order id", solanapay.ErrInvalidOrder)
+			}
+			return solanapay.Order{
+				Instructions: []instructions.InstructionFunc{
+					instructions.Memo("order "+orderID, merchant.PublicKey),
+					instructions.TransferSOL(instructions.TransferSOLParams{
+						Sender:     payer,
+						Recipient:  merchant.PublicKey,
+						Amount:     1_000_000,
+						References: []common.PublicKey{reference},
+					}),
+				},
+				Message: "Thanks for your order!",
+			}, nil
+		},
+		solanapay.WithMerchantSigner(merchant),
+	)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "*", resp.Header.Get("Access-Control-Allow-Origin"))
+	var metadata solanapay.TransactionRequestMetadata
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&metadata))
+	require.Equal(t, solanapay.TransactionRequestMetadata{Label: "Michael's Coffee", Icon: "https://example.com/icon.svg"}, metadata)
+
+	post := func(query, body string) *http.Response {
+		resp, err := http.Post(server.URL+query, "application/json", strings.NewReader(body))
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	resp = post("?order=42", `{"account":"`+payer.ToBase58()+`"}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var response solanapay.TransactionResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+	require.Equal(t, "Thanks for your order!", response.Message)
+
+	tx, err := utils.DecodeTransaction(response.Transaction)
+	require.NoError(t, err)
+	require.Equal(t, payer, tx.Message.Accounts[0])
+	require.Equal(t, mock.DefaultBlockhash, tx.Message.RecentBlockHash)
+	require.Len(t, tx.Signatures, 2)
+	require.Equal(t, make([]byte, 64), []byte(tx.Signatures[0]), "the payer signs in the wallet")
+	message, err := tx.Message.Serialize()
+	require.NoError(t, err)
+	require.True(t, ed25519.Verify(merchant.PublicKey.Bytes(), message, tx.Signatures[1]))
+
+	parsed, err := parser.ParseMessage(tx.Message)
+	require.NoError(t, err)
+	require.Equal(t, parser.Memo{Text: "order 42", Signers: []common.PublicKey{merchant.PublicKey}}, parsed[0].Parsed)
+	require.Equal(t, parser.SystemTransfer{From: payer, To: merchant.PublicKey, Lamports: 1_000_000}, parsed[1].Parsed)
+	require.Contains(t, parsed[1].Accounts, reference)
+
+	for name, tc := range map[string]struct {
+		query, body string
+		status      int
+		err         error
+	}{
+		"invalid body":    {"?order=42", `{"account":`, http.StatusBadRequest, solanapay.ErrInvalidAccount},
+		"invalid account": {"?order=42", `{"account":"payer"}`, http.StatusBadRequest, solanapay.ErrInvalidAccount},
+		"invalid order":   {"", `{"account":"` + payer.ToBase58() + `"}`, http.StatusBadRequest, solanapay.ErrInvalidOrder},
+	} {
+		resp := post(tc.query, tc.body)
+		require.Equal(t, tc.status, resp.StatusCode, name)
+		var errResponse solanapay.ErrorResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&errResponse), name)
+		require.Contains(t, errResponse.Error, tc.err.Error(), name)
+	}
+
+	req, err := http.NewRequest(http.MethodPut, server.URL, nil)
+	require.NoError(t, err)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	require.Equal(t, "GET, POST, OPTIONS", resp.Header.Get("Allow"))
+}
+
+func TestTransactionRequestHandler_InternalError(t *testing.T) {
+	fake := mock.NewRPCServer()
+	defer fake.Close()
+
+	var handled error
+	handler := solanapay.NewTransactionRequestHandler(
+		client.New(client.SetSolanaEndpoint(fake.URL)),
+		"Michael's Coffee",
+		"https://example.com/icon.svg",
+		func(context.Context, common.PublicKey, *http.Request) (solanapay.Order, error) {
+			return solanapay.Order{}, fmt.Errorf("rpc https://rpc.example.com/?api-key=secret is down")
+		},
+		solanapay.WithErrorHandler(func(_ *http.Request, err error) { handled = err }),
+	)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL, "application/json", strings.NewReader(`{"account":"`+types.NewAccount().PublicKey.ToBase58()+`"}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+
+	var errResponse solanapay.ErrorResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&errResponse))
+	require.Equal(t, "failed to build transaction", errResponse.Error)
+	require.ErrorContains(t, handled, "api-key=secret")
+}
+
+func TestTransactionRequestURL(t *testing.T) {
+	link, err := solanapay.EncodeTransactionRequest("https://example.com/pay")
+	require.NoError(t, err)
+	require.Equal(t, "solana:https://example.com/pay", link)
+
+	link, err = solanapay.EncodeTransactionRequest("https://example.com/pay?order=42&label=a b")
+	require.NoError(t, err)
+	require.Equal(t, "solana:https%3A%2F%2Fexample.com%2Fpay%3Forder%3D42%26label%3Da%20b", link)
+
+	parsed, err := solanapay.ParseTransactionRequest(link)
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/pay?order=42&label=a b", parsed)
+
+	_, err = solanapay.ParseTransferRequest(link)
+	require.ErrorIs(t, err, solanapay.ErrNotTransferRequest)
+
+	_, err = solanapay.EncodeTransactionRequest("http://example.com/pay")
+	require.ErrorIs(t, err, solanapay.ErrInvalidLink)
+	_, err = solanapay.ParseTransactionRequest("solana:mvines9iiHiQTysrwkJjGf2gb9Ex9jXJX8ns3qwf2kN")
+	require.ErrorIs(t, err, solanapay.ErrInvalidLink)
+}
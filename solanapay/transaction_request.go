@@ -0,0 +1,57 @@
+package solanapay
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/EntySquare/solana/utils"
+)
+
+// EncodeTransactionRequest encodes the https link of the transaction request endpoint to the solana: URL.
+// The link with the query parameters is URL-encoded, so the wallet keeps them.
+// Returns the URL or an error if the link is not an absolute https URL.
+func EncodeTransactionRequest(link string) (string, error) {
+	u, err := parseLink(link)
+	if err != nil {
+		return "", err
+	}
+	if u.RawQuery != "" {
+		// like encodeURIComponent, the spaces are %20, since the link is decoded as a path
+		return Scheme + ":" + strings.ReplaceAll(url.QueryEscape(link), "+", "%20"), nil
+	}
+
+	return Scheme + ":" + link, nil
+}
+
+// ParseTransactionRequest parses the solana: transaction request URL.
+// Returns the https link of the endpoint or an error if the URL is not a valid transaction request.
+func ParseTransactionRequest(rawURL string) (string, error) {
+	if !strings.HasPrefix(rawURL, Scheme+":") {
+		return "", utils.StackErrors(ErrInvalidScheme, fmt.Errorf("url %q", rawURL))
+	}
+
+	// the link is URL-encoded if it has the query parameters
+	link, err := url.PathUnescape(strings.TrimPrefix(rawURL, Scheme+":"))
+	if err != nil {
+		return "", utils.StackErrors(ErrInvalidURL, err)
+	}
+	if _, err := parseLink(link); err != nil {
+		return "", err
+	}
+
+	return link, nil
+}
+
+// parseLink parses the link of the transaction request endpoint.
+func parseLink(link string) (*url.URL, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, utils.StackErrors(ErrInvalidLink, err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return nil, utils.StackErrors(ErrInvalidLink, fmt.Errorf("link %q is not an absolute https url", link))
+	}
+
+	return u, nil
+}
@@ -0,0 +1,251 @@
+// Package solanapay encodes and parses the Solana Pay URLs.
+// See https://docs.solanapay.com/spec for the specification.
+package solanapay
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana/instructions"
+	"github.com/EntySquare/solana/types"
+	"github.com/EntySquare/solana/utils"
+	"github.com/mr-tron/base58"
+)
+
+// Scheme is the scheme of the Solana Pay URLs.
+const Scheme = "solana"
+
+// Parameters of the transfer request URL.
+const (
+	ParamAmount    = "amount"
+	ParamSPLToken  = "spl-token"
+	ParamReference = "reference"
+	ParamLabel     = "label"
+	ParamMessage   = "message"
+	ParamMemo      = "memo"
+)
+
+// SOLDecimals is the number of decimals of the SOL amount.
+const SOLDecimals = types.SPLTokenDefaultDecimals
+
+// amountPattern is a non-negative decimal number without leading zeros and exponent, e.g. "0.5".
+var amountPattern = regexp.MustCompile(`^(0|[1-9][0-9]*)(\.[0-9]+)?$`)
+
+// TransferRequest is the Solana Pay transfer request: a non-interactive request for a SOL or token transfer.
+type TransferRequest struct {
+	Recipient  common.PublicKey   // required; the wallet to receive SOL, or the owner of the token account
+	Amount     string             // optional; decimal amount in the user units, e.g. "0.5" SOL; empty lets the wallet ask for it
+	SPLToken   *common.PublicKey  // optional; the token mint, nil for SOL
+	References []common.PublicKey // optional; the keys added to the transfer instruction to find the transaction
+	Label      string             // optional; the merchant, e.g. "Michael's Coffee"
+	Message    string             // optional; the purchase, e.g. "Thanks for your order!"
+	Memo       string             // optional; added to the transaction with the memo instruction, public on the chain
+}
+
+// Validate validates the transfer request.
+func (r TransferRequest) Validate() error {
+	if r.Recipient == (common.PublicKey{}) {
+		return utils.StackErrors(ErrInvalidTransferRequest, ErrInvalidRecipient)
+	}
+	if r.Amount != "" && !amountPattern.MatchString(r.Amount) {
+		return utils.StackErrors(ErrInvalidTransferRequest, ErrInvalidAmount, fmt.Errorf("amount %q", r.Amount))
+	}
+	if r.SPLToken != nil && *r.SPLToken == (common.PublicKey{}) {
+		return utils.StackErrors(ErrInvalidTransferRequest, ErrInvalidSPLToken)
+	}
+	for _, reference := range r.References {
+		if reference == (common.PublicKey{}) {
+			return utils.StackErrors(ErrInvalidTransferRequest, ErrInvalidReference)
+		}
+	}
+	for _, text := range []string{r.Label, r.Message, r.Memo} {
+		if !utf8.ValidString(text) {
+			return utils.StackErrors(ErrInvalidTransferRequest, ErrInvalidText, fmt.Errorf("text %q is not utf-8", text))
+		}
+	}
+
+	return nil
+}
+
+// Encode encodes the transfer request to the solana: URL.
+// Returns the URL or an error if the request is invalid.
+func (r TransferRequest) Encode() (string, error) {
+	if err := r.Validate(); err != nil {
+		return "", err
+	}
+
+	// the parameters keep the order of the specification, unlike url.Values.Encode
+	var params []string
+	add := func(key, value string) {
+		params = append(params, key+"="+url.QueryEscape(value))
+	}
+	if r.Amount != "" {
+		add(ParamAmount, r.Amount)
+	}
+	if r.SPLToken != nil {
+		add(ParamSPLToken, r.SPLToken.ToBase58())
+	}
+	for _, reference := range r.References {
+		add(ParamReference, reference.ToBase58())
+	}
+	if r.Label != "" {
+		add(ParamLabel, r.Label)
+	}
+	if r.Message != "" {
+		add(ParamMessage, r.Message)
+	}
+	if r.Memo != "" {
+		add(ParamMemo, r.Memo)
+	}
+
+	result := Scheme + ":" + r.Recipient.ToBase58()
+	if len(params) > 0 {
+		result += "?" + strings.Join(params, "&")
+	}
+
+	return result, nil
+}
+
+// ParseTransferRequest parses the solana: transfer request URL.
+// The parameters, except the repeatable reference, must be set at most once; the unknown ones are ignored.
+// Returns the transfer request or an error if the URL is not a valid transfer request.
+func ParseTransferRequest(rawURL string) (TransferRequest, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return TransferRequest{}, utils.StackErrors(ErrInvalidURL, err)
+	}
+	if u.Scheme != Scheme {
+		return TransferRequest{}, utils.StackErrors(ErrInvalidScheme, fmt.Errorf("scheme %q", u.Scheme))
+	}
+	if u.Opaque == "" {
+		// solana://... has a host, not the recipient
+		return TransferRequest{}, utils.StackErrors(ErrInvalidURL, ErrInvalidRecipient)
+	}
+	if strings.HasPrefix(strings.ToLower(u.Opaque), "https") {
+		return TransferRequest{}, ErrNotTransferRequest
+	}
+
+	var req TransferRequest
+	if req.Recipient, err = parsePublicKey(u.Opaque); err != nil {
+		return TransferRequest{}, utils.StackErrors(ErrInvalidURL, ErrInvalidRecipient, err)
+	}
+
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return TransferRequest{}, utils.StackErrors(ErrInvalidURL, err)
+	}
+	single := func(key string) (string, error) {
+		values := query[key]
+		if len(values) > 1 {
+			return "", utils.StackErrors(ErrInvalidURL, ErrDuplicateParameter, fmt.Errorf("parameter %s", key))
+		}
+		if len(values) == 0 {
+			return "", nil
+		}
+		return values[0], nil
+	}
+
+	if req.Amount, err = single(ParamAmount); err != nil {
+		return TransferRequest{}, err
+	}
+	if _, ok := query[ParamAmount]; ok && !amountPattern.MatchString(req.Amount) {
+		return TransferRequest{}, utils.StackErrors(ErrInvalidURL, ErrInvalidAmount, fmt.Errorf("amount %q", req.Amount))
+	}
+
+	splToken, err := single(ParamSPLToken)
+	if err != nil {
+		return TransferRequest{}, err
+	}
+	if _, ok := query[ParamSPLToken]; ok {
+		mint, err := parsePublicKey(splToken)
+		if err != nil {
+			return TransferRequest{}, utils.StackErrors(ErrInvalidURL, ErrInvalidSPLToken, err)
+		}
+		req.SPLToken = &mint
+	}
+
+	for _, value := range query[ParamReference] {
+		reference, err := parsePublicKey(value)
+		if err != nil {
+			return TransferRequest{}, utils.StackErrors(ErrInvalidURL, ErrInvalidReference, err)
+		}
+		req.References = append(req.References, reference)
+	}
+
+	for key, field := range map[string]*string{ParamLabel: &req.Label, ParamMessage: &req.Message, ParamMemo: &req.Memo} {
+		if *field, err = single(key); err != nil {
+			return TransferRequest{}, err
+		}
+		if !utf8.ValidString(*field) {
+			return TransferRequest{}, utils.StackErrors(ErrInvalidURL, ErrInvalidText, fmt.Errorf("parameter %s is not utf-8", key))
+		}
+	}
+
+	return req, nil
+}
+
+// TransferSOLParams returns the params of instructions.TransferSOL from the sender with the references attached.
+// The memo, if any, is added separately with instructions.Memo before the transfer.
+// Returns an error if the request is a token transfer or has no amount.
+func (r TransferRequest) TransferSOLParams(sender common.PublicKey) (instructions.TransferSOLParams, error) {
+	if err := r.Validate(); err != nil {
+		return instructions.TransferSOLParams{}, err
+	}
+	if r.SPLToken != nil {
+		return instructions.TransferSOLParams{}, ErrUnexpectedSPLToken
+	}
+	amount, err := r.AmountUnits(SOLDecimals)
+	if err != nil {
+		return instructions.TransferSOLParams{}, err
+	}
+
+	return instructions.TransferSOLParams{
+		Sender:     sender,
+		Recipient:  r.Recipient,
+		Amount:     amount,
+		References: r.References,
+	}, nil
+}
+
+// TransferTokenParams returns the params of instructions.TransferToken from the sender with the references attached.
+// The decimals of the spl-token mint convert the amount to the base units.
+// The memo, if any, is added separately with instructions.Memo before the transfer.
+// Returns an error if the request is a SOL transfer or has no amount.
+func (r TransferRequest) TransferTokenParams(sender common.PublicKey, decimals uint8) (instructions.TransferTokenParam, error) {
+	if err := r.Validate(); err != nil {
+		return instructions.TransferTokenParam{}, err
+	}
+	if r.SPLToken == nil {
+		return instructions.TransferTokenParam{}, ErrMissingSPLToken
+	}
+	amount, err := r.AmountUnits(decimals)
+	if err != nil {
+		return instructions.TransferTokenParam{}, err
+	}
+
+	return instructions.TransferTokenParam{
+		Sender:     sender,
+		Recipient:  r.Recipient,
+		Mint:       *r.SPLToken,
+		Amount:     amount,
+		References: r.References,
+	}, nil
+}
+
+// parsePublicKey decodes the base58 encoded public key.
+// The key is not required to be on the curve, e.g. the recipient may be a program derived address.
+func parsePublicKey(s string) (common.PublicKey, error) {
+	data, err := base58.Decode(s)
+	if err != nil {
+		return common.PublicKey{}, fmt.Errorf("public key %q: %w", s, err)
+	}
+	if len(data) != common.PublicKeyLength {
+		return common.PublicKey{}, fmt.Errorf("public key %q has %d bytes, want %d", s, len(data), common.PublicKeyLength)
+	}
+
+	return common.PublicKeyFromBytes(data), nil
+}
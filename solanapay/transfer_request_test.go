@@ -0,0 +1,163 @@
+package solanapay_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/types"
+	"github.com/EntySquare/solana/instructions"
+	"github.com/EntySquare/solana/solanapay"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferRequest_EncodeParse(t *testing.T) {
+	recipient := common.PublicKeyFromString("mvines9iiHiQTysrwkJjGf2gb9Ex9jXJX8ns3qwf2kN")
+	mint := common.PublicKeyFromString("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v")
+	reference1 := common.PublicKeyFromString("82ZJ7nbGpixjeDCmEhUcmwXYfvurzAgGdtSMuHnUgyny")
+	reference2 := common.PublicKeyFromString("FpWGkhb7oTxfSHyA5nKZd5qt8rEcUdzG9nMRTRd5zZm5")
+
+	req := solanapay.TransferRequest{
+		Recipient:  recipient,
+		Amount:     "0.01",
+		SPLToken:   &mint,
+		References: []common.PublicKey{reference1, reference2},
+		Label:      "Michael's Coffee",
+		Message:    "Thanks for all the fish",
+		Memo:       "OrderId#12345",
+	}
+	link, err := req.Encode()
+	require.NoError(t, err)
+	require.Equal(t, "solana:mvines9iiHiQTysrwkJjGf2gb9Ex9jXJX8ns3qwf2kN"+
+		"?amount=0.01"+
+		"&spl-token=EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v"+
+		"&reference=82ZJ7nbGpixjeDCmEhUcmwXYfvurzAgGdtSMuHnUgyny"+
+		"&reference=FpWGkhb7oTxfSHyA5nKZd5qt8rEcUdzG9nMRTRd5zZm5"+
+		"&label=Michael%27s+Coffee"+
+		"&message=Thanks+for+all+the+fish"+
+		"&memo=OrderId%2312345", link)
+
+	parsed, err := solanapay.ParseTransferRequest(link)
+	require.NoError(t, err)
+	require.Equal(t, req, parsed)
+
+	// the spec examples encode the spaces with %20
+	parsed, err = solanapay.ParseTransferRequest("solana:mvines9iiHiQTysrwkJjGf2gb9Ex9jXJX8ns3qwf2kN?amount=1&label=Michael&message=Thanks%20for%20all%20the%20fish&memo=OrderId12345")
+	require.NoError(t, err)
+	require.Equal(t, "Thanks for all the fish", parsed.Message)
+	require.Nil(t, parsed.SPLToken)
+	require.Empty(t, parsed.References)
+
+	link, err = solanapay.TransferRequest{Recipient: recipient}.Encode()
+	require.NoError(t, err)
+	require.Equal(t, "solana:mvines9iiHiQTysrwkJjGf2gb9Ex9jXJX8ns3qwf2kN", link)
+
+	for name, tc := range map[string]struct {
+		url string
+		err error
+	}{
+		"scheme":             {"bitcoin:mvines9iiHiQTysrwkJjGf2gb9Ex9jXJX8ns3qwf2kN", solanapay.ErrInvalidScheme},
+		"host":               {"solana://mvines9iiHiQTysrwkJjGf2gb9Ex9jXJX8ns3qwf2kN", solanapay.ErrInvalidRecipient},
+		"recipient":          {"solana:mvines9iiHiQTysrwkJjGf2gb9Ex9jXJX8ns3qwf2k0", solanapay.ErrInvalidRecipient},
+		"short recipient":    {"solana:mvines9iiHiQTysrwkJjGf2gb9Ex9", solanapay.ErrInvalidRecipient},
+		"transaction":        {"solana:https%3A%2F%2Fexample.com%2Fpay", solanapay.ErrNotTransferRequest},
+		"leading dot":        {"solana:mvines9iiHiQTysrwkJjGf2gb9Ex9jXJX8ns3qwf2kN?amount=.5", solanapay.ErrInvalidAmount},
+		"leading zero":       {"solana:mvines9iiHiQTysrwkJjGf2gb9Ex9jXJX8ns3qwf2kN?amount=01", solanapay.ErrInvalidAmount},
+		"exponent":           {"solana:mvines9iiHiQTysrwkJjGf2gb9Ex9jXJX8ns3qwf2kN?amount=1e3", solanapay.ErrInvalidAmount},
+		"negative":           {"solana:mvines9iiHiQTysrwkJjGf2gb9Ex9jXJX8ns3qwf2kN?amount=-1", solanapay.ErrInvalidAmount},
+		"empty amount":       {"solana:mvines9iiHiQTysrwkJjGf2gb9Ex9jXJX8ns3qwf2kN?amount=", solanapay.ErrInvalidAmount},
+		"duplicate amount":   {"solana:mvines9iiHiQTysrwkJjGf2gb9Ex9jXJX8ns3qwf2kN?amount=1&amount=2", solanapay.ErrDuplicateParameter},
+		"spl-token":          {"solana:mvines9iiHiQTysrwkJjGf2gb9Ex9jXJX8ns3qwf2kN?spl-token=usdc", solanapay.ErrInvalidSPLToken},
+		"reference":          {"solana:mvines9iiHiQTysrwkJjGf2gb9Ex9jXJX8ns3qwf2kN?reference=", solanapay.ErrInvalidReference},
+		"duplicate label":    {"solana:mvines9iiHiQTysrwkJjGf2gb9Ex9jXJX8ns3qwf2kN?label=a&label=b", solanapay.ErrDuplicateParameter},
+		"invalid utf-8 memo": {"solana:mvines9iiHiQTysrwkJjGf2gb9Ex9jXJX8ns3qwf2kN?memo=%ff", solanapay.ErrInvalidText},
+	} {
+		_, err := solanapay.ParseTransferRequest(tc.url)
+		require.ErrorIs(t, err, tc.err, name)
+	}
+
+	_, err = solanapay.TransferRequest{Recipient: recipient, Amount: "1.5.0"}.Encode()
+	require.ErrorIs(t, err, solanapay.ErrInvalidAmount)
+}
+
+func TestParseAmount(t *testing.T) {
+	for _, tc := range []struct {
+		amount   string
+		decimals uint8
+		units    uint64
+		err      error
+	}{
+		{"0", 9, 0, nil},
+		{"1", 9, 1_000_000_000, nil},
+		{"0.5", 9, 500_000_000, nil},
+		{"0.000000001", 9, 1, nil},
+		{"1.50", 2, 150, nil},
+		{"42", 0, 42, nil},
+		{"18446744073709551615", 0, math.MaxUint64, nil},
+		{"18446744073709551616", 0, 0, solanapay.ErrAmountOverflow},
+		{"18446744073.709551616", 9, 0, solanapay.ErrAmountOverflow},
+		{"0.0000000001", 9, 0, solanapay.ErrTooManyDecimals},
+		{"1.5", 0, 0, solanapay.ErrTooManyDecimals},
+		{"1,5", 9, 0, solanapay.ErrInvalidAmount},
+	} {
+		units, err := solanapay.ParseAmount(tc.amount, tc.decimals)
+		if tc.err != nil {
+			require.ErrorIs(t, err, tc.err, tc.amount)
+			continue
+		}
+		require.NoError(t, err, tc.amount)
+		require.Equal(t, tc.units, units, tc.amount)
+		if units > 0 {
+			// the trailing zeros are not kept
+			parsed, err := solanapay.ParseAmount(solanapay.FormatAmount(units, tc.decimals), tc.decimals)
+			require.NoError(t, err)
+			require.Equal(t, units, parsed)
+		}
+	}
+
+	require.Equal(t, "0.5", solanapay.FormatAmount(500_000_000, 9))
+	require.Equal(t, "0.000000001", solanapay.FormatAmount(1, 9))
+	require.Equal(t, "12", solanapay.FormatAmount(1200, 2))
+	require.Equal(t, "0", solanapay.FormatAmount(0, 6))
+}
+
+func TestTransferRequest_TransferParams(t *testing.T) {
+	sender := types.NewAccount().PublicKey
+	recipient := types.NewAccount().PublicKey
+	mint := types.NewAccount().PublicKey
+	references := []common.PublicKey{types.NewAccount().PublicKey, types.NewAccount().PublicKey}
+
+	req := solanapay.TransferRequest{Recipient: recipient, Amount: "0.25", References: references}
+	solParams, err := req.TransferSOLParams(sender)
+	require.NoError(t, err)
+	require.EqualValues(t, 250_000_000, solParams.Amount)
+	require.Equal(t, recipient, solParams.Recipient)
+
+	_, err = req.TransferTokenParams(sender, 6)
+	require.ErrorIs(t, err, solanapay.ErrMissingSPLToken)
+
+	// the references are the trailing read-only accounts of the transfer instruction
+	built, err := instructions.TransferSOL(solParams)(context.Background(), nil)
+	require.NoError(t, err)
+	accounts := built[0].Accounts
+	require.Len(t, accounts, 4)
+	require.Equal(t, references[0], accounts[2].PubKey)
+	require.Equal(t, references[1], accounts[3].PubKey)
+	require.False(t, accounts[3].IsWritable)
+	require.False(t, accounts[3].IsSigner)
+
+	req.SPLToken = &mint
+	tokenParams, err := req.TransferTokenParams(sender, 6)
+	require.NoError(t, err)
+	require.EqualValues(t, 250_000, tokenParams.Amount)
+	require.Equal(t, mint, tokenParams.Mint)
+	require.Equal(t, references, tokenParams.References)
+
+	_, err = req.TransferSOLParams(sender)
+	require.ErrorIs(t, err, solanapay.ErrUnexpectedSPLToken)
+
+	req.Amount = ""
+	_, err = req.TransferTokenParams(sender, 6)
+	require.ErrorIs(t, err, solanapay.ErrMissingAmount)
+}
@@ -1,22 +1,24 @@
 package e2e
 
 import (
+	"os"
+
 	"github.com/EntySquare/solana/common"
 
 	_ "github.com/joho/godotenv/autoload" // Load .env file automatically
 )
 
 var (
-	SolanaDevnetRPCNode = env.GetString("SOLANA_RPC_ENDPOINT", "https://api.devnet.solana.com")
+	SolanaDevnetRPCNode = envString("SOLANA_RPC_ENDPOINT", "https://api.devnet.solana.com")
 
 	FeePayerPubkey     = common.PublicKeyFromString("71fjb18P3CCaCNgRrUGbMsVQx2vB9XbdhL1BfmRahEPq")
-	FeePayerPrivateKey = env.MustString("FEE_PAYER_PRIVATE_KEY")
+	FeePayerPrivateKey = envString("FEE_PAYER_PRIVATE_KEY", "")
 
 	Wallet1Pubkey     = common.PublicKeyFromString("FuQhSmAT6kAmmzCMiiYbzFcTQJFuu6raXAdCFibz4YPR")
-	Wallet1PrivateKey = env.MustString("WALLET_1_PRIVATE_KEY")
+	Wallet1PrivateKey = envString("WALLET_1_PRIVATE_KEY", "")
 
 	Wallet2Pubkey     = common.PublicKeyFromString("RjpQLUttBMdoQ4HKMygScEjkd6S69dZZC9T4W3Z3DKD")
-	Wallet2PrivateKey = env.MustString("WALLET_2_PRIVATE_KEY")
+	Wallet2PrivateKey = envString("WALLET_2_PRIVATE_KEY", "")
 
 	TokenMintPubkey         = common.PublicKeyFromString("3GYtjt6Qi93no13nQED5siMMU4fR8zRDPi6V55Vg2mez")
 	AssetMintPubkey         = common.PublicKeyFromString("7HyvGUEjxsGJLFX5foWTCWZVpEtabAF5LV63wP2Ei41d")
@@ -25,8 +27,19 @@ var (
 	EditionMintPubkey4      = common.PublicKeyFromString("FphT9gWeUQr6gYdD3i8PMneXGHDQyHjyedZY75uAa6Gv")
 
 	CollectionPubkey     = common.PublicKeyFromString("5kyJBiH1ybSnhMniwr2CyaL7LitMKaLGA4HpGZtRcD6e")
-	CollectionPrivateKey = env.MustString("COLLECTION_PRIVATE_KEY")
+	CollectionPrivateKey = envString("COLLECTION_PRIVATE_KEY", "")
 
-	ArweaveWalletPath = env.GetString("ARWEAVE_WALLET_PATH", "./wallet.json")
+	ArweaveWalletPath = envString("ARWEAVE_WALLET_PATH", "./wallet.json")
 	ArweaveClientURL  = "https://arweave.net"
 )
+
+// requiredEnv are the environment variables of the private keys signing the e2e transactions.
+var requiredEnv = []string{"FEE_PAYER_PRIVATE_KEY", "WALLET_1_PRIVATE_KEY", "WALLET_2_PRIVATE_KEY", "COLLECTION_PRIVATE_KEY"}
+
+// envString returns the environment variable or the fallback if it is not set.
+func envString(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return fallback
+}
@@ -6,7 +6,6 @@ import (
 	"testing"
 
 	"github.com/EntySquare/solana-go-sdk/program/token"
-	"github.com/EntySquare/solana/client"
 	"github.com/EntySquare/solana/common"
 	"github.com/EntySquare/solana/instructions"
 	"github.com/EntySquare/solana/tests/e2e"
@@ -17,6 +16,8 @@ import (
 )
 
 func TestFungibleToken(t *testing.T) {
+	session := e2e.NewSession(t)
+
 	var (
 		tokenNameInit   = "Test Token Init"
 		tokenSymbolInit = "TSTi"
@@ -24,14 +25,14 @@ func TestFungibleToken(t *testing.T) {
 		tokenSymbol     = "TSTt"
 		metadataUri     = "https://www.arweave.net/QR1PsBgIbiYoKgGff5Jq2U8QavHChRjBki8XRJ-06mI?ext=json"
 		supplyAmount    = 1000000 * types.SPLTokenDefaultMultiplier
-		mint            = common.NewAccount()
+		mint            = session.NewAccount("mint")
 	)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	// Create a new sc
-	sc := client.New(client.SetSolanaEndpoint(e2e.SolanaDevnetRPCNode))
+	sc := session.Client
 
 	t.Run("mint fungible token", func(t *testing.T) {
 		// Mint token
@@ -148,12 +149,14 @@ func TestFungibleToken(t *testing.T) {
 }
 
 func TestFreezeTokenAccount(t *testing.T) {
+	session := e2e.NewSession(t)
+
 	var (
 		tokenName    = "Test Token"
 		tokenSymbol  = "TSTt"
 		metadataUri  = "https://www.arweave.net/QR1PsBgIbiYoKgGff5Jq2U8QavHChRjBki8XRJ-06mI?ext=json"
 		supplyAmount = 1000000 * types.SPLTokenDefaultMultiplier
-		mint         = common.NewAccount()
+		mint         = session.NewAccount("mint")
 	)
 
 	fmt.Println("Mint:", mint.PublicKey.ToBase58())
@@ -162,7 +165,7 @@ func TestFreezeTokenAccount(t *testing.T) {
 	defer cancel()
 
 	// Create a new sc
-	sc := client.New(client.SetSolanaEndpoint(e2e.SolanaDevnetRPCNode))
+	sc := session.Client
 
 	t.Run("mint fungible token", func(t *testing.T) {
 		// Mint token
@@ -472,19 +475,21 @@ func TestFreezeTokenAccount(t *testing.T) {
 }
 
 func TestDisableMintingOfFungibleToken(t *testing.T) {
+	session := e2e.NewSession(t)
+
 	var (
 		tokenName    = "Test Token"
 		tokenSymbol  = "TSTt"
 		metadataUri  = "https://www.arweave.net/QR1PsBgIbiYoKgGff5Jq2U8QavHChRjBki8XRJ-06mI?ext=json"
 		supplyAmount = 1000000 * types.SPLTokenDefaultMultiplier
-		mint         = common.NewAccount()
+		mint         = session.NewAccount("mint")
 	)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	// Create a new sc
-	sc := client.New(client.SetSolanaEndpoint(e2e.SolanaDevnetRPCNode))
+	sc := session.Client
 
 	t.Run("mint fungible token", func(t *testing.T) {
 		// Mint token
@@ -5,8 +5,6 @@ import (
 	"fmt"
 	"testing"
 
-	"github.com/EntySquare/solana/client"
-	"github.com/EntySquare/solana/common"
 	"github.com/EntySquare/solana/instructions"
 	"github.com/EntySquare/solana/tests/e2e"
 	"github.com/EntySquare/solana/token_metadata"
@@ -17,19 +15,21 @@ import (
 )
 
 func TestNFT(t *testing.T) {
+	session := e2e.NewSession(t)
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	// Create a new sc
-	sc := client.New(client.SetSolanaEndpoint(e2e.SolanaDevnetRPCNode))
+	sc := session.Client
 
 	var (
 		tokenName   = "Test NFT"
 		tokenSymbol = "TSTn"
 		metadataUri = "https://www.arweave.net/jQ6ecVJtPZwaC-tsSYftEqaKsC8R3winHH2Z2hLxiBk?ext=json"
-		collection  = common.NewAccount()
-		mint        = common.NewAccount()
-		editionMint = common.NewAccount()
+		collection  = session.NewAccount("collection")
+		mint        = session.NewAccount("mint")
+		editionMint = session.NewAccount("editionMint")
 	)
 
 	// Display account public keys
@@ -311,17 +311,19 @@ func TestNFT(t *testing.T) {
 }
 
 func TestUseNFT_Burn(t *testing.T) {
+	session := e2e.NewSession(t)
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	// Create a new sc
-	sc := client.New(client.SetSolanaEndpoint(e2e.SolanaDevnetRPCNode))
+	sc := session.Client
 
 	var (
 		tokenName   = "Test NFT"
 		tokenSymbol = "TSTn"
 		metadataUri = "https://www.arweave.net/jQ6ecVJtPZwaC-tsSYftEqaKsC8R3winHH2Z2hLxiBk?ext=json"
-		mint        = common.NewAccount()
+		mint        = session.NewAccount("mint")
 	)
 
 	fmt.Println("Mint:", mint.PublicKey.ToBase58())
@@ -448,17 +450,19 @@ func TestUseNFT_Burn(t *testing.T) {
 }
 
 func TestUseNFT_Single(t *testing.T) {
+	session := e2e.NewSession(t)
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	// Create a new sc
-	sc := client.New(client.SetSolanaEndpoint(e2e.SolanaDevnetRPCNode))
+	sc := session.Client
 
 	var (
 		tokenName   = "Test NFT"
 		tokenSymbol = "TSTn"
 		metadataUri = "https://www.arweave.net/jQ6ecVJtPZwaC-tsSYftEqaKsC8R3winHH2Z2hLxiBk?ext=json"
-		mint        = common.NewAccount()
+		mint        = session.NewAccount("mint")
 	)
 
 	fmt.Println("Mint:", mint.PublicKey.ToBase58())
@@ -593,17 +597,19 @@ func TestUseNFT_Single(t *testing.T) {
 }
 
 func TestUseNFT_Multi(t *testing.T) {
+	session := e2e.NewSession(t)
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	// Create a new sc
-	sc := client.New(client.SetSolanaEndpoint(e2e.SolanaDevnetRPCNode))
+	sc := session.Client
 
 	var (
 		tokenName   = "Test NFT"
 		tokenSymbol = "TSTn"
 		metadataUri = "https://www.arweave.net/jQ6ecVJtPZwaC-tsSYftEqaKsC8R3winHH2Z2hLxiBk?ext=json"
-		mint        = common.NewAccount()
+		mint        = session.NewAccount("mint")
 	)
 
 	fmt.Println("Mint:", mint.PublicKey.ToBase58())
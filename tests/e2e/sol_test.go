@@ -5,8 +5,6 @@ import (
 	"fmt"
 	"testing"
 
-	"github.com/EntySquare/solana-go-sdk/types"
-	"github.com/EntySquare/solana/client"
 	"github.com/EntySquare/solana/instructions"
 	"github.com/EntySquare/solana/tests/e2e"
 	"github.com/EntySquare/solana/transaction"
@@ -18,11 +16,13 @@ import (
 func TestRequestAirdrop(t *testing.T) {
 	t.SkipNow() // uncomment to run this test
 
+	session := e2e.NewSession(t)
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	// Create a new client
-	client := client.New(client.SetSolanaEndpoint(e2e.SolanaDevnetRPCNode))
+	client := session.Client
 
 	// Request airdrop
 	airdropSignature, err := client.RequestAirdrop(ctx, e2e.Wallet1Pubkey.ToBase58(), typesx.SOL)
@@ -32,19 +32,16 @@ func TestRequestAirdrop(t *testing.T) {
 }
 
 func TestTransaction(t *testing.T) {
-	senderAccount, err := types.AccountFromBase58(e2e.Wallet1PrivateKey)
-	require.NoError(t, err)
-	require.NotEmpty(t, senderAccount)
+	session := e2e.NewSession(t)
 
-	recipientAccount, err := types.AccountFromBase58(e2e.Wallet2PrivateKey)
-	require.NoError(t, err)
-	require.NotEmpty(t, recipientAccount)
+	sender := e2e.Wallet1Pubkey
+	recipient := e2e.Wallet2Pubkey
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	// Create a new client
-	sc := client.New(client.SetSolanaEndpoint(e2e.SolanaDevnetRPCNode))
+	sc := session.Client
 
 	minAccountRent, err := sc.GetMinimumBalanceForRentExemption(context.Background(), typesx.AccountSize)
 	require.NoError(t, err)
@@ -53,12 +50,12 @@ func TestTransaction(t *testing.T) {
 	amount := minAccountRent + 100
 
 	// Get sender balance
-	startSenderBalance, err := sc.GetSOLBalance(ctx, senderAccount.PublicKey.ToBase58())
+	startSenderBalance, err := sc.GetSOLBalance(ctx, sender.ToBase58())
 	require.NoError(t, err)
 	assert.Greater(t, startSenderBalance, amount+minAccountRent)
 
 	if startSenderBalance < amount+minAccountRent {
-		tx, err := sc.RequestAirdrop(ctx, senderAccount.PublicKey.ToBase58(), typesx.SOL)
+		tx, err := sc.RequestAirdrop(ctx, sender.ToBase58(), typesx.SOL)
 		require.NoError(t, err)
 		require.NotEmpty(t, tx)
 
@@ -68,58 +65,48 @@ func TestTransaction(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, typesx.TransactionStatusSuccess, status)
 
-		startSenderBalance, err = sc.GetSOLBalance(ctx, senderAccount.PublicKey.ToBase58())
+		startSenderBalance, err = sc.GetSOLBalance(ctx, sender.ToBase58())
 		require.NoError(t, err)
 		assert.Greater(t, startSenderBalance, amount+minAccountRent)
 		fmt.Printf("Start sender balance: %d\n", startSenderBalance)
 	}
 
 	// Get recipient balance
-	startRecipientBalance, err := sc.GetSOLBalance(ctx, recipientAccount.PublicKey.ToBase58())
+	startRecipientBalance, err := sc.GetSOLBalance(ctx, recipient.ToBase58())
 	require.NoError(t, err)
 	fmt.Printf("Start recipient balance: %d\n", startRecipientBalance)
 
 	// Create a new transaction
 	txb, err := transaction.NewTransactionBuilder(sc).
-		SetFeePayer(senderAccount.PublicKey).
+		SetFeePayer(sender).
 		AddInstruction(instructions.TransferSOL(instructions.TransferSOLParams{
-			Sender:    senderAccount.PublicKey,
-			Recipient: recipientAccount.PublicKey,
+			Sender:    sender,
+			Recipient: recipient,
 			Amount:    amount,
 		})).
 		AddInstruction(instructions.Memo(
-			fmt.Sprintf("Send %d lamports to %s", amount, recipientAccount.PublicKey.ToBase58()),
-			senderAccount.PublicKey,
+			fmt.Sprintf("Send %d lamports to %s", amount, recipient.ToBase58()),
+			sender,
 		)).
 		Build(ctx)
 	require.NoError(t, err)
 	require.NotEmpty(t, txb)
 
-	// Sign transaction
-	txs, err := sc.SignTransaction(ctx, senderAccount, txb)
-	require.NoError(t, err)
-	require.NotEmpty(t, txs)
-
-	// Send transaction
-	txSignature, err := sc.SendTransaction(ctx, txs)
+	// Sign and send transaction, wait for it to be confirmed
+	txSignature, status, err := e2e.SignAndSendTransaction(ctx, sc, txb, e2e.Wallet1PrivateKey)
 	require.NoError(t, err)
 	require.NotEmpty(t, txSignature)
-	fmt.Printf("Transaction signature: %s\n", txSignature)
-
-	// Wait for transaction to be confirmed
-	fmt.Println("Waiting for transaction to be confirmed...")
-	status, err := sc.WaitForTransactionConfirmed(ctx, txSignature, 0)
-	require.NoError(t, err)
 	require.Equal(t, typesx.TransactionStatusSuccess, status)
+	fmt.Printf("Transaction signature: %s\n", txSignature)
 
 	// Get sender balance
-	senderBalance, err := sc.GetSOLBalance(ctx, senderAccount.PublicKey.ToBase58())
+	senderBalance, err := sc.GetSOLBalance(ctx, sender.ToBase58())
 	require.NoError(t, err)
 	require.Less(t, senderBalance, startSenderBalance)
 	fmt.Printf("Sender balance: %d\n", senderBalance)
 
 	// Get recipient balance
-	recipientBalance, err := sc.GetSOLBalance(ctx, recipientAccount.PublicKey.ToBase58())
+	recipientBalance, err := sc.GetSOLBalance(ctx, recipient.ToBase58())
 	require.NoError(t, err)
 	require.Greater(t, recipientBalance, startRecipientBalance)
 	fmt.Printf("Recipient balance: %d\n", recipientBalance)
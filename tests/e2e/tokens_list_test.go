@@ -5,17 +5,18 @@ import (
 	"fmt"
 	"testing"
 
-	"github.com/EntySquare/solana/client"
 	"github.com/EntySquare/solana/tests/e2e"
 	"github.com/stretchr/testify/require"
 )
 
 func TestGetTokensList(t *testing.T) {
+	session := e2e.NewSession(t)
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	// Create a new sc
-	sc := client.New(client.SetSolanaEndpoint(e2e.SolanaDevnetRPCNode))
+	sc := session.Client
 
 	// Get tokens list
 	tokensList, err := sc.GetFungibleTokensList(ctx, e2e.Wallet1Pubkey.ToBase58())
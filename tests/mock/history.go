@@ -0,0 +1,231 @@
+package mock
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	sdkclient "github.com/EntySquare/solana-go-sdk/client"
+	"github.com/EntySquare/solana-go-sdk/rpc"
+	sdktypes "github.com/EntySquare/solana-go-sdk/types"
+	"github.com/mr-tron/base58"
+)
+
+// RPCTransaction is the confirmed transaction stored by the fake server.
+// The zero values of the meta are filled in by AddTransaction.
+type RPCTransaction struct {
+	Transaction       sdktypes.Transaction
+	Slot              uint64  // default is the current slot
+	BlockTime         int64   // unix time; default is now
+	Err               any     // transaction error, nil if the transaction succeeded
+	Fee               uint64  // default is DefaultLamportsPerSignature per signature
+	PreBalances       []int64 // lamports of the message accounts; default is zeros
+	PostBalances      []int64
+	PreTokenBalances  []rpc.TransactionMetaTokenBalance
+	PostTokenBalances []rpc.TransactionMetaTokenBalance
+	InnerInstructions []sdkclient.InnerInstruction
+	LogMessages       []string
+}
+
+// AddTransaction stores the confirmed transaction as the newest one of the history;
+// getTransaction, getSignaturesForAddress and getSignatureStatuses read it.
+// The transaction without signatures gets a random one.
+// Returns the signature of the transaction.
+func (s *RPCServer) AddTransaction(tx RPCTransaction) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(tx.Transaction.Signatures) == 0 {
+		signature := make([]byte, 64)
+		rand.Read(signature)
+		tx.Transaction.Signatures = []sdktypes.Signature{signature}
+	}
+	if tx.Slot == 0 {
+		tx.Slot = s.slot
+	}
+	if tx.BlockTime == 0 {
+		tx.BlockTime = time.Now().Unix()
+	}
+	if tx.Fee == 0 {
+		tx.Fee = uint64(len(tx.Transaction.Signatures)) * DefaultLamportsPerSignature
+	}
+	if tx.PreBalances == nil {
+		tx.PreBalances = make([]int64, len(tx.Transaction.Message.Accounts))
+	}
+	if tx.PostBalances == nil {
+		tx.PostBalances = make([]int64, len(tx.Transaction.Message.Accounts))
+	}
+
+	signature := base58.Encode(tx.Transaction.Signatures[0])
+	s.history = append(s.history, tx)
+	s.statuses[signature] = SignatureStatus{Slot: tx.Slot, ConfirmationStatus: "finalized", Err: tx.Err}
+
+	return signature
+}
+
+// signatureWithStatus is the getSignaturesForAddress item with the confirmation status.
+type signatureWithStatus struct {
+	rpc.SignatureWithStatus
+	ConfirmationStatus string `json:"confirmationStatus"`
+}
+
+func (s *RPCServer) getTransaction(params []json.RawMessage) (any, error) {
+	var signature string
+	var config struct {
+		Commitment string `json:"commitment"`
+	}
+	if err := decodeParam(params, 0, &signature); err != nil {
+		return nil, err
+	}
+	if len(params) > 1 {
+		if err := decodeParam(params, 1, &config); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, tx := range s.history {
+		if base58.Encode(tx.Transaction.Signatures[0]) != signature {
+			continue
+		}
+		// the transaction is returned once it reached the requested commitment
+		if !commitmentReached(s.statuses[signature].ConfirmationStatus, config.Commitment) {
+			return nil, nil
+		}
+		data, err := tx.Transaction.Serialize()
+		if err != nil {
+			return nil, &RPCError{Code: RPCErrorInvalidParams, Message: "failed to serialize transaction: " + err.Error()}
+		}
+
+		return map[string]any{
+			"slot":        tx.Slot,
+			"blockTime":   tx.BlockTime,
+			"transaction": []string{base64.StdEncoding.EncodeToString(data), "base64"},
+			"meta":        encodeTransactionMeta(tx),
+		}, nil
+	}
+
+	return nil, nil
+}
+
+func (s *RPCServer) getSignaturesForAddress(params []json.RawMessage) (any, error) {
+	var address string
+	var config struct {
+		Limit  int    `json:"limit"`
+		Before string `json:"before"`
+		Until  string `json:"until"`
+	}
+	if err := decodeParam(params, 0, &address); err != nil {
+		return nil, err
+	}
+	if len(params) > 1 {
+		if err := decodeParam(params, 1, &config); err != nil {
+			return nil, err
+		}
+	}
+	if config.Limit <= 0 || config.Limit > 1000 {
+		config.Limit = 1000
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]signatureWithStatus, 0)
+	skip := config.Before != ""
+	for i := len(s.history) - 1; i >= 0 && len(result) < config.Limit; i-- {
+		tx := s.history[i]
+		signature := base58.Encode(tx.Transaction.Signatures[0])
+		if skip {
+			skip = signature != config.Before
+			continue
+		}
+		if signature == config.Until {
+			break
+		}
+		// the processed transactions are not listed
+		status := s.statuses[signature]
+		if !mentions(tx.Transaction.Message, address) || !commitmentReached(status.ConfirmationStatus, "confirmed") {
+			continue
+		}
+
+		blockTime := tx.BlockTime
+		result = append(result, signatureWithStatus{
+			SignatureWithStatus: rpc.SignatureWithStatus{
+				Signature: signature,
+				Slot:      tx.Slot,
+				BlockTime: &blockTime,
+				Err:       tx.Err,
+			},
+			ConfirmationStatus: status.ConfirmationStatus,
+		})
+	}
+
+	return result, nil
+}
+
+// commitmentReached reports whether the confirmation status reached the commitment; empty commitment is finalized.
+func commitmentReached(status, commitment string) bool {
+	levels := map[string]int{"processed": 1, "confirmed": 2, "finalized": 3}
+	if commitment == "" {
+		commitment = "finalized"
+	}
+	return levels[status] >= levels[commitment]
+}
+
+// mentions reports whether the address is one of the message accounts.
+func mentions(message sdktypes.Message, address string) bool {
+	for _, account := range message.Accounts {
+		if account.ToBase58() == address {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeTransactionMeta encodes the meta of the transaction like the RPC node.
+func encodeTransactionMeta(tx RPCTransaction) map[string]any {
+	inner := make([]any, 0, len(tx.InnerInstructions))
+	for _, ix := range tx.InnerInstructions {
+		instructions := make([]any, 0, len(ix.Instructions))
+		for _, ci := range ix.Instructions {
+			accounts := ci.Accounts
+			if accounts == nil {
+				accounts = []int{}
+			}
+			instructions = append(instructions, map[string]any{
+				"programIdIndex": ci.ProgramIDIndex,
+				"accounts":       accounts,
+				"data":           base58.Encode(ci.Data),
+			})
+		}
+		inner = append(inner, map[string]any{"index": ix.Index, "instructions": instructions})
+	}
+
+	preTokenBalances := tx.PreTokenBalances
+	if preTokenBalances == nil {
+		preTokenBalances = []rpc.TransactionMetaTokenBalance{}
+	}
+	postTokenBalances := tx.PostTokenBalances
+	if postTokenBalances == nil {
+		postTokenBalances = []rpc.TransactionMetaTokenBalance{}
+	}
+	logs := tx.LogMessages
+	if logs == nil {
+		logs = []string{}
+	}
+
+	return map[string]any{
+		"err":               tx.Err,
+		"fee":               tx.Fee,
+		"preBalances":       tx.PreBalances,
+		"postBalances":      tx.PostBalances,
+		"preTokenBalances":  preTokenBalances,
+		"postTokenBalances": postTokenBalances,
+		"innerInstructions": inner,
+		"logMessages":       logs,
+		"loadedAddresses":   map[string]any{"writable": []string{}, "readonly": []string{}},
+	}
+}
@@ -0,0 +1,660 @@
+package mock
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+
+	sdkclient "github.com/EntySquare/solana-go-sdk/client"
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/program/token"
+	sdktypes "github.com/EntySquare/solana-go-sdk/types"
+	"github.com/EntySquare/solana/client"
+	commonx "github.com/EntySquare/solana/common"
+	"github.com/EntySquare/solana/instructions"
+	"github.com/EntySquare/solana/token_metadata"
+	"github.com/EntySquare/solana/utils"
+	"github.com/mr-tron/base58"
+)
+
+// Compute units charged by the ledger for every executed instruction.
+const LedgerUnitsPerInstruction = 1000
+
+// Builtin instruction errors of the ledger programs.
+var (
+	ErrInstructionInsufficientFunds = &InstructionError{Name: "InsufficientFunds"}
+	ErrInvalidAccountData           = &InstructionError{Name: "InvalidAccountData"}
+	ErrInvalidInstructionData       = &InstructionError{Name: "InvalidInstructionData"}
+	ErrAccountAlreadyInitialized    = &InstructionError{Name: "AccountAlreadyInitialized"}
+	ErrUninitializedAccount         = &InstructionError{Name: "UninitializedAccount"}
+	ErrMissingRequiredSignature     = &InstructionError{Name: "MissingRequiredSignature"}
+	ErrIncorrectProgramID           = &InstructionError{Name: "IncorrectProgramId"}
+	ErrNotEnoughAccountKeys         = &InstructionError{Name: "NotEnoughAccountKeys"}
+	ErrReadonlyDataModified         = &InstructionError{Name: "ReadonlyDataModified"}
+	ErrExternalAccountDataModified  = &InstructionError{Name: "ExternalAccountDataModified"}
+	ErrExternalAccountLamportSpend  = &InstructionError{Name: "ExternalAccountLamportSpend"}
+)
+
+// Errors of the ledger client methods.
+var (
+	ErrLedgerNotSupported    = errors.New("not supported by the in-memory ledger")
+	ErrLedgerAccountNotFound = errors.New("account not found in the ledger")
+)
+
+// the ledger can be used wherever the instructions need a client
+var _ instructions.Client = (*Ledger)(nil)
+
+type (
+	// Ledger is an in-memory bank which executes transactions without a validator.
+	// It implements the client methods used by the instructions and the transaction builder,
+	// and executes the System, SPL Token and Associated Token Account programs.
+	// Other programs can be added with RegisterProgram.
+	Ledger struct {
+		mu           sync.Mutex
+		accounts     map[common.PublicKey]RPCAccount
+		programs     map[common.PublicKey]Program
+		lookupTables map[common.PublicKey][]common.PublicKey
+		blockhashes  map[string]bool
+		blockhash    string
+		slot         uint64
+		signatures   map[string]bool
+	}
+
+	// Program executes an instruction of the on-chain program in the ledger.
+	// Returns *InstructionError to fail with a builtin or a custom program error.
+	Program func(ix *InstructionContext) error
+
+	// InstructionContext is the instruction being executed with the accounts it can read and modify.
+	InstructionContext struct {
+		ProgramID common.PublicKey
+		Accounts  []sdktypes.AccountMeta // instruction accounts in order; the flags are the ones of the transaction
+		Data      []byte
+
+		state  *ledgerState
+		before map[common.PublicKey]RPCAccount // accounts before the instruction or the last invoke
+	}
+
+	// InstructionError is the error of the failed instruction, e.g. "InvalidAccountData" or a custom program error.
+	InstructionError struct {
+		Name string // builtin error name; "Custom" for the custom program errors
+		Code uint32 // custom program error code
+	}
+
+	// ledgerState is the copy of the touched accounts during the transaction execution.
+	ledgerState struct {
+		ledger   *Ledger
+		accounts map[common.PublicKey]*RPCAccount
+	}
+)
+
+// NewLedger creates a new empty ledger with the builtin programs.
+func NewLedger() *Ledger {
+	l := &Ledger{
+		accounts:     make(map[common.PublicKey]RPCAccount),
+		lookupTables: make(map[common.PublicKey][]common.PublicKey),
+		blockhashes:  make(map[string]bool),
+		signatures:   make(map[string]bool),
+		slot:         DefaultSlot,
+	}
+	l.programs = map[common.PublicKey]Program{
+		common.SystemProgramID:                    systemProgram,
+		common.TokenProgramID:                     tokenProgram,
+		commonx.Token2022ProgramID:                tokenProgram,
+		common.SPLAssociatedTokenAccountProgramID: associatedTokenProgram,
+		common.ComputeBudgetProgramID:             NoopProgram,
+		common.MemoProgramID:                      NoopProgram,
+	}
+	l.blockhash = DefaultBlockhash
+	l.blockhashes[l.blockhash] = true
+
+	return l
+}
+
+// NoopProgram accepts any instruction without changing the accounts.
+// Register it for the programs the ledger can not execute, e.g. the token metadata program.
+func NoopProgram(*InstructionContext) error {
+	return nil
+}
+
+// CustomError returns the custom program error with the given code.
+func CustomError(code uint32) *InstructionError {
+	return &InstructionError{Name: "Custom", Code: code}
+}
+
+// Error returns the error message.
+func (e *InstructionError) Error() string {
+	if e.Name == "Custom" {
+		return fmt.Sprintf("custom program error: 0x%x", e.Code)
+	}
+	return e.Name
+}
+
+// RegisterProgram sets the program executed for the instructions of the program id.
+func (l *Ledger) RegisterProgram(programID common.PublicKey, program Program) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.programs[programID] = program
+}
+
+// SetAccount stores the account.
+func (l *Ledger) SetAccount(address common.PublicKey, account RPCAccount) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.accounts[address] = account
+}
+
+// Account returns the account and true, or false if it does not exist.
+func (l *Ledger) Account(address common.PublicKey) (RPCAccount, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	account, ok := l.accounts[address]
+	return account, ok
+}
+
+// Airdrop adds the lamports to the account, creating a system account if it does not exist.
+func (l *Ledger) Airdrop(address common.PublicKey, lamports uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	account, ok := l.accounts[address]
+	if !ok {
+		account.Owner = common.SystemProgramID
+	}
+	account.Lamports += lamports
+	l.accounts[address] = account
+}
+
+// SetMint stores the SPL token mint account.
+func (l *Ledger) SetMint(address common.PublicKey, mint token.MintAccount) {
+	l.SetAccount(address, RPCAccount{
+		Lamports: RentExemptBalance(token.MintAccountSize),
+		Owner:    common.TokenProgramID,
+		Data:     MintAccountData(mint),
+	})
+}
+
+// SetTokenAccount stores the SPL token account.
+func (l *Ledger) SetTokenAccount(address common.PublicKey, account token.TokenAccount) {
+	l.SetAccount(address, RPCAccount{
+		Lamports: RentExemptBalance(token.TokenAccountSize),
+		Owner:    common.TokenProgramID,
+		Data:     TokenAccountData(account),
+	})
+}
+
+// SetLookupTable stores the addresses of the address lookup table.
+func (l *Ledger) SetLookupTable(address common.PublicKey, addresses []common.PublicKey) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lookupTables[address] = append([]common.PublicKey(nil), addresses...)
+}
+
+// NewBlockhash advances the slot and returns the new latest blockhash.
+// The previous blockhashes stay valid.
+func (l *Ledger) NewBlockhash() string {
+	hash := make([]byte, 32)
+	rand.Read(hash)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.slot++
+	l.blockhash = base58.Encode(hash)
+	l.blockhashes[l.blockhash] = true
+
+	return l.blockhash
+}
+
+// GetSOLBalance returns the lamports of the account, 0 if it does not exist.
+func (l *Ledger) GetSOLBalance(_ context.Context, base58Addr string) (uint64, error) {
+	account, _ := l.Account(common.PublicKeyFromString(base58Addr))
+	return account.Lamports, nil
+}
+
+// GetMintInfo returns the SPL token mint.
+func (l *Ledger) GetMintInfo(_ context.Context, base58MintAddr string) (token.MintAccount, error) {
+	account, ok := l.Account(common.PublicKeyFromString(base58MintAddr))
+	if !ok {
+		return token.MintAccount{}, utils.StackErrors(client.ErrGetMintInfo, ErrLedgerAccountNotFound)
+	}
+	mint, err := token.MintAccountFromData(account.Data)
+	if err != nil {
+		return token.MintAccount{}, utils.StackErrors(client.ErrGetMintInfo, err)
+	}
+
+	return mint, nil
+}
+
+// GetTokenProgramID returns the token program owning the mint or the token account.
+func (l *Ledger) GetTokenProgramID(_ context.Context, base58Addr string) (common.PublicKey, error) {
+	account, ok := l.Account(common.PublicKeyFromString(base58Addr))
+	if !ok {
+		return common.PublicKey{}, utils.StackErrors(client.ErrGetTokenProgram, ErrLedgerAccountNotFound)
+	}
+	if !commonx.IsTokenProgram(account.Owner) {
+		return common.PublicKey{}, utils.StackErrors(client.ErrGetTokenProgram, client.ErrUnknownTokenProgram)
+	}
+
+	return account.Owner, nil
+}
+
+// DefaultDecimals returns the default decimals of the fungible tokens.
+func (l *Ledger) DefaultDecimals() uint8 {
+	return 9
+}
+
+// GetMinimumBalanceForRentExemption returns the rent exempt balance of the account with the given data size.
+func (l *Ledger) GetMinimumBalanceForRentExemption(_ context.Context, size uint64) (uint64, error) {
+	return RentExemptBalance(size), nil
+}
+
+// GetTokenAccountInfo returns the SPL token account.
+func (l *Ledger) GetTokenAccountInfo(_ context.Context, base58AtaAddr string) (token.TokenAccount, error) {
+	account, ok := l.Account(common.PublicKeyFromString(base58AtaAddr))
+	if !ok {
+		return token.TokenAccount{}, utils.StackErrors(client.ErrGetTokenAccount, ErrLedgerAccountNotFound)
+	}
+	tokenAccount, err := deserializeTokenAccount(account)
+	if err != nil {
+		return token.TokenAccount{}, utils.StackErrors(client.ErrGetTokenAccount, err)
+	}
+
+	return tokenAccount, nil
+}
+
+// GetTokenMetadata is not supported by the ledger.
+func (l *Ledger) GetTokenMetadata(context.Context, string) (*token_metadata.Metadata, error) {
+	return nil, utils.StackErrors(client.ErrGetTokenMetadata, ErrLedgerNotSupported)
+}
+
+// GetMasterEditionSupply is not supported by the ledger.
+func (l *Ledger) GetMasterEditionSupply(context.Context, common.PublicKey) (uint64, uint64, error) {
+	return 0, 0, utils.StackErrors(client.ErrGetMasterEditionCurrentSupply, ErrLedgerNotSupported)
+}
+
+// GetEditionInfo is not supported by the ledger.
+func (l *Ledger) GetEditionInfo(context.Context, string) (*token_metadata.Edition, error) {
+	return nil, ErrLedgerNotSupported
+}
+
+// GetSlot returns the current slot.
+func (l *Ledger) GetSlot(context.Context) (uint64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.slot, nil
+}
+
+// GetLookupTableAddresses returns the addresses of the stored lookup table.
+func (l *Ledger) GetLookupTableAddresses(_ context.Context, base58Addr string) ([]common.PublicKey, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	addresses, ok := l.lookupTables[common.PublicKeyFromString(base58Addr)]
+	if !ok {
+		return nil, utils.StackErrors(client.ErrGetLookupTable, client.ErrLookupTableNotFound)
+	}
+	return append([]common.PublicKey(nil), addresses...), nil
+}
+
+// EstimateComputeUnitPrice returns 0, the ledger has no prioritization fees.
+func (l *Ledger) EstimateComputeUnitPrice(context.Context, ...string) (uint64, error) {
+	return 0, nil
+}
+
+// NewTransaction creates the transaction with the latest blockhash of the ledger.
+func (l *Ledger) NewTransaction(_ context.Context, params client.NewTransactionParams) (string, error) {
+	l.mu.Lock()
+	blockhash := l.blockhash
+	l.mu.Unlock()
+
+	tx, err := sdktypes.NewTransaction(sdktypes.NewTransactionParam{
+		Message: sdktypes.NewMessage(sdktypes.NewMessageParam{
+			FeePayer:                   params.FeePayer,
+			RecentBlockhash:            blockhash,
+			Instructions:               params.Instructions,
+			AddressLookupTableAccounts: params.AddressLookupTables,
+		}),
+		Signers: params.Signers,
+	})
+	if err != nil {
+		return "", utils.StackErrors(client.ErrNewTransaction, err)
+	}
+
+	return utils.EncodeTransaction(tx)
+}
+
+// NewDurableTransaction is not supported by the ledger, it has no nonce accounts.
+func (l *Ledger) NewDurableTransaction(context.Context, client.NewDurableTransactionParams) (string, error) {
+	return "", utils.StackErrors(client.ErrNewDurableTransaction, ErrLedgerNotSupported)
+}
+
+// SimulateTransaction executes the transaction without committing the changes.
+// The signatures are verified only if opts.SigVerify is set.
+func (l *Ledger) SimulateTransaction(_ context.Context, txSource string, opts client.SimulateTransactionOptions) (client.SimulateTransactionResult, error) {
+	tx, err := utils.DecodeTransaction(txSource)
+	if err != nil {
+		return client.SimulateTransactionResult{}, utils.StackErrors(client.ErrSimulateTransaction, err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if opts.ReplaceRecentBlockhash {
+		tx.Message.RecentBlockHash = l.blockhash
+	}
+
+	state, logs, txErr := l.execute(tx, opts.SigVerify)
+	result := client.SimulateTransactionResult{
+		Slot:          l.slot,
+		Logs:          logs,
+		UnitsConsumed: uint64(len(logs)/2) * LedgerUnitsPerInstruction,
+		Err:           txErr,
+	}
+	for _, addr := range opts.Accounts {
+		key := common.PublicKeyFromString(addr)
+		diff := client.AccountDiff{Address: addr}
+		if pre, ok := l.accounts[key]; ok {
+			diff.Pre = pre.accountInfo()
+		}
+		if txErr == nil {
+			if post, ok := state.accounts[key]; ok {
+				if post.Lamports > 0 {
+					diff.Post = post.accountInfo()
+				}
+			} else {
+				diff.Post = diff.Pre
+			}
+		}
+		result.Accounts = append(result.Accounts, diff)
+	}
+
+	return result, nil
+}
+
+// SendTransaction verifies and executes the base64 encoded transaction.
+// The transaction is atomic: if it fails, the ledger is not changed and no fee is charged,
+// like a transaction rejected by the preflight check.
+// Returns the transaction signature or *client.TransactionError.
+func (l *Ledger) SendTransaction(_ context.Context, txSource string) (string, error) {
+	tx, err := utils.DecodeTransaction(txSource)
+	if err != nil {
+		return "", utils.StackErrors(client.ErrSendTransaction, client.ErrDeserializeTransaction, err)
+	}
+	if len(tx.Signatures) == 0 {
+		return "", utils.StackErrors(client.ErrSendTransaction, errors.New("transaction is not signed"))
+	}
+	signature := base58.Encode(tx.Signatures[0])
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.signatures[signature] {
+		return "", utils.StackErrors(client.ErrSendTransaction, client.DecodeTransactionError("AlreadyProcessed", tx))
+	}
+
+	state, _, txErr := l.execute(tx, true)
+	if txErr != nil {
+		return "", utils.StackErrors(client.ErrSendTransaction, txErr)
+	}
+
+	for key, account := range state.accounts {
+		if account.Lamports == 0 {
+			delete(l.accounts, key)
+			continue
+		}
+		l.accounts[key] = *account
+	}
+	l.signatures[signature] = true
+
+	return signature, nil
+}
+
+// execute runs the transaction on a copy of the touched accounts; the caller holds the lock.
+// Returns the modified accounts, the program logs and the transaction error.
+func (l *Ledger) execute(tx sdktypes.Transaction, sigVerify bool) (*ledgerState, []string, *client.TransactionError) {
+	msg := tx.Message
+	state := &ledgerState{ledger: l, accounts: make(map[common.PublicKey]*RPCAccount)}
+
+	if !l.blockhashes[msg.RecentBlockHash] {
+		return state, nil, client.DecodeTransactionError("BlockhashNotFound", tx)
+	}
+
+	if sigVerify {
+		data, err := msg.Serialize()
+		if err != nil || len(tx.Signatures) < int(msg.Header.NumRequireSignatures) {
+			return state, nil, client.DecodeTransactionError("SignatureFailure", tx)
+		}
+		for i := 0; i < int(msg.Header.NumRequireSignatures); i++ {
+			if !ed25519.Verify(msg.Accounts[i].Bytes(), data, tx.Signatures[i]) {
+				return state, nil, client.DecodeTransactionError("SignatureFailure", tx)
+			}
+		}
+	}
+
+	keys, err := l.accountKeys(msg)
+	if err != nil {
+		return state, nil, client.DecodeTransactionError("AddressLookupTableNotFound", tx)
+	}
+
+	// the fee is charged before the execution
+	payer := state.account(msg.Accounts[0])
+	fee := uint64(msg.Header.NumRequireSignatures) * DefaultLamportsPerSignature
+	if payer.Lamports == 0 {
+		return state, nil, client.DecodeTransactionError("AccountNotFound", tx)
+	}
+	if payer.Lamports < fee {
+		return state, nil, client.DecodeTransactionError("InsufficientFundsForFee", tx)
+	}
+	payer.Lamports -= fee
+
+	var logs []string
+	for i, compiled := range msg.Instructions {
+		programID := keys[compiled.ProgramIDIndex].PubKey
+		program, ok := l.programs[programID]
+		if !ok {
+			return state, logs, client.DecodeTransactionError("ProgramAccountNotFound", tx)
+		}
+
+		ix := &InstructionContext{ProgramID: programID, Data: compiled.Data, state: state}
+		for _, idx := range compiled.Accounts {
+			ix.Accounts = append(ix.Accounts, keys[idx])
+		}
+
+		logs = append(logs, fmt.Sprintf("Program %s invoke [1]", programID.ToBase58()))
+		if err := ix.run(program); err != nil {
+			logs = append(logs, fmt.Sprintf("Program %s failed: %s", programID.ToBase58(), err))
+			return state, logs, client.DecodeTransactionError(instructionErrorRaw(i, err), tx)
+		}
+		logs = append(logs, fmt.Sprintf("Program %s success", programID.ToBase58()))
+	}
+
+	for i, key := range keys {
+		account, ok := state.accounts[key.PubKey]
+		if !ok || account.Lamports == 0 {
+			continue
+		}
+		if account.Lamports < RentExemptBalance(uint64(len(account.Data))) {
+			return state, logs, client.DecodeTransactionError(map[string]any{
+				"InsufficientFundsForRent": map[string]any{"account_index": float64(i)},
+			}, tx)
+		}
+	}
+
+	return state, logs, nil
+}
+
+// accountKeys returns the account keys of the message, including the ones loaded from the lookup tables,
+// with the signer and the writable flags; the caller holds the lock.
+func (l *Ledger) accountKeys(msg sdktypes.Message) ([]sdktypes.AccountMeta, error) {
+	numSigned := int(msg.Header.NumRequireSignatures)
+	numStatic := len(msg.Accounts)
+
+	keys := make([]sdktypes.AccountMeta, 0, numStatic)
+	for i, key := range msg.Accounts {
+		writable := i < numSigned-int(msg.Header.NumReadonlySignedAccounts) ||
+			i >= numSigned && i < numStatic-int(msg.Header.NumReadonlyUnsignedAccounts)
+		keys = append(keys, sdktypes.AccountMeta{PubKey: key, IsSigner: i < numSigned, IsWritable: writable})
+	}
+
+	var readonly []sdktypes.AccountMeta
+	for _, table := range msg.AddressLookupTables {
+		addresses, ok := l.lookupTables[table.AccountKey]
+		if !ok {
+			return nil, fmt.Errorf("lookup table %s not found", table.AccountKey.ToBase58())
+		}
+		for _, idx := range table.WritableIndexes {
+			if int(idx) >= len(addresses) {
+				return nil, fmt.Errorf("lookup table %s index %d out of range", table.AccountKey.ToBase58(), idx)
+			}
+			keys = append(keys, sdktypes.AccountMeta{PubKey: addresses[idx], IsWritable: true})
+		}
+		for _, idx := range table.ReadonlyIndexes {
+			if int(idx) >= len(addresses) {
+				return nil, fmt.Errorf("lookup table %s index %d out of range", table.AccountKey.ToBase58(), idx)
+			}
+			readonly = append(readonly, sdktypes.AccountMeta{PubKey: addresses[idx]})
+		}
+	}
+
+	return append(keys, readonly...), nil
+}
+
+// instructionErrorRaw returns the instruction error in the RPC format, e.g. {"InstructionError":[0,{"Custom":1}]}.
+func instructionErrorRaw(index int, err error) map[string]any {
+	var ixErr *InstructionError
+	if !errors.As(err, &ixErr) {
+		ixErr = &InstructionError{Name: "ProgramFailedToComplete"}
+	}
+
+	var value any = ixErr.Name
+	if ixErr.Name == "Custom" {
+		value = map[string]any{"Custom": float64(ixErr.Code)}
+	}
+
+	return map[string]any{"InstructionError": []any{float64(index), value}}
+}
+
+// run executes the program and checks that it modified only the writable accounts it may modify.
+func (ix *InstructionContext) run(program Program) error {
+	ix.before = make(map[common.PublicKey]RPCAccount, len(ix.Accounts))
+	for _, meta := range ix.Accounts {
+		ix.before[meta.PubKey] = ix.state.account(meta.PubKey).clone()
+	}
+
+	if err := program(ix); err != nil {
+		return err
+	}
+
+	for _, meta := range ix.Accounts {
+		pre, post := ix.before[meta.PubKey], ix.state.account(meta.PubKey)
+		dataChanged := pre.Owner != post.Owner || !bytes.Equal(pre.Data, post.Data)
+		if (dataChanged || pre.Lamports != post.Lamports) && !meta.IsWritable {
+			return ErrReadonlyDataModified
+		}
+		if dataChanged && pre.Owner != ix.ProgramID {
+			return ErrExternalAccountDataModified
+		}
+		if post.Lamports < pre.Lamports && pre.Owner != ix.ProgramID {
+			return ErrExternalAccountLamportSpend
+		}
+	}
+
+	return nil
+}
+
+// invoke executes the instruction of another program, like a cross-program invocation.
+// The callee gets the privileges of the caller's accounts; the signers sign for the program derived addresses.
+func (ix *InstructionContext) invoke(programID common.PublicKey, accounts []common.PublicKey, data []byte, signers ...common.PublicKey) error {
+	program, ok := ix.state.ledger.programs[programID]
+	if !ok {
+		return ErrIncorrectProgramID
+	}
+
+	metas := make([]sdktypes.AccountMeta, 0, len(accounts))
+	for _, key := range accounts {
+		meta := sdktypes.AccountMeta{PubKey: key}
+		found := false
+		for _, callerMeta := range ix.Accounts {
+			if callerMeta.PubKey == key {
+				meta.IsSigner = meta.IsSigner || callerMeta.IsSigner
+				meta.IsWritable = meta.IsWritable || callerMeta.IsWritable
+				found = true
+			}
+		}
+		if !found {
+			return ErrNotEnoughAccountKeys
+		}
+		for _, signer := range signers {
+			meta.IsSigner = meta.IsSigner || signer == key
+		}
+		metas = append(metas, meta)
+	}
+
+	callee := &InstructionContext{ProgramID: programID, Accounts: metas, Data: data, state: ix.state}
+	if err := callee.run(program); err != nil {
+		return err
+	}
+	for _, meta := range metas {
+		ix.before[meta.PubKey] = ix.state.account(meta.PubKey).clone()
+	}
+
+	return nil
+}
+
+// Account returns the i-th instruction account; changes of the returned account are applied
+// when the transaction succeeds. A missing account is returned empty.
+func (ix *InstructionContext) Account(i int) (*RPCAccount, error) {
+	if i >= len(ix.Accounts) {
+		return nil, ErrNotEnoughAccountKeys
+	}
+	return ix.state.account(ix.Accounts[i].PubKey), nil
+}
+
+// Key returns the public key of the i-th instruction account.
+func (ix *InstructionContext) Key(i int) (common.PublicKey, error) {
+	if i >= len(ix.Accounts) {
+		return common.PublicKey{}, ErrNotEnoughAccountKeys
+	}
+	return ix.Accounts[i].PubKey, nil
+}
+
+// IsSigner returns true if the i-th instruction account signed the transaction.
+func (ix *InstructionContext) IsSigner(i int) bool {
+	return i < len(ix.Accounts) && ix.Accounts[i].IsSigner
+}
+
+// account returns the working copy of the account, copying it from the ledger on the first access.
+func (s *ledgerState) account(key common.PublicKey) *RPCAccount {
+	if account, ok := s.accounts[key]; ok {
+		return account
+	}
+	account, ok := s.ledger.accounts[key]
+	if !ok {
+		// missing accounts belong to the system program
+		account.Owner = common.SystemProgramID
+	}
+	account = account.clone()
+	s.accounts[key] = &account
+	return &account
+}
+
+// clone returns the deep copy of the account.
+func (a RPCAccount) clone() RPCAccount {
+	a.Data = append([]byte(nil), a.Data...)
+	return a
+}
+
+// accountInfo converts the account into the client account info.
+func (a RPCAccount) accountInfo() *sdkclient.AccountInfo {
+	return &sdkclient.AccountInfo{
+		Lamports:   a.Lamports,
+		Owner:      a.Owner,
+		Executable: a.Executable,
+		RentEpoch:  a.RentEpoch,
+		Data:       append([]byte(nil), a.Data...),
+	}
+}
@@ -0,0 +1,679 @@
+package mock
+
+import (
+	"encoding/binary"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/program/system"
+	"github.com/EntySquare/solana-go-sdk/program/token"
+	commonx "github.com/EntySquare/solana/common"
+)
+
+// Custom error codes of the builtin programs, see client.ProgramError.
+const (
+	systemErrAccountAlreadyInUse        = 0
+	systemErrResultWithNegativeLamports = 1
+
+	tokenErrInsufficientFunds      = 1
+	tokenErrInvalidMint            = 2
+	tokenErrMintMismatch           = 3
+	tokenErrOwnerMismatch          = 4
+	tokenErrFixedSupply            = 5
+	tokenErrAlreadyInUse           = 6
+	tokenErrUninitializedState     = 9
+	tokenErrNonNativeHasBalance    = 11
+	tokenErrInvalidInstruction     = 12
+	tokenErrInvalidState           = 13
+	tokenErrOverflow               = 14
+	tokenErrAuthorityNotSupported  = 15
+	tokenErrMintCannotFreeze       = 16
+	tokenErrAccountFrozen          = 17
+	tokenErrMintDecimalsMismatch   = 18
+	associatedTokenErrInvalidOwner = 0
+)
+
+// ErrInvalidSeeds is returned if the associated token account address does not match the derivation.
+var ErrInvalidSeeds = &InstructionError{Name: "InvalidSeeds"}
+
+// systemProgram executes the System program: CreateAccount, Assign and Transfer.
+func systemProgram(ix *InstructionContext) error {
+	if len(ix.Data) < 4 {
+		return ErrInvalidInstructionData
+	}
+	data := ix.Data[4:]
+
+	switch system.Instruction(binary.LittleEndian.Uint32(ix.Data)) {
+	case system.InstructionCreateAccount:
+		if len(data) < 48 {
+			return ErrInvalidInstructionData
+		}
+		lamports := binary.LittleEndian.Uint64(data[0:8])
+		space := binary.LittleEndian.Uint64(data[8:16])
+		owner := common.PublicKeyFromBytes(data[16:48])
+
+		from, to, err := ix.systemAccounts()
+		if err != nil {
+			return err
+		}
+		if !ix.IsSigner(1) {
+			return ErrMissingRequiredSignature
+		}
+		if to.Lamports > 0 || len(to.Data) > 0 || to.Owner != common.SystemProgramID {
+			return CustomError(systemErrAccountAlreadyInUse)
+		}
+		if from.Lamports < lamports {
+			return CustomError(systemErrResultWithNegativeLamports)
+		}
+		from.Lamports -= lamports
+		to.Lamports = lamports
+		to.Data = make([]byte, space)
+		to.Owner = owner
+
+	case system.InstructionAssign:
+		if len(data) < 32 {
+			return ErrInvalidInstructionData
+		}
+		account, err := ix.Account(0)
+		if err != nil {
+			return err
+		}
+		if !ix.IsSigner(0) {
+			return ErrMissingRequiredSignature
+		}
+		if account.Owner != common.SystemProgramID {
+			return ErrIncorrectProgramID
+		}
+		account.Owner = common.PublicKeyFromBytes(data[0:32])
+
+	case system.InstructionTransfer:
+		if len(data) < 8 {
+			return ErrInvalidInstructionData
+		}
+		lamports := binary.LittleEndian.Uint64(data[0:8])
+
+		from, to, err := ix.systemAccounts()
+		if err != nil {
+			return err
+		}
+		if len(from.Data) > 0 {
+			return ErrInvalidAccountData
+		}
+		if from.Lamports < lamports {
+			return CustomError(systemErrResultWithNegativeLamports)
+		}
+		from.Lamports -= lamports
+		to.Lamports += lamports
+
+	default:
+		return ErrInvalidInstructionData
+	}
+
+	return nil
+}
+
+// systemAccounts returns the signed system account to debit and the account to credit.
+func (ix *InstructionContext) systemAccounts() (*RPCAccount, *RPCAccount, error) {
+	from, err := ix.Account(0)
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := ix.Account(1)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ix.IsSigner(0) {
+		return nil, nil, ErrMissingRequiredSignature
+	}
+	if from.Owner != common.SystemProgramID {
+		return nil, nil, ErrIncorrectProgramID
+	}
+
+	return from, to, nil
+}
+
+// tokenProgram executes the SPL Token program instructions for the single signer authorities.
+// It executes the Token-2022 program too, without the extensions.
+func tokenProgram(ix *InstructionContext) error {
+	if len(ix.Data) == 0 {
+		return CustomError(tokenErrInvalidInstruction)
+	}
+	data := ix.Data[1:]
+
+	switch token.Instruction(ix.Data[0]) {
+	case token.InstructionInitializeMint, token.InstructionInitializeMint2:
+		return tokenInitializeMint(ix, data)
+	case token.InstructionInitializeAccount:
+		owner, err := ix.Key(2)
+		if err != nil {
+			return err
+		}
+		return tokenInitializeAccount(ix, owner)
+	case token.InstructionInitializeAccount3:
+		if len(data) < 32 {
+			return CustomError(tokenErrInvalidInstruction)
+		}
+		return tokenInitializeAccount(ix, common.PublicKeyFromBytes(data[0:32]))
+	case token.InstructionTransfer:
+		return tokenTransfer(ix, data, false)
+	case token.InstructionTransferChecked:
+		return tokenTransfer(ix, data, true)
+	case token.InstructionSetAuthority:
+		return tokenSetAuthority(ix, data)
+	case token.InstructionMintTo:
+		return tokenMintTo(ix, data, false)
+	case token.InstructionMintToChecked:
+		return tokenMintTo(ix, data, true)
+	case token.InstructionBurn:
+		return tokenBurn(ix, data, false)
+	case token.InstructionBurnChecked:
+		return tokenBurn(ix, data, true)
+	case token.InstructionCloseAccount:
+		return tokenCloseAccount(ix)
+	case token.InstructionFreezeAccount:
+		return tokenFreeze(ix, true)
+	case token.InstructionThawAccount:
+		return tokenFreeze(ix, false)
+	default:
+		return CustomError(tokenErrInvalidInstruction)
+	}
+}
+
+func tokenInitializeMint(ix *InstructionContext, data []byte) error {
+	if len(data) < 34 {
+		return CustomError(tokenErrInvalidInstruction)
+	}
+	account, err := ix.Account(0)
+	if err != nil {
+		return err
+	}
+	if account.Owner != ix.ProgramID {
+		return ErrIncorrectProgramID
+	}
+	if len(account.Data) != token.MintAccountSize {
+		return ErrInvalidAccountData
+	}
+	if mint, _ := token.MintAccountFromData(account.Data); mint.IsInitialized {
+		return CustomError(tokenErrAlreadyInUse)
+	}
+
+	mintAuthority := common.PublicKeyFromBytes(data[1:33])
+	mint := token.MintAccount{MintAuthority: &mintAuthority, Decimals: data[0], IsInitialized: true}
+	if data[33] == 1 {
+		if len(data) < 66 {
+			return CustomError(tokenErrInvalidInstruction)
+		}
+		freezeAuthority := common.PublicKeyFromBytes(data[34:66])
+		mint.FreezeAuthority = &freezeAuthority
+	}
+	account.Data = MintAccountData(mint)
+
+	return nil
+}
+
+func tokenInitializeAccount(ix *InstructionContext, owner common.PublicKey) error {
+	account, err := ix.Account(0)
+	if err != nil {
+		return err
+	}
+	mintKey, err := ix.Key(1)
+	if err != nil {
+		return err
+	}
+	if account.Owner != ix.ProgramID {
+		return ErrIncorrectProgramID
+	}
+	if len(account.Data) != token.TokenAccountSize {
+		return ErrInvalidAccountData
+	}
+	if existing, _ := token.TokenAccountFromData(account.Data); existing.State != token.TokenAccountStateUninitialized {
+		return CustomError(tokenErrAlreadyInUse)
+	}
+	if _, err := ix.mint(1); err != nil {
+		return CustomError(tokenErrInvalidMint)
+	}
+
+	account.Data = TokenAccountData(token.TokenAccount{
+		Mint:  mintKey,
+		Owner: owner,
+		State: token.TokenAccountStateInitialized,
+	})
+
+	return nil
+}
+
+func tokenTransfer(ix *InstructionContext, data []byte, checked bool) error {
+	amount, decimals, err := tokenAmountData(data, checked)
+	if err != nil {
+		return err
+	}
+
+	// Transfer: source, destination, authority; TransferChecked: source, mint, destination, authority
+	destIdx, authIdx := 1, 2
+	if checked {
+		destIdx, authIdx = 2, 3
+	}
+
+	source, err := ix.tokenAccount(0)
+	if err != nil {
+		return err
+	}
+	dest, err := ix.tokenAccount(destIdx)
+	if err != nil {
+		return err
+	}
+	if source.Mint != dest.Mint {
+		return CustomError(tokenErrMintMismatch)
+	}
+	if checked {
+		if err := ix.checkMintDecimals(1, source.Mint, decimals); err != nil {
+			return err
+		}
+	}
+	if source.State == token.TokenAccountFrozen || dest.State == token.TokenAccountFrozen {
+		return CustomError(tokenErrAccountFrozen)
+	}
+	if source.Amount < amount {
+		return CustomError(tokenErrInsufficientFunds)
+	}
+	if err := ix.checkOwnerOrDelegate(authIdx, &source, amount); err != nil {
+		return err
+	}
+
+	sourceKey, _ := ix.Key(0)
+	destKey, _ := ix.Key(destIdx)
+	source.Amount -= amount
+	if sourceKey == destKey {
+		dest = source
+	}
+	if dest.Amount+amount < dest.Amount {
+		return CustomError(tokenErrOverflow)
+	}
+	dest.Amount += amount
+
+	ix.setTokenAccount(0, source)
+	ix.setTokenAccount(destIdx, dest)
+
+	return nil
+}
+
+func tokenSetAuthority(ix *InstructionContext, data []byte) error {
+	if len(data) < 2 {
+		return CustomError(tokenErrInvalidInstruction)
+	}
+	var newAuthority *common.PublicKey
+	if data[1] == 1 {
+		if len(data) < 34 {
+			return CustomError(tokenErrInvalidInstruction)
+		}
+		key := common.PublicKeyFromBytes(data[2:34])
+		newAuthority = &key
+	}
+
+	account, err := ix.Account(0)
+	if err != nil {
+		return err
+	}
+
+	switch token.AuthorityType(data[0]) {
+	case token.AuthorityTypeMintTokens, token.AuthorityTypeFreezeAccount:
+		mint, err := ix.mint(0)
+		if err != nil {
+			return err
+		}
+		current := &mint.MintAuthority
+		if token.AuthorityType(data[0]) == token.AuthorityTypeFreezeAccount {
+			current = &mint.FreezeAuthority
+		}
+		if *current == nil {
+			if token.AuthorityType(data[0]) == token.AuthorityTypeMintTokens {
+				return CustomError(tokenErrFixedSupply)
+			}
+			return CustomError(tokenErrMintCannotFreeze)
+		}
+		if err := ix.checkAuthority(1, **current); err != nil {
+			return err
+		}
+		*current = newAuthority
+		account.Data = MintAccountData(mint)
+
+	case token.AuthorityTypeAccountOwner:
+		tokenAccount, err := ix.tokenAccount(0)
+		if err != nil {
+			return err
+		}
+		if newAuthority == nil {
+			return CustomError(tokenErrInvalidInstruction)
+		}
+		if err := ix.checkAuthority(1, tokenAccount.Owner); err != nil {
+			return err
+		}
+		tokenAccount.Owner = *newAuthority
+		tokenAccount.Delegate = nil
+		tokenAccount.DelegatedAmount = 0
+		ix.setTokenAccount(0, tokenAccount)
+
+	default:
+		return CustomError(tokenErrAuthorityNotSupported)
+	}
+
+	return nil
+}
+
+func tokenMintTo(ix *InstructionContext, data []byte, checked bool) error {
+	amount, decimals, err := tokenAmountData(data, checked)
+	if err != nil {
+		return err
+	}
+
+	mint, err := ix.mint(0)
+	if err != nil {
+		return err
+	}
+	mintKey, _ := ix.Key(0)
+	dest, err := ix.tokenAccount(1)
+	if err != nil {
+		return err
+	}
+	if dest.Mint != mintKey {
+		return CustomError(tokenErrMintMismatch)
+	}
+	if dest.State == token.TokenAccountFrozen {
+		return CustomError(tokenErrAccountFrozen)
+	}
+	if checked && decimals != mint.Decimals {
+		return CustomError(tokenErrMintDecimalsMismatch)
+	}
+	if mint.MintAuthority == nil {
+		return CustomError(tokenErrFixedSupply)
+	}
+	if err := ix.checkAuthority(2, *mint.MintAuthority); err != nil {
+		return err
+	}
+	if mint.Supply+amount < mint.Supply {
+		return CustomError(tokenErrOverflow)
+	}
+
+	mint.Supply += amount
+	dest.Amount += amount
+	ix.setMint(0, mint)
+	ix.setTokenAccount(1, dest)
+
+	return nil
+}
+
+func tokenBurn(ix *InstructionContext, data []byte, checked bool) error {
+	amount, decimals, err := tokenAmountData(data, checked)
+	if err != nil {
+		return err
+	}
+
+	account, err := ix.tokenAccount(0)
+	if err != nil {
+		return err
+	}
+	mint, err := ix.mint(1)
+	if err != nil {
+		return err
+	}
+	mintKey, _ := ix.Key(1)
+	if account.Mint != mintKey {
+		return CustomError(tokenErrMintMismatch)
+	}
+	if checked && decimals != mint.Decimals {
+		return CustomError(tokenErrMintDecimalsMismatch)
+	}
+	if account.State == token.TokenAccountFrozen {
+		return CustomError(tokenErrAccountFrozen)
+	}
+	if account.Amount < amount {
+		return CustomError(tokenErrInsufficientFunds)
+	}
+	if err := ix.checkOwnerOrDelegate(2, &account, amount); err != nil {
+		return err
+	}
+
+	account.Amount -= amount
+	mint.Supply -= amount
+	ix.setTokenAccount(0, account)
+	ix.setMint(1, mint)
+
+	return nil
+}
+
+func tokenCloseAccount(ix *InstructionContext) error {
+	tokenAccount, err := ix.tokenAccount(0)
+	if err != nil {
+		return err
+	}
+	account, _ := ix.Account(0)
+	dest, err := ix.Account(1)
+	if err != nil {
+		return err
+	}
+	if tokenAccount.Amount > 0 {
+		return CustomError(tokenErrNonNativeHasBalance)
+	}
+	closeAuthority := tokenAccount.Owner
+	if tokenAccount.CloseAuthority != nil {
+		closeAuthority = *tokenAccount.CloseAuthority
+	}
+	if err := ix.checkAuthority(2, closeAuthority); err != nil {
+		return err
+	}
+
+	dest.Lamports += account.Lamports
+	account.Lamports = 0
+	account.Data = nil
+	account.Owner = common.SystemProgramID
+
+	return nil
+}
+
+func tokenFreeze(ix *InstructionContext, freeze bool) error {
+	tokenAccount, err := ix.tokenAccount(0)
+	if err != nil {
+		return err
+	}
+	mint, err := ix.mint(1)
+	if err != nil {
+		return err
+	}
+	mintKey, _ := ix.Key(1)
+	if tokenAccount.Mint != mintKey {
+		return CustomError(tokenErrMintMismatch)
+	}
+	if freeze == (tokenAccount.State == token.TokenAccountFrozen) {
+		return CustomError(tokenErrInvalidState)
+	}
+	if mint.FreezeAuthority == nil {
+		return CustomError(tokenErrMintCannotFreeze)
+	}
+	if err := ix.checkAuthority(2, *mint.FreezeAuthority); err != nil {
+		return err
+	}
+
+	tokenAccount.State = token.TokenAccountStateInitialized
+	if freeze {
+		tokenAccount.State = token.TokenAccountFrozen
+	}
+	ix.setTokenAccount(0, tokenAccount)
+
+	return nil
+}
+
+// tokenAmountData decodes the amount and, for the checked instructions, the decimals.
+func tokenAmountData(data []byte, checked bool) (uint64, uint8, error) {
+	size := 8
+	if checked {
+		size = 9
+	}
+	if len(data) < size {
+		return 0, 0, CustomError(tokenErrInvalidInstruction)
+	}
+
+	var decimals uint8
+	if checked {
+		decimals = data[8]
+	}
+
+	return binary.LittleEndian.Uint64(data[0:8]), decimals, nil
+}
+
+// mint returns the initialized mint of the i-th account.
+func (ix *InstructionContext) mint(i int) (token.MintAccount, error) {
+	account, err := ix.Account(i)
+	if err != nil {
+		return token.MintAccount{}, err
+	}
+	if account.Owner != ix.ProgramID {
+		return token.MintAccount{}, ErrIncorrectProgramID
+	}
+	mint, err := token.MintAccountFromData(account.Data)
+	if err != nil {
+		return token.MintAccount{}, ErrInvalidAccountData
+	}
+	if !mint.IsInitialized {
+		return token.MintAccount{}, CustomError(tokenErrUninitializedState)
+	}
+
+	return mint, nil
+}
+
+// tokenAccount returns the initialized token account of the i-th account.
+func (ix *InstructionContext) tokenAccount(i int) (token.TokenAccount, error) {
+	account, err := ix.Account(i)
+	if err != nil {
+		return token.TokenAccount{}, err
+	}
+	if account.Owner != ix.ProgramID {
+		return token.TokenAccount{}, ErrIncorrectProgramID
+	}
+	tokenAccount, err := token.TokenAccountFromData(account.Data)
+	if err != nil {
+		return token.TokenAccount{}, ErrInvalidAccountData
+	}
+	if tokenAccount.State == token.TokenAccountStateUninitialized {
+		return token.TokenAccount{}, CustomError(tokenErrUninitializedState)
+	}
+
+	return tokenAccount, nil
+}
+
+// setMint stores the mint into the i-th account.
+func (ix *InstructionContext) setMint(i int, mint token.MintAccount) {
+	account, _ := ix.Account(i)
+	account.Data = MintAccountData(mint)
+}
+
+// setTokenAccount stores the token account into the i-th account.
+func (ix *InstructionContext) setTokenAccount(i int, tokenAccount token.TokenAccount) {
+	account, _ := ix.Account(i)
+	account.Data = TokenAccountData(tokenAccount)
+}
+
+// checkMintDecimals checks the mint of the i-th account and its decimals.
+func (ix *InstructionContext) checkMintDecimals(i int, mintKey common.PublicKey, decimals uint8) error {
+	mint, err := ix.mint(i)
+	if err != nil {
+		return err
+	}
+	if key, _ := ix.Key(i); key != mintKey {
+		return CustomError(tokenErrMintMismatch)
+	}
+	if mint.Decimals != decimals {
+		return CustomError(tokenErrMintDecimalsMismatch)
+	}
+
+	return nil
+}
+
+// checkAuthority checks that the i-th account is the expected authority and it signed the transaction.
+func (ix *InstructionContext) checkAuthority(i int, authority common.PublicKey) error {
+	key, err := ix.Key(i)
+	if err != nil {
+		return err
+	}
+	if key != authority {
+		return CustomError(tokenErrOwnerMismatch)
+	}
+	if !ix.IsSigner(i) {
+		return ErrMissingRequiredSignature
+	}
+
+	return nil
+}
+
+// checkOwnerOrDelegate checks the authority of the token account and spends the delegated amount
+// if the authority is the delegate.
+func (ix *InstructionContext) checkOwnerOrDelegate(i int, account *token.TokenAccount, amount uint64) error {
+	key, err := ix.Key(i)
+	if err != nil {
+		return err
+	}
+	if account.Delegate == nil || *account.Delegate != key || key == account.Owner {
+		return ix.checkAuthority(i, account.Owner)
+	}
+
+	if !ix.IsSigner(i) {
+		return ErrMissingRequiredSignature
+	}
+	if account.DelegatedAmount < amount {
+		return CustomError(tokenErrInsufficientFunds)
+	}
+	account.DelegatedAmount -= amount
+	if account.DelegatedAmount == 0 {
+		account.Delegate = nil
+	}
+
+	return nil
+}
+
+// associatedTokenProgram executes the Associated Token Account program: Create and CreateIdempotent.
+// Accounts: funder, associated token account, owner, mint, system program, token program.
+func associatedTokenProgram(ix *InstructionContext) error {
+	idempotent := len(ix.Data) > 0 && ix.Data[0] == 1
+	if len(ix.Data) > 0 && ix.Data[0] > 1 {
+		return ErrInvalidInstructionData
+	}
+	if len(ix.Accounts) < 6 {
+		return ErrNotEnoughAccountKeys
+	}
+
+	funder, _ := ix.Key(0)
+	ata, _ := ix.Key(1)
+	owner, _ := ix.Key(2)
+	mint, _ := ix.Key(3)
+	tokenProgramID, _ := ix.Key(5)
+	if !commonx.IsTokenProgram(tokenProgramID) {
+		return ErrIncorrectProgramID
+	}
+
+	expected, err := commonx.DeriveTokenAccountPubkeyWithProgram(owner, mint, tokenProgramID)
+	if err != nil || expected != ata {
+		return ErrInvalidSeeds
+	}
+
+	account, _ := ix.Account(1)
+	if account.Owner == tokenProgramID && len(account.Data) > 0 {
+		existing, err := token.TokenAccountFromData(account.Data)
+		if idempotent && err == nil && existing.State != token.TokenAccountStateUninitialized {
+			if existing.Owner != owner {
+				return CustomError(associatedTokenErrInvalidOwner)
+			}
+			return nil
+		}
+		return CustomError(systemErrAccountAlreadyInUse)
+	}
+
+	createData := make([]byte, 52)
+	binary.LittleEndian.PutUint32(createData[0:4], uint32(system.InstructionCreateAccount))
+	binary.LittleEndian.PutUint64(createData[4:12], RentExemptBalance(token.TokenAccountSize))
+	binary.LittleEndian.PutUint64(createData[12:20], token.TokenAccountSize)
+	copy(createData[20:52], tokenProgramID.Bytes())
+	if err := ix.invoke(common.SystemProgramID, []common.PublicKey{funder, ata}, createData, ata); err != nil {
+		return err
+	}
+
+	initData := append([]byte{byte(token.InstructionInitializeAccount3)}, owner.Bytes()...)
+	return ix.invoke(tokenProgramID, []common.PublicKey{ata, mint}, initData)
+}
@@ -0,0 +1,333 @@
+package mock_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/types"
+	"github.com/EntySquare/solana/client"
+	commonx "github.com/EntySquare/solana/common"
+	"github.com/EntySquare/solana/instructions"
+	"github.com/EntySquare/solana/tests/mock"
+	"github.com/EntySquare/solana/transaction"
+	typesx "github.com/EntySquare/solana/types"
+	"github.com/stretchr/testify/require"
+)
+
+func sendWithLedger(t *testing.T, ledger *mock.Ledger, feePayer types.Account, ix instructions.InstructionFunc, signers ...types.Account) error {
+	builder := transaction.NewTransactionBuilder(ledger).
+		SetFeePayer(feePayer.PublicKey).
+		AddSigner(feePayer).
+		AddInstruction(ix)
+	for _, signer := range signers {
+		builder.AddSigner(signer)
+	}
+
+	txSource, err := builder.Build(context.Background())
+	require.NoError(t, err)
+
+	_, err = ledger.SendTransaction(context.Background(), txSource)
+	return err
+}
+
+func tokenBalance(t *testing.T, ledger *mock.Ledger, owner, mint common.PublicKey) uint64 {
+	ata, _, err := common.FindAssociatedTokenAddress(owner, mint)
+	require.NoError(t, err)
+
+	account, err := ledger.GetTokenAccountInfo(context.Background(), ata.ToBase58())
+	require.NoError(t, err)
+	return account.Amount
+}
+
+func TestLedger_FungibleLifecycle(t *testing.T) {
+	ctx := context.Background()
+	ledger := mock.NewLedger()
+	// the ledger does not execute the token metadata program
+	ledger.RegisterProgram(common.MetaplexTokenMetaProgramID, mock.NoopProgram)
+
+	issuer := types.NewAccount()
+	recipient := types.NewAccount()
+	mint := types.NewAccount()
+	ledger.Airdrop(issuer.PublicKey, 10*typesx.SOL)
+	ledger.Airdrop(recipient.PublicKey, typesx.SOL)
+
+	err := sendWithLedger(t, ledger, issuer, instructions.MintFungible(instructions.MintFungibleParam{
+		Mint:         mint.PublicKey,
+		MintTo:       issuer.PublicKey,
+		Decimals:     6,
+		SupplyAmount: 1_000_000,
+		TokenName:    "Test Token",
+		TokenSymbol:  "TEST",
+	}), mint)
+	require.NoError(t, err)
+	require.EqualValues(t, 1_000_000, tokenBalance(t, ledger, issuer.PublicKey, mint.PublicKey))
+
+	err = sendWithLedger(t, ledger, issuer, instructions.CreateAssociatedTokenAccountIfNotExists(instructions.CreateAssociatedTokenAccountParam{
+		Funder: issuer.PublicKey,
+		Owner:  recipient.PublicKey,
+		Mint:   mint.PublicKey,
+	}))
+	require.NoError(t, err)
+
+	err = sendWithLedger(t, ledger, issuer, instructions.TransferToken(instructions.TransferTokenParam{
+		Sender:    issuer.PublicKey,
+		Recipient: recipient.PublicKey,
+		Mint:      mint.PublicKey,
+		Amount:    400_000,
+	}))
+	require.NoError(t, err)
+	require.EqualValues(t, 600_000, tokenBalance(t, ledger, issuer.PublicKey, mint.PublicKey))
+	require.EqualValues(t, 400_000, tokenBalance(t, ledger, recipient.PublicKey, mint.PublicKey))
+
+	err = sendWithLedger(t, ledger, recipient, instructions.BurnToken(instructions.BurnTokenParams{
+		Mint:              mint.PublicKey,
+		TokenAccountOwner: recipient.PublicKey,
+		Amount:            400_000,
+	}))
+	require.NoError(t, err)
+
+	mintInfo, err := ledger.GetMintInfo(ctx, mint.PublicKey.ToBase58())
+	require.NoError(t, err)
+	require.EqualValues(t, 600_000, mintInfo.Supply)
+
+	balanceBefore, err := ledger.GetSOLBalance(ctx, recipient.PublicKey.ToBase58())
+	require.NoError(t, err)
+
+	err = sendWithLedger(t, ledger, recipient, instructions.CloseTokenAccount(instructions.CloseTokenAccountParams{
+		Owner: recipient.PublicKey,
+		Mint:  &mint.PublicKey,
+	}))
+	require.NoError(t, err)
+
+	recipientAta, _, err := common.FindAssociatedTokenAddress(recipient.PublicKey, mint.PublicKey)
+	require.NoError(t, err)
+	_, ok := ledger.Account(recipientAta)
+	require.False(t, ok)
+
+	// the rent of the closed account is refunded to the owner
+	balanceAfter, err := ledger.GetSOLBalance(ctx, recipient.PublicKey.ToBase58())
+	require.NoError(t, err)
+	require.Equal(t, balanceBefore+mock.RentExemptBalance(165)-mock.DefaultLamportsPerSignature, balanceAfter)
+}
+
+func TestLedger_Token2022Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	ledger := mock.NewLedger()
+	ledger.RegisterProgram(common.MetaplexTokenMetaProgramID, mock.NoopProgram)
+
+	issuer := types.NewAccount()
+	recipient := types.NewAccount()
+	mint := types.NewAccount()
+	ledger.Airdrop(issuer.PublicKey, 10*typesx.SOL)
+	ledger.Airdrop(recipient.PublicKey, typesx.SOL)
+
+	balanceOf := func(owner common.PublicKey) uint64 {
+		ata, err := commonx.DeriveTokenAccountPubkeyWithProgram(owner, mint.PublicKey, commonx.Token2022ProgramID)
+		require.NoError(t, err)
+		account, err := ledger.GetTokenAccountInfo(ctx, ata.ToBase58())
+		require.NoError(t, err)
+		return account.Amount
+	}
+
+	err := sendWithLedger(t, ledger, issuer, instructions.MintFungible(instructions.MintFungibleParam{
+		Mint:         mint.PublicKey,
+		MintTo:       issuer.PublicKey,
+		Decimals:     6,
+		SupplyAmount: 1_000_000,
+		TokenName:    "Test Token",
+		TokenSymbol:  "TEST",
+		TokenProgram: &commonx.Token2022ProgramID,
+	}), mint)
+	require.NoError(t, err)
+	mintAccount, ok := ledger.Account(mint.PublicKey)
+	require.True(t, ok)
+	require.Equal(t, commonx.Token2022ProgramID, mintAccount.Owner)
+	require.EqualValues(t, 1_000_000, balanceOf(issuer.PublicKey))
+
+	// the token program is detected by the mint
+	err = sendWithLedger(t, ledger, issuer, instructions.CreateAssociatedTokenAccountIfNotExists(instructions.CreateAssociatedTokenAccountParam{
+		Funder: issuer.PublicKey,
+		Owner:  recipient.PublicKey,
+		Mint:   mint.PublicKey,
+	}))
+	require.NoError(t, err)
+
+	err = sendWithLedger(t, ledger, issuer, instructions.TransferToken(instructions.TransferTokenParam{
+		Sender:    issuer.PublicKey,
+		Recipient: recipient.PublicKey,
+		Mint:      mint.PublicKey,
+		Amount:    400_000,
+	}))
+	require.NoError(t, err)
+	require.EqualValues(t, 600_000, balanceOf(issuer.PublicKey))
+	require.EqualValues(t, 400_000, balanceOf(recipient.PublicKey))
+
+	err = sendWithLedger(t, ledger, issuer, instructions.FreezeTokenAccount(instructions.FreezeTokenAccountParams{
+		FreezeAuth:        issuer.PublicKey,
+		Mint:              mint.PublicKey,
+		TokenAccountOwner: &recipient.PublicKey,
+	}))
+	require.NoError(t, err)
+	err = sendWithLedger(t, ledger, recipient, instructions.BurnToken(instructions.BurnTokenParams{
+		Mint:              mint.PublicKey,
+		TokenAccountOwner: recipient.PublicKey,
+		Amount:            400_000,
+	}))
+	require.ErrorIs(t, err, client.ErrTokenAccountFrozen)
+	err = sendWithLedger(t, ledger, issuer, instructions.UnfreezeTokenAccount(instructions.UnfreezeTokenAccountParams{
+		FreezeAuth:        issuer.PublicKey,
+		Mint:              mint.PublicKey,
+		TokenAccountOwner: &recipient.PublicKey,
+	}))
+	require.NoError(t, err)
+
+	err = sendWithLedger(t, ledger, recipient, instructions.BurnToken(instructions.BurnTokenParams{
+		Mint:              mint.PublicKey,
+		TokenAccountOwner: recipient.PublicKey,
+		Amount:            400_000,
+	}))
+	require.NoError(t, err)
+
+	err = sendWithLedger(t, ledger, issuer, instructions.MintExistedFungible(instructions.MintExistedFungibleParam{
+		Mint:         mint.PublicKey,
+		MintTo:       issuer.PublicKey,
+		SupplyAmount: 50_000,
+	}))
+	require.NoError(t, err)
+	err = sendWithLedger(t, ledger, issuer, instructions.DisableFungibleTokenMinting(instructions.DisableFungibleTokenMintingParam{
+		Mint:     mint.PublicKey,
+		MintAuth: issuer.PublicKey,
+	}))
+	require.NoError(t, err)
+
+	mintInfo, err := ledger.GetMintInfo(ctx, mint.PublicKey.ToBase58())
+	require.NoError(t, err)
+	require.EqualValues(t, 650_000, mintInfo.Supply)
+	require.Nil(t, mintInfo.MintAuthority)
+
+	err = sendWithLedger(t, ledger, recipient, instructions.CloseTokenAccount(instructions.CloseTokenAccountParams{
+		Owner: recipient.PublicKey,
+		Mint:  &mint.PublicKey,
+	}))
+	require.NoError(t, err)
+	recipientAta, err := commonx.DeriveTokenAccountPubkeyWithProgram(recipient.PublicKey, mint.PublicKey, commonx.Token2022ProgramID)
+	require.NoError(t, err)
+	_, ok = ledger.Account(recipientAta)
+	require.False(t, ok)
+
+	// the explicit token program must own the mint
+	err = sendWithLedger(t, ledger, issuer, instructions.TransferToken(instructions.TransferTokenParam{
+		Sender:       issuer.PublicKey,
+		Recipient:    issuer.PublicKey,
+		Mint:         mint.PublicKey,
+		Amount:       1,
+		TokenProgram: &common.TokenProgramID,
+	}))
+	require.ErrorIs(t, err, client.ErrIncorrectProgramID)
+}
+
+func TestLedger_Errors(t *testing.T) {
+	ledger := mock.NewLedger()
+	ledger.RegisterProgram(common.MetaplexTokenMetaProgramID, mock.NoopProgram)
+
+	issuer := types.NewAccount()
+	recipient := types.NewAccount()
+	mint := types.NewAccount()
+	ledger.Airdrop(issuer.PublicKey, 10*typesx.SOL)
+
+	err := sendWithLedger(t, ledger, issuer, instructions.MintFungible(instructions.MintFungibleParam{
+		Mint:          mint.PublicKey,
+		MintTo:        issuer.PublicKey,
+		Decimals:      6,
+		SupplyAmount:  100,
+		IsFixedSupply: true,
+		TokenName:     "Test Token",
+		TokenSymbol:   "TEST",
+	}), mint)
+	require.NoError(t, err)
+
+	balance, err := ledger.GetSOLBalance(context.Background(), issuer.PublicKey.ToBase58())
+	require.NoError(t, err)
+
+	t.Run("insufficient token funds", func(t *testing.T) {
+		err := sendWithLedger(t, ledger, issuer, instructions.TransferToken(instructions.TransferTokenParam{
+			Sender:    issuer.PublicKey,
+			Recipient: issuer.PublicKey,
+			Mint:      mint.PublicKey,
+			Amount:    101,
+		}))
+		require.ErrorIs(t, err, client.ErrTokenInsufficientFunds)
+		require.ErrorIs(t, err, client.ErrInstructionFailed)
+	})
+
+	t.Run("fixed supply", func(t *testing.T) {
+		err := sendWithLedger(t, ledger, issuer, instructions.MintExistedFungible(instructions.MintExistedFungibleParam{
+			Mint:         mint.PublicKey,
+			MintTo:       issuer.PublicKey,
+			SupplyAmount: 1,
+		}))
+		require.ErrorIs(t, err, client.ErrTokenFixedSupply)
+	})
+
+	t.Run("missing signature", func(t *testing.T) {
+		txSource, err := transaction.NewTransactionBuilder(ledger).
+			SetFeePayer(issuer.PublicKey).
+			AddInstruction(instructions.TransferSOL(instructions.TransferSOLParams{
+				Sender:    issuer.PublicKey,
+				Recipient: recipient.PublicKey,
+				Amount:    typesx.SOL,
+			})).
+			Build(context.Background())
+		require.NoError(t, err)
+
+		_, err = ledger.SendTransaction(context.Background(), txSource)
+		require.ErrorIs(t, err, client.ErrSignatureFailure)
+	})
+
+	t.Run("new account below rent", func(t *testing.T) {
+		err := sendWithLedger(t, ledger, issuer, instructions.TransferSOL(instructions.TransferSOLParams{
+			Sender:    issuer.PublicKey,
+			Recipient: recipient.PublicKey,
+			Amount:    1000,
+		}))
+		require.ErrorIs(t, err, client.ErrInsufficientFundsForRent)
+	})
+
+	// the failed transactions do not change the ledger
+	after, err := ledger.GetSOLBalance(context.Background(), issuer.PublicKey.ToBase58())
+	require.NoError(t, err)
+	require.Equal(t, balance, after)
+	require.EqualValues(t, 100, tokenBalance(t, ledger, issuer.PublicKey, mint.PublicKey))
+}
+
+func TestLedger_Simulate(t *testing.T) {
+	ledger := mock.NewLedger()
+	sender := types.NewAccount()
+	recipient := types.NewAccount()
+	ledger.Airdrop(sender.PublicKey, typesx.SOL)
+
+	txSource, err := transaction.NewTransactionBuilder(ledger).
+		SetFeePayer(sender.PublicKey).
+		AddSigner(sender).
+		AddInstruction(instructions.TransferSOL(instructions.TransferSOLParams{
+			Sender:    sender.PublicKey,
+			Recipient: recipient.PublicKey,
+			Amount:    typesx.SOL / 2,
+		})).
+		SetAutoComputeUnitLimit(10).
+		Build(context.Background())
+	require.NoError(t, err)
+
+	result, err := ledger.SimulateTransaction(context.Background(), txSource, client.SimulateTransactionOptions{
+		Accounts: []string{recipient.PublicKey.ToBase58()},
+	})
+	require.NoError(t, err)
+	require.Nil(t, result.Err)
+	require.Len(t, result.Accounts, 1)
+	require.EqualValues(t, typesx.SOL/2, result.Accounts[0].LamportsDelta())
+
+	// the simulation does not change the ledger
+	_, ok := ledger.Account(recipient.PublicKey)
+	require.False(t, ok)
+}
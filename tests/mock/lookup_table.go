@@ -0,0 +1,34 @@
+package mock
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/program/address_lookup_table"
+	"github.com/EntySquare/solana/types"
+)
+
+// SetLookupTable stores the active address lookup table account of the authority with the addresses.
+func (s *RPCServer) SetLookupTable(address string, authority common.PublicKey, addresses []common.PublicKey) {
+	data := LookupTableData(authority, addresses)
+	s.SetAccount(address, RPCAccount{
+		Lamports: RentExemptBalance(uint64(len(data))),
+		Owner:    common.AddressLookupTableProgramID,
+		Data:     data,
+	})
+}
+
+// LookupTableData encodes the active address lookup table in the account layout.
+func LookupTableData(authority common.PublicKey, addresses []common.PublicKey) []byte {
+	data := make([]byte, types.LookupTableMetaSize, types.LookupTableMetaSize+uint64(32*len(addresses)))
+	binary.LittleEndian.PutUint32(data[0:4], uint32(address_lookup_table.ProgramStateLookupTable))
+	binary.LittleEndian.PutUint64(data[4:12], math.MaxUint64) // not deactivated
+	data[21] = 1
+	copy(data[22:54], authority.Bytes())
+	for _, address := range addresses {
+		data = append(data, address.Bytes()...)
+	}
+
+	return data
+}
@@ -0,0 +1,265 @@
+package mock
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+type (
+	// PubSubServer is a local fake of the solana PubSub websocket API.
+	// It accepts any *Subscribe request, and the test pushes the notifications with Notify.
+	PubSubServer struct {
+		server   *httptest.Server
+		upgrader websocket.Upgrader
+
+		mu       sync.Mutex
+		cond     *sync.Cond
+		nextID   uint64
+		conns    map[*websocket.Conn]*sync.Mutex
+		subs     map[uint64]PubSubSubscription
+		silent   map[*websocket.Conn]bool // connections that do not answer the pings
+		rejected map[string]string        // subscribe method -> error message
+	}
+
+	// PubSubSubscription is the subscription received by the fake server.
+	PubSubSubscription struct {
+		ID     uint64
+		Method string            // subscribe method, e.g. accountSubscribe
+		Params []json.RawMessage // subscribe params
+		conn   *websocket.Conn
+	}
+
+	pubsubRequest struct {
+		ID     uint64            `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+)
+
+// NewPubSubServer starts a new fake PubSub server.
+func NewPubSubServer() *PubSubServer {
+	s := &PubSubServer{
+		conns:    make(map[*websocket.Conn]*sync.Mutex),
+		subs:     make(map[uint64]PubSubSubscription),
+		silent:   make(map[*websocket.Conn]bool),
+		rejected: make(map[string]string),
+	}
+	s.cond = sync.NewCond(&s.mu)
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+
+	return s
+}
+
+// URL returns the websocket URL of the server.
+func (s *PubSubServer) URL() string {
+	return "ws" + strings.TrimPrefix(s.server.URL, "http")
+}
+
+// Close closes all the connections and stops the server.
+func (s *PubSubServer) Close() {
+	s.DropConnections()
+	s.server.Close()
+}
+
+// DropConnections closes all the client connections to simulate a network failure.
+// The active subscriptions are forgotten, like on a real node.
+func (s *PubSubServer) DropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.conns = make(map[*websocket.Conn]*sync.Mutex)
+	s.subs = make(map[uint64]PubSubSubscription)
+}
+
+// StopPongs stops answering the pings on the current connections to simulate half-open connections.
+// The connections stay open, the new ones answer the pings.
+func (s *PubSubServer) StopPongs() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for conn := range s.conns {
+		s.silent[conn] = true
+	}
+}
+
+// RejectSubscriptions answers the subscribe requests of the method with the error message; empty message accepts them again.
+func (s *PubSubServer) RejectSubscriptions(method, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if message == "" {
+		delete(s.rejected, method)
+		return
+	}
+	s.rejected[method] = message
+}
+
+// Subscriptions returns the active subscriptions.
+func (s *PubSubServer) Subscriptions() []PubSubSubscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]PubSubSubscription, 0, len(s.subs))
+	for _, sub := range s.subs {
+		result = append(result, sub)
+	}
+	return result
+}
+
+// WaitForSubscription waits for an active subscription with the given method.
+// Returns the latest such subscription and true, or false on timeout.
+func (s *PubSubServer) WaitForSubscription(method string, timeout time.Duration) (PubSubSubscription, bool) {
+	deadline := time.Now().Add(timeout)
+	timer := time.AfterFunc(timeout, func() {
+		s.mu.Lock()
+		s.cond.Broadcast()
+		s.mu.Unlock()
+	})
+	defer timer.Stop()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for {
+		var latest *PubSubSubscription
+		for _, sub := range s.subs {
+			if sub.Method == method && (latest == nil || sub.ID > latest.ID) {
+				sub := sub
+				latest = &sub
+			}
+		}
+		if latest != nil {
+			return *latest, true
+		}
+		if time.Now().After(deadline) {
+			return PubSubSubscription{}, false
+		}
+		s.cond.Wait()
+	}
+}
+
+// Notify sends the notification with the given result to the subscription.
+// Signature subscriptions are removed after the notification, like on a real node.
+// Returns false if the subscription does not exist.
+func (s *PubSubServer) Notify(subID uint64, result any) bool {
+	s.mu.Lock()
+	sub, ok := s.subs[subID]
+	if ok && sub.Method == "signatureSubscribe" {
+		delete(s.subs, subID)
+	}
+	writeMu := s.conns[sub.conn]
+	s.mu.Unlock()
+	if !ok || writeMu == nil {
+		return false
+	}
+
+	writeMu.Lock()
+	defer writeMu.Unlock()
+	err := sub.conn.WriteJSON(map[string]any{
+		"jsonrpc": "2.0",
+		"method":  strings.TrimSuffix(sub.Method, "Subscribe") + "Notification",
+		"params": map[string]any{
+			"result":       result,
+			"subscription": subID,
+		},
+	})
+
+	return err == nil
+}
+
+// handle upgrades the connection and serves the subscribe and unsubscribe requests.
+func (s *PubSubServer) handle(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	writeMu := &sync.Mutex{}
+	s.mu.Lock()
+	s.conns[conn] = writeMu
+	s.mu.Unlock()
+
+	conn.SetPingHandler(func(data string) error {
+		s.mu.Lock()
+		silent := s.silent[conn]
+		s.mu.Unlock()
+		if silent {
+			return nil
+		}
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		delete(s.silent, conn)
+		for id, sub := range s.subs {
+			if sub.conn == conn {
+				delete(s.subs, id)
+			}
+		}
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	for {
+		var req pubsubRequest
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+
+		var result, rpcErr any
+		var newSub *PubSubSubscription
+		s.mu.Lock()
+		message, rejected := s.rejected[req.Method]
+		switch {
+		case rejected:
+			rpcErr = map[string]any{"code": -32602, "message": message}
+		case strings.HasSuffix(req.Method, "Unsubscribe"):
+			var subID uint64
+			if len(req.Params) > 0 {
+				json.Unmarshal(req.Params[0], &subID)
+			}
+			_, ok := s.subs[subID]
+			delete(s.subs, subID)
+			result = ok
+		case strings.HasSuffix(req.Method, "Subscribe"):
+			s.nextID++
+			newSub = &PubSubSubscription{
+				ID:     s.nextID,
+				Method: req.Method,
+				Params: req.Params,
+				conn:   conn,
+			}
+			result = s.nextID
+		}
+		s.mu.Unlock()
+
+		writeMu.Lock()
+		resp := map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result}
+		if rpcErr != nil {
+			resp = map[string]any{"jsonrpc": "2.0", "id": req.ID, "error": rpcErr}
+		}
+		err := conn.WriteJSON(resp)
+		writeMu.Unlock()
+		if err != nil {
+			return
+		}
+
+		// the subscription becomes visible to the test only after the client got its id
+		s.mu.Lock()
+		if newSub != nil {
+			s.subs[newSub.ID] = *newSub
+		}
+		s.cond.Broadcast()
+		s.mu.Unlock()
+	}
+}
@@ -0,0 +1,487 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	sdktypes "github.com/EntySquare/solana-go-sdk/types"
+	"github.com/EntySquare/solana/utils"
+	"github.com/mr-tron/base58"
+)
+
+// Modes of the recorder.
+const (
+	RecorderModeReplay RecorderMode = iota // replays the fixture, no request reaches the network
+	RecorderModeRecord                     // sends the requests to the network and records them to the fixture
+)
+
+// Placeholders of the values ignored by the request matching.
+const (
+	placeholderSignature = "<signature>"
+	placeholderBlockhash = "11111111111111111111111111111111"
+)
+
+// Errors of the recorder.
+var (
+	ErrRecorderNoMatch  = errors.New("no recorded interaction matches the request")
+	ErrRecorderLoad     = errors.New("failed to load the recorder fixture")
+	ErrRecorderSave     = errors.New("failed to save the recorder fixture")
+	ErrRecorderNoValue  = errors.New("no recorded value")
+	ErrRecorderReadBody = errors.New("failed to read the http body")
+)
+
+type (
+	// RecorderMode selects whether the recorder records or replays the http traffic.
+	RecorderMode int
+
+	// Recorder is a http.RoundTripper recording the request/response pairs to a fixture file
+	// and replaying them later without network access.
+	// Use it with client.SetRPCTransport for the RPC requests and client.SetHTTPClient for the other http requests.
+	//
+	// The replayed request matches the recorded one by the http method, the URL and the body.
+	// The URL is recorded and matched without the userinfo and the query, which may carry the API keys.
+	// The JSON-RPC ids, the transaction signatures and the blockhashes are ignored, so a rebuilt
+	// and re-signed transaction matches its recording. Equal requests are replayed in the recorded order,
+	// the last response is repeated when the recording is exhausted.
+	Recorder struct {
+		mode RecorderMode
+		path string
+		next http.RoundTripper
+
+		mu      sync.Mutex
+		fixture RecorderFixture
+		keys    []string // match keys of the fixture interactions
+		used    []bool
+		last    map[string]int // match key -> index of the last replayed interaction
+	}
+
+	// RecorderOption configures the recorder.
+	RecorderOption func(*Recorder)
+
+	// RecorderFixture is the content of the fixture file.
+	RecorderFixture struct {
+		Values       map[string]string `json:"values,omitempty"`
+		Interactions []Interaction     `json:"interactions"`
+	}
+
+	// Interaction is the recorded request/response pair.
+	Interaction struct {
+		Request  RecordedRequest  `json:"request"`
+		Response RecordedResponse `json:"response"`
+	}
+
+	// RecordedRequest is the recorded http request.
+	// The JSON body is kept in Body for readability, any other body in Text.
+	RecordedRequest struct {
+		Method string          `json:"method"`
+		URL    string          `json:"url"`
+		Body   json.RawMessage `json:"body,omitempty"`
+		Text   string          `json:"text,omitempty"`
+	}
+
+	// RecordedResponse is the recorded http response.
+	// The JSON body is kept in Body for readability, any other body in Text.
+	RecordedResponse struct {
+		StatusCode int             `json:"status_code"`
+		Header     http.Header     `json:"header,omitempty"`
+		Body       json.RawMessage `json:"body,omitempty"`
+		Text       string          `json:"text,omitempty"`
+	}
+)
+
+// WithRecorderTransport sets the transport of the recorded requests; default is http.DefaultTransport.
+func WithRecorderTransport(transport http.RoundTripper) RecorderOption {
+	return func(r *Recorder) {
+		r.next = transport
+	}
+}
+
+// NewRecorder creates the recorder of the fixture file at the path.
+// The replay mode loads the fixture, the record mode starts an empty one written by Save.
+// Returns the recorder or an error if the fixture can not be loaded.
+func NewRecorder(path string, mode RecorderMode, opts ...RecorderOption) (*Recorder, error) {
+	r := &Recorder{
+		mode:    mode,
+		path:    path,
+		next:    http.DefaultTransport,
+		fixture: RecorderFixture{Values: map[string]string{}},
+		last:    map[string]int{},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if mode == RecorderModeRecord {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, utils.StackErrors(ErrRecorderLoad, err)
+	}
+	if err := json.Unmarshal(data, &r.fixture); err != nil {
+		return nil, utils.StackErrors(ErrRecorderLoad, err)
+	}
+	if r.fixture.Values == nil {
+		r.fixture.Values = map[string]string{}
+	}
+
+	r.keys = make([]string, len(r.fixture.Interactions))
+	r.used = make([]bool, len(r.fixture.Interactions))
+	for i, interaction := range r.fixture.Interactions {
+		r.keys[i] = matchKey(interaction.Request.Method, redactURL(interaction.Request.URL), joinBody(interaction.Request.Body, interaction.Request.Text))
+	}
+
+	return r, nil
+}
+
+// Mode returns the mode of the recorder.
+func (r *Recorder) Mode() RecorderMode {
+	return r.mode
+}
+
+// HTTPClient returns the http client using the recorder, to be passed to client.SetHTTPClient.
+func (r *Recorder) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Value returns the named value of the recording, e.g. the key of a random account created by the test.
+// The record mode stores the generated value, the replay mode returns the stored one.
+// The test fails if the replayed fixture has no such value.
+func (r *Recorder) Value(t testing.TB, name string, generate func() string) string {
+	t.Helper()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.mode == RecorderModeRecord {
+		value := generate()
+		r.fixture.Values[name] = value
+		return value
+	}
+
+	value, ok := r.fixture.Values[name]
+	if !ok {
+		t.Fatalf("%v: %s", ErrRecorderNoValue, name)
+	}
+	return value
+}
+
+// Save writes the recorded fixture to the file, it does nothing in the replay mode.
+func (r *Recorder) Save() error {
+	if r.mode != RecorderModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.fixture, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return utils.StackErrors(ErrRecorderSave, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return utils.StackErrors(ErrRecorderSave, err)
+	}
+	if err := os.WriteFile(r.path, data, 0o644); err != nil {
+		return utils.StackErrors(ErrRecorderSave, err)
+	}
+
+	return nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, utils.StackErrors(ErrRecorderReadBody, err)
+		}
+	}
+
+	if r.mode == RecorderModeRecord {
+		return r.record(req, body)
+	}
+	return r.replay(req, body)
+}
+
+// record sends the request and records the interaction.
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	out.ContentLength = int64(len(body))
+
+	resp, err := r.next.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, utils.StackErrors(ErrRecorderReadBody, err)
+	}
+
+	header := resp.Header.Clone()
+	header.Del("Date")
+	header.Del("Content-Length")
+
+	request := RecordedRequest{Method: req.Method, URL: redactURL(req.URL.String())}
+	request.Body, request.Text = splitBody(body)
+	response := RecordedResponse{StatusCode: resp.StatusCode, Header: header}
+	response.Body, response.Text = splitBody(respBody)
+
+	r.mu.Lock()
+	r.fixture.Interactions = append(r.fixture.Interactions, Interaction{Request: request, Response: response})
+	r.mu.Unlock()
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	return resp, nil
+}
+
+// replay answers the request with the first unused matching interaction.
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	key := matchKey(req.Method, redactURL(req.URL.String()), body)
+
+	r.mu.Lock()
+	index := -1
+	for i := range r.fixture.Interactions {
+		if !r.used[i] && r.keys[i] == key {
+			index = i
+			break
+		}
+	}
+	if index >= 0 {
+		r.used[index] = true
+		r.last[key] = index
+	} else if last, ok := r.last[key]; ok {
+		index = last
+	}
+	r.mu.Unlock()
+
+	if index < 0 {
+		return nil, fmt.Errorf("%w: %s %s %s", ErrRecorderNoMatch, req.Method, redactURL(req.URL.String()), body)
+	}
+
+	recorded := r.fixture.Interactions[index].Response
+	respBody := joinBody(recorded.Body, recorded.Text)
+	if recorded.Body != nil {
+		respBody = replaceRPCIDs(body, respBody)
+	}
+	header := recorded.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(respBody)),
+		ContentLength: int64(len(respBody)),
+		Request:       req,
+	}, nil
+}
+
+// redactURL returns the URL without the userinfo and the query.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	u.User = nil
+	u.RawQuery = ""
+	u.ForceQuery = false
+	return u.String()
+}
+
+// splitBody returns the JSON body as is or any other body as the text.
+func splitBody(body []byte) (json.RawMessage, string) {
+	if len(body) == 0 {
+		return nil, ""
+	}
+	if json.Valid(body) {
+		return body, ""
+	}
+	return nil, string(body)
+}
+
+// joinBody returns the body recorded by splitBody.
+func joinBody(body json.RawMessage, text string) []byte {
+	if body != nil {
+		return body
+	}
+	return []byte(text)
+}
+
+// matchKey returns the key of the request ignoring the JSON-RPC ids, the signatures and the blockhashes.
+func matchKey(method, url string, body []byte) string {
+	key := method + " " + url + " "
+
+	var requests []RPCRequest
+	if isBatch(body) {
+		if err := json.Unmarshal(body, &requests); err != nil {
+			return key + string(body)
+		}
+	} else {
+		var request RPCRequest
+		if err := json.Unmarshal(body, &request); err != nil || request.Method == "" {
+			return key + string(body)
+		}
+		requests = []RPCRequest{request}
+	}
+
+	normalized := make([]any, len(requests))
+	for i, request := range requests {
+		params := make([]any, len(request.Params))
+		for j, param := range request.Params {
+			params[j] = normalizeParam(request.Method, j, param)
+		}
+		normalized[i] = map[string]any{"method": request.Method, "params": params}
+	}
+
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return key + string(body)
+	}
+	return key + string(data)
+}
+
+// normalizeParam replaces the signatures and the blockhashes of the JSON-RPC param by placeholders.
+func normalizeParam(method string, index int, param json.RawMessage) any {
+	decoder := json.NewDecoder(bytes.NewReader(param))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return string(param)
+	}
+
+	if encoded, ok := value.(string); ok && index == 0 {
+		switch method {
+		case "sendTransaction", "simulateTransaction":
+			return normalizeTransaction(encoded)
+		case "getFeeForMessage":
+			return normalizeMessage(encoded)
+		case "isBlockhashValid":
+			return placeholderBlockhash
+		}
+	}
+
+	return normalizeSignatures(value)
+}
+
+// normalizeSignatures replaces the signatures in the value by the placeholder.
+func normalizeSignatures(value any) any {
+	switch v := value.(type) {
+	case string:
+		if decoded, err := base58.Decode(v); err == nil && len(decoded) == 64 {
+			return placeholderSignature
+		}
+	case []any:
+		for i := range v {
+			v[i] = normalizeSignatures(v[i])
+		}
+	case map[string]any:
+		for k := range v {
+			v[k] = normalizeSignatures(v[k])
+		}
+	}
+	return value
+}
+
+// normalizeTransaction returns the message of the encoded transaction with the placeholder blockhash;
+// the signatures are dropped.
+func normalizeTransaction(encoded string) string {
+	data, ok := decodeBinary(encoded)
+	if !ok {
+		return encoded
+	}
+	tx, err := sdktypes.TransactionDeserialize(data)
+	if err != nil {
+		return encoded
+	}
+	return serializeNormalizedMessage(tx.Message, encoded)
+}
+
+// normalizeMessage returns the encoded message with the placeholder blockhash.
+func normalizeMessage(encoded string) string {
+	data, ok := decodeBinary(encoded)
+	if !ok {
+		return encoded
+	}
+	message, err := sdktypes.MessageDeserialize(data)
+	if err != nil {
+		return encoded
+	}
+	return serializeNormalizedMessage(message, encoded)
+}
+
+func serializeNormalizedMessage(message sdktypes.Message, fallback string) string {
+	message.RecentBlockHash = placeholderBlockhash
+	data, err := message.Serialize()
+	if err != nil {
+		return fallback
+	}
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// decodeBinary decodes the base64 or the base58 encoded data.
+func decodeBinary(encoded string) ([]byte, bool) {
+	if data, err := base64.StdEncoding.DecodeString(encoded); err == nil {
+		return data, true
+	}
+	if data, err := base58.Decode(encoded); err == nil {
+		return data, true
+	}
+	return nil, false
+}
+
+// replaceRPCIDs sets the ids of the recorded JSON-RPC responses to the ids of the replayed requests.
+// The response is returned as is if it does not answer the request.
+func replaceRPCIDs(request, response []byte) []byte {
+	if !isBatch(response) {
+		var req, resp map[string]json.RawMessage
+		if json.Unmarshal(request, &req) != nil || json.Unmarshal(response, &resp) != nil || req["id"] == nil {
+			return response
+		}
+		resp["id"] = req["id"]
+		if data, err := json.Marshal(resp); err == nil {
+			return data
+		}
+		return response
+	}
+
+	var requests, responses []map[string]json.RawMessage
+	if json.Unmarshal(request, &requests) != nil || json.Unmarshal(response, &responses) != nil || len(requests) != len(responses) {
+		return response
+	}
+	for i := range responses {
+		if id, ok := requests[i]["id"]; ok {
+			responses[i]["id"] = id
+		}
+	}
+	if data, err := json.Marshal(responses); err == nil {
+		return data
+	}
+	return response
+}
+
+// isBatch reports whether the JSON body is a batch of the JSON-RPC messages.
+func isBatch(body []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(body), []byte("["))
+}
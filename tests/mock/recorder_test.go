@@ -0,0 +1,118 @@
+package mock_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/program/system"
+	"github.com/EntySquare/solana-go-sdk/types"
+	"github.com/EntySquare/solana/client"
+	commonx "github.com/EntySquare/solana/common"
+	"github.com/EntySquare/solana/tests/mock"
+	"github.com/EntySquare/solana/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	ctx := context.Background()
+	fixture := filepath.Join(t.TempDir(), "fixture.json")
+	recipient := common.PublicKeyFromString("11111111111111111111111111111112")
+
+	fake := mock.NewRPCServer()
+	fake.SetLatestBlockhash("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi", mock.DefaultLastValidBlockHeight)
+
+	recorder, err := mock.NewRecorder(fixture, mock.RecorderModeRecord)
+	require.NoError(t, err)
+	payer, err := commonx.AccountFromBase58(recorder.Value(t, "payer", func() string {
+		return commonx.AccountToBase58(types.NewAccount())
+	}))
+	require.NoError(t, err)
+	fake.SetBalance(payer.PublicKey.ToBase58(), 1_000_000)
+
+	c := client.New(client.SetSolanaEndpoint(fake.URL), client.SetRPCTransport(recorder))
+	txSource, err := c.NewTransaction(ctx, client.NewTransactionParams{
+		FeePayer: payer.PublicKey,
+		Instructions: []types.Instruction{system.Transfer(system.TransferParam{
+			From: payer.PublicKey, To: recipient, Amount: 1000,
+		})},
+		Signers: []types.Account{payer},
+	})
+	require.NoError(t, err)
+	recordedHash, err := c.SendTransaction(ctx, txSource)
+	require.NoError(t, err)
+	balance, err := c.GetSOLBalance(ctx, payer.PublicKey.ToBase58())
+	require.NoError(t, err)
+	require.NoError(t, recorder.Save())
+	fake.Close()
+
+	// the replay does not reach the closed server
+	replayer, err := mock.NewRecorder(fixture, mock.RecorderModeReplay)
+	require.NoError(t, err)
+	replayedPayer, err := commonx.AccountFromBase58(replayer.Value(t, "payer", nil))
+	require.NoError(t, err)
+	require.Equal(t, payer.PublicKey, replayedPayer.PublicKey)
+
+	c = client.New(client.SetSolanaEndpoint(fake.URL), client.SetRPCTransport(replayer))
+
+	// the transaction with another blockhash and signature matches the recorded one
+	tx, err := types.NewTransaction(types.NewTransactionParam{
+		Message: types.NewMessage(types.NewMessageParam{
+			FeePayer:        replayedPayer.PublicKey,
+			RecentBlockhash: mock.DefaultBlockhash,
+			Instructions: []types.Instruction{system.Transfer(system.TransferParam{
+				From: replayedPayer.PublicKey, To: recipient, Amount: 1000,
+			})},
+		}),
+		Signers: []types.Account{replayedPayer},
+	})
+	require.NoError(t, err)
+	txSource, err = utils.EncodeTransaction(tx)
+	require.NoError(t, err)
+	txhash, err := c.SendTransaction(ctx, txSource)
+	require.NoError(t, err)
+	require.Equal(t, recordedHash, txhash)
+
+	replayedBalance, err := c.GetSOLBalance(ctx, replayedPayer.PublicKey.ToBase58())
+	require.NoError(t, err)
+	require.Equal(t, balance, replayedBalance)
+
+	_, err = c.GetSOLBalance(ctx, types.NewAccount().PublicKey.ToBase58())
+	require.ErrorContains(t, err, mock.ErrRecorderNoMatch.Error())
+}
+
+func TestRecorder_MissingFixture(t *testing.T) {
+	_, err := mock.NewRecorder(filepath.Join(t.TempDir(), "missing.json"), mock.RecorderModeReplay)
+	require.ErrorIs(t, err, mock.ErrRecorderLoad)
+}
+
+func TestRecorder_RedactsURL(t *testing.T) {
+	ctx := context.Background()
+	fixture := filepath.Join(t.TempDir(), "fixture.json")
+	address := types.NewAccount().PublicKey.ToBase58()
+
+	fake := mock.NewRPCServer()
+	fake.SetBalance(address, 1000)
+
+	recorder, err := mock.NewRecorder(fixture, mock.RecorderModeRecord)
+	require.NoError(t, err)
+	c := client.New(client.SetSolanaEndpoint(fake.URL+"/?api-key=secret"), client.SetRPCTransport(recorder))
+	_, err = c.GetSOLBalance(ctx, address)
+	require.NoError(t, err)
+	require.NoError(t, recorder.Save())
+	fake.Close()
+
+	data, err := os.ReadFile(fixture)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "secret")
+
+	// the replay matches with another API key
+	replayer, err := mock.NewRecorder(fixture, mock.RecorderModeReplay)
+	require.NoError(t, err)
+	c = client.New(client.SetSolanaEndpoint(fake.URL+"/?api-key=other"), client.SetRPCTransport(replayer))
+	balance, err := c.GetSOLBalance(ctx, address)
+	require.NoError(t, err)
+	require.EqualValues(t, 1000, balance)
+}
@@ -0,0 +1,595 @@
+package mock
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	sdktypes "github.com/EntySquare/solana-go-sdk/types"
+	"github.com/mr-tron/base58"
+)
+
+// JSON-RPC error codes returned by the fake server.
+const (
+	RPCErrorInvalidRequest = -32600
+	RPCErrorMethodNotFound = -32601
+	RPCErrorInvalidParams  = -32602
+)
+
+// Defaults of the fake cluster state.
+const (
+	DefaultBlockhash            = "EkSnNWid2cvwEVnVx9aBqawnmiCNiDgp3gUdkDPTKN1N"
+	DefaultSlot                 = 1000
+	DefaultBlockHeight          = 900
+	DefaultLastValidBlockHeight = DefaultBlockHeight + 150
+	DefaultLamportsPerSignature = 5000
+)
+
+type (
+	// RPCServer is a local fake of the solana JSON-RPC API.
+	// It keeps the accounts, the signature statuses and the latest blockhash in memory
+	// and answers the common methods from this state; any method can be scripted with Handle.
+	// Every received request is recorded, so the test can assert on them.
+	RPCServer struct {
+		URL string // http URL of the server, to be passed to client.SetSolanaEndpoint
+
+		server *httptest.Server
+
+		mu                   sync.Mutex
+		handlers             map[string]RPCHandler
+		requests             []RPCRequest
+		sent                 []sdktypes.Transaction
+		accounts             map[string]RPCAccount
+		statuses             map[string]SignatureStatus
+		history              []RPCTransaction // confirmed transactions, oldest first
+		blockhash            string
+		lastValidBlockHeight uint64
+		slot                 uint64
+		blockHeight          uint64
+		simulation           Simulation
+	}
+
+	// RPCHandler answers the JSON-RPC request with the given params.
+	// Returns the result or an error; *RPCError is sent as is, other errors as internal errors.
+	RPCHandler func(params []json.RawMessage) (any, error)
+
+	// RPCRequest is the JSON-RPC request received by the fake server.
+	RPCRequest struct {
+		ID     json.RawMessage   `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+
+	// RPCError is the JSON-RPC error response.
+	RPCError struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    any    `json:"data,omitempty"`
+	}
+
+	// RPCAccount is the account stored by the fake server.
+	RPCAccount struct {
+		Lamports   uint64
+		Owner      common.PublicKey
+		Data       []byte
+		Executable bool
+		RentEpoch  uint64
+	}
+
+	// SignatureStatus is the status of the transaction signature.
+	SignatureStatus struct {
+		Slot               uint64
+		Confirmations      *uint64
+		ConfirmationStatus string // processed, confirmed or finalized
+		Err                any    // transaction error, nil if the transaction succeeded
+	}
+
+	rpcResponse struct {
+		JSONRPC string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Result  any             `json:"result,omitempty"`
+		Error   *RPCError       `json:"error,omitempty"`
+	}
+)
+
+// NewRPCServer starts a new fake JSON-RPC server.
+func NewRPCServer() *RPCServer {
+	s := &RPCServer{
+		handlers:             make(map[string]RPCHandler),
+		accounts:             make(map[string]RPCAccount),
+		statuses:             make(map[string]SignatureStatus),
+		blockhash:            DefaultBlockhash,
+		lastValidBlockHeight: DefaultLastValidBlockHeight,
+		slot:                 DefaultSlot,
+		blockHeight:          DefaultBlockHeight,
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	s.URL = s.server.URL
+
+	return s
+}
+
+// Close stops the server.
+func (s *RPCServer) Close() {
+	s.server.Close()
+}
+
+// Error returns the error message.
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+// Handle sets the handler of the method, it takes precedence over the built-in one.
+func (s *RPCServer) Handle(method string, handler RPCHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method] = handler
+}
+
+// Builtin returns the built-in handler of the method, e.g. to wrap it by Handle; nil if there is none.
+func (s *RPCServer) Builtin(method string) RPCHandler {
+	handler, _ := s.builtin(method)
+	return handler
+}
+
+// SetResult answers the method with the given result.
+func (s *RPCServer) SetResult(method string, result any) {
+	s.Handle(method, func([]json.RawMessage) (any, error) { return result, nil })
+}
+
+// SetError answers the method with the given JSON-RPC error.
+func (s *RPCServer) SetError(method string, code int, message string) {
+	s.Handle(method, func([]json.RawMessage) (any, error) {
+		return nil, &RPCError{Code: code, Message: message}
+	})
+}
+
+// Requests returns the received requests in order; the requests of a batch are recorded one by one.
+func (s *RPCServer) Requests() []RPCRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]RPCRequest(nil), s.requests...)
+}
+
+// RequestsOf returns the received requests of the method in order.
+func (s *RPCServer) RequestsOf(method string) []RPCRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []RPCRequest
+	for _, req := range s.requests {
+		if req.Method == method {
+			result = append(result, req)
+		}
+	}
+	return result
+}
+
+// SentTransactions returns the transactions received by sendTransaction in order.
+func (s *RPCServer) SentTransactions() []sdktypes.Transaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]sdktypes.Transaction(nil), s.sent...)
+}
+
+// SetAccount stores the account; getAccountInfo, getMultipleAccounts and getBalance read it.
+func (s *RPCServer) SetAccount(address string, account RPCAccount) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accounts[address] = account
+}
+
+// SetBalance sets the lamports of the account, creating a system account if it does not exist.
+func (s *RPCServer) SetBalance(address string, lamports uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	account, ok := s.accounts[address]
+	if !ok {
+		account.Owner = common.SystemProgramID
+	}
+	account.Lamports = lamports
+	s.accounts[address] = account
+}
+
+// DeleteAccount removes the account.
+func (s *RPCServer) DeleteAccount(address string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.accounts, address)
+}
+
+// Account returns the stored account and true, or false if it does not exist.
+func (s *RPCServer) Account(address string) (RPCAccount, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	account, ok := s.accounts[address]
+	return account, ok
+}
+
+// SetSignatureStatus sets the status of the transaction signature returned by getSignatureStatuses.
+func (s *RPCServer) SetSignatureStatus(signature string, status SignatureStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.statuses[signature] = status
+}
+
+// SetLatestBlockhash sets the blockhash returned by getLatestBlockhash.
+func (s *RPCServer) SetLatestBlockhash(blockhash string, lastValidBlockHeight uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blockhash = blockhash
+	s.lastValidBlockHeight = lastValidBlockHeight
+}
+
+// SetBlockHeight sets the slot and the block height of the cluster.
+func (s *RPCServer) SetBlockHeight(slot, blockHeight uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.slot = slot
+	s.blockHeight = blockHeight
+}
+
+// handle serves the single and the batch JSON-RPC requests.
+func (s *RPCServer) handle(w http.ResponseWriter, r *http.Request) {
+	var body json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if body = bytes.TrimSpace(body); len(body) > 0 && body[0] == '[' {
+		var batch []RPCRequest
+		if err := json.Unmarshal(body, &batch); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		responses := make([]rpcResponse, 0, len(batch))
+		for _, req := range batch {
+			responses = append(responses, s.serve(req))
+		}
+		json.NewEncoder(w).Encode(responses)
+		return
+	}
+
+	var req RPCRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	json.NewEncoder(w).Encode(s.serve(req))
+}
+
+// serve records the request and answers it with the scripted or the built-in handler.
+func (s *RPCServer) serve(req RPCRequest) rpcResponse {
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	handler, ok := s.handlers[req.Method]
+	s.mu.Unlock()
+
+	if !ok {
+		handler, ok = s.builtin(req.Method)
+	}
+	if !ok {
+		return rpcResponse{JSONRPC: "2.0", ID: req.ID, Error: &RPCError{
+			Code:    RPCErrorMethodNotFound,
+			Message: "Method not found: " + req.Method,
+		}}
+	}
+
+	result, err := handler(req.Params)
+	if err != nil {
+		rpcErr, ok := err.(*RPCError)
+		if !ok {
+			rpcErr = &RPCError{Code: -32603, Message: err.Error()}
+		}
+		return rpcResponse{JSONRPC: "2.0", ID: req.ID, Error: rpcErr}
+	}
+	if result == nil {
+		result = json.RawMessage("null")
+	}
+
+	return rpcResponse{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+// builtin returns the built-in handler of the method.
+func (s *RPCServer) builtin(method string) (RPCHandler, bool) {
+	handler, ok := map[string]RPCHandler{
+		"getHealth":                         func([]json.RawMessage) (any, error) { return "ok", nil },
+		"getSlot":                           s.getSlot,
+		"getBlockHeight":                    s.getBlockHeight,
+		"getLatestBlockhash":                s.getLatestBlockhash,
+		"isBlockhashValid":                  s.isBlockhashValid,
+		"getAccountInfo":                    s.getAccountInfo,
+		"getMultipleAccounts":               s.getMultipleAccounts,
+		"getBalance":                        s.getBalance,
+		"getMinimumBalanceForRentExemption": s.getMinimumBalanceForRentExemption,
+		"getFeeForMessage":                  s.getFeeForMessage,
+		"sendTransaction":                   s.sendTransaction,
+		"simulateTransaction":               s.simulateTransaction,
+		"requestAirdrop":                    s.requestAirdrop,
+		"getSignatureStatuses":              s.getSignatureStatuses,
+		"getTokenAccountsByOwner":           s.getTokenAccountsByOwner,
+		"getTokenAccountBalance":            s.getTokenAccountBalance,
+		"getTokenSupply":                    s.getTokenSupply,
+		"getTransaction":                    s.getTransaction,
+		"getSignaturesForAddress":           s.getSignaturesForAddress,
+	}[method]
+	return handler, ok
+}
+
+func (s *RPCServer) getSlot([]json.RawMessage) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.slot, nil
+}
+
+func (s *RPCServer) getBlockHeight([]json.RawMessage) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.blockHeight, nil
+}
+
+func (s *RPCServer) getLatestBlockhash([]json.RawMessage) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.withContext(map[string]any{
+		"blockhash":            s.blockhash,
+		"lastValidBlockHeight": s.lastValidBlockHeight,
+	}), nil
+}
+
+func (s *RPCServer) isBlockhashValid(params []json.RawMessage) (any, error) {
+	var blockhash string
+	if err := decodeParam(params, 0, &blockhash); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.withContext(blockhash == s.blockhash && s.blockHeight <= s.lastValidBlockHeight), nil
+}
+
+func (s *RPCServer) getAccountInfo(params []json.RawMessage) (any, error) {
+	var address string
+	if err := decodeParam(params, 0, &address); err != nil {
+		return nil, err
+	}
+	encoding := paramEncoding(params, 1)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	account, ok := s.accounts[address]
+	if !ok {
+		return s.withContext(nil), nil
+	}
+	encoded, err := encodeAccount(account, encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.withContext(encoded), nil
+}
+
+func (s *RPCServer) getMultipleAccounts(params []json.RawMessage) (any, error) {
+	var addresses []string
+	if err := decodeParam(params, 0, &addresses); err != nil {
+		return nil, err
+	}
+	encoding := paramEncoding(params, 1)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]any, 0, len(addresses))
+	for _, address := range addresses {
+		account, ok := s.accounts[address]
+		if !ok {
+			result = append(result, nil)
+			continue
+		}
+		encoded, err := encodeAccount(account, encoding)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, encoded)
+	}
+
+	return s.withContext(result), nil
+}
+
+func (s *RPCServer) getBalance(params []json.RawMessage) (any, error) {
+	var address string
+	if err := decodeParam(params, 0, &address); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.withContext(s.accounts[address].Lamports), nil
+}
+
+func (s *RPCServer) getMinimumBalanceForRentExemption(params []json.RawMessage) (any, error) {
+	var size uint64
+	if err := decodeParam(params, 0, &size); err != nil {
+		return nil, err
+	}
+	return RentExemptBalance(size), nil
+}
+
+func (s *RPCServer) getFeeForMessage(params []json.RawMessage) (any, error) {
+	var message string
+	if err := decodeParam(params, 0, &message); err != nil {
+		return nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(message)
+	if err != nil || len(data) == 0 {
+		return nil, &RPCError{Code: RPCErrorInvalidParams, Message: "invalid message"}
+	}
+
+	// the first byte of the header is the number of the signatures, it follows the version prefix of v0 messages
+	signatures := data[0]
+	if signatures&0x80 != 0 && len(data) > 1 {
+		signatures = data[1]
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.withContext(uint64(signatures) * DefaultLamportsPerSignature), nil
+}
+
+func (s *RPCServer) sendTransaction(params []json.RawMessage) (any, error) {
+	var rawTx string
+	if err := decodeParam(params, 0, &rawTx); err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	var err error
+	if paramEncoding(params, 1) == "base58" {
+		data, err = base58.Decode(rawTx)
+	} else {
+		data, err = base64.StdEncoding.DecodeString(rawTx)
+	}
+	if err != nil {
+		return nil, &RPCError{Code: RPCErrorInvalidParams, Message: "failed to decode transaction: " + err.Error()}
+	}
+	tx, err := sdktypes.TransactionDeserialize(data)
+	if err != nil || len(tx.Signatures) == 0 {
+		return nil, &RPCError{Code: RPCErrorInvalidParams, Message: "failed to deserialize transaction"}
+	}
+	signature := base58.Encode(tx.Signatures[0])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sent = append(s.sent, tx)
+	// the sent transactions are finalized at once; the test may override the status later
+	s.statuses[signature] = SignatureStatus{Slot: s.slot, ConfirmationStatus: "finalized"}
+
+	return signature, nil
+}
+
+func (s *RPCServer) requestAirdrop(params []json.RawMessage) (any, error) {
+	var address string
+	var lamports uint64
+	if err := decodeParam(params, 0, &address); err != nil {
+		return nil, err
+	}
+	if err := decodeParam(params, 1, &lamports); err != nil {
+		return nil, err
+	}
+
+	signature := make([]byte, 64)
+	rand.Read(signature)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	account, ok := s.accounts[address]
+	if !ok {
+		account.Owner = common.SystemProgramID
+	}
+	account.Lamports += lamports
+	s.accounts[address] = account
+	s.statuses[base58.Encode(signature)] = SignatureStatus{Slot: s.slot, ConfirmationStatus: "finalized"}
+
+	return base58.Encode(signature), nil
+}
+
+func (s *RPCServer) getSignatureStatuses(params []json.RawMessage) (any, error) {
+	var signatures []string
+	if err := decodeParam(params, 0, &signatures); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]any, 0, len(signatures))
+	for _, signature := range signatures {
+		status, ok := s.statuses[signature]
+		if !ok {
+			result = append(result, nil)
+			continue
+		}
+		result = append(result, map[string]any{
+			"slot":               status.Slot,
+			"confirmations":      status.Confirmations,
+			"confirmationStatus": status.ConfirmationStatus,
+			"err":                status.Err,
+		})
+	}
+
+	return s.withContext(result), nil
+}
+
+// withContext wraps the value with the context of the current slot; the caller holds the lock.
+func (s *RPCServer) withContext(value any) map[string]any {
+	return map[string]any{
+		"context": map[string]any{"slot": s.slot},
+		"value":   value,
+	}
+}
+
+// RentExemptBalance returns the rent exempt balance of the account with the given data size.
+func RentExemptBalance(size uint64) uint64 {
+	// 128 bytes of the account metadata, 3480 lamports per byte-year for 2 years
+	return (128 + size) * 3480 * 2
+}
+
+// decodeParam decodes the param at the given index.
+func decodeParam(params []json.RawMessage, i int, v any) error {
+	if i >= len(params) {
+		return &RPCError{Code: RPCErrorInvalidParams, Message: fmt.Sprintf("missing param %d", i)}
+	}
+	if err := json.Unmarshal(params[i], v); err != nil {
+		return &RPCError{Code: RPCErrorInvalidParams, Message: fmt.Sprintf("invalid param %d: %s", i, err)}
+	}
+	return nil
+}
+
+// paramEncoding returns the encoding of the config param at the given index; default is base64.
+func paramEncoding(params []json.RawMessage, i int) string {
+	var cfg struct {
+		Encoding string `json:"encoding"`
+	}
+	if i < len(params) {
+		json.Unmarshal(params[i], &cfg)
+	}
+	if cfg.Encoding == "" {
+		return "base64"
+	}
+	return cfg.Encoding
+}
+
+// encodeAccount encodes the account in the requested encoding.
+func encodeAccount(account RPCAccount, encoding string) (map[string]any, error) {
+	result := map[string]any{
+		"lamports":   account.Lamports,
+		"owner":      account.Owner.ToBase58(),
+		"executable": account.Executable,
+		"rentEpoch":  account.RentEpoch,
+		"space":      len(account.Data),
+	}
+
+	switch encoding {
+	case "base64":
+		result["data"] = []string{base64.StdEncoding.EncodeToString(account.Data), "base64"}
+	case "base58":
+		result["data"] = []string{base58.Encode(account.Data), "base58"}
+	default:
+		return nil, &RPCError{Code: RPCErrorInvalidParams, Message: "unsupported encoding: " + encoding}
+	}
+
+	return result, nil
+}
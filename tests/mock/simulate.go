@@ -0,0 +1,82 @@
+package mock
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	sdktypes "github.com/EntySquare/solana-go-sdk/types"
+)
+
+// Simulation is the outcome of the transactions simulated by simulateTransaction.
+type Simulation struct {
+	Err           any                   // transaction error, nil if the simulation succeeded
+	Logs          []string              // program logs
+	UnitsConsumed uint64                // compute units consumed by the transaction
+	Accounts      map[string]RPCAccount // accounts after the transaction by address; the stored account is returned for the others
+}
+
+// SetSimulation sets the outcome of the simulateTransaction requests; by default the simulation succeeds without logs.
+func (s *RPCServer) SetSimulation(simulation Simulation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.simulation = simulation
+}
+
+func (s *RPCServer) simulateTransaction(params []json.RawMessage) (any, error) {
+	var rawTx string
+	if err := decodeParam(params, 0, &rawTx); err != nil {
+		return nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(rawTx)
+	if err != nil {
+		return nil, &RPCError{Code: RPCErrorInvalidParams, Message: "failed to decode transaction: " + err.Error()}
+	}
+	if _, err := sdktypes.TransactionDeserialize(data); err != nil {
+		return nil, &RPCError{Code: RPCErrorInvalidParams, Message: "failed to deserialize transaction"}
+	}
+
+	var cfg struct {
+		Accounts *struct {
+			Encoding  string   `json:"encoding"`
+			Addresses []string `json:"addresses"`
+		} `json:"accounts"`
+	}
+	if len(params) > 1 {
+		json.Unmarshal(params[1], &cfg)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var accounts []any
+	if cfg.Accounts != nil {
+		encoding := cfg.Accounts.Encoding
+		if encoding == "" {
+			encoding = "base64"
+		}
+		accounts = make([]any, 0, len(cfg.Accounts.Addresses))
+		for _, address := range cfg.Accounts.Addresses {
+			account, ok := s.simulation.Accounts[address]
+			if !ok {
+				account, ok = s.accounts[address]
+			}
+			if !ok {
+				accounts = append(accounts, nil)
+				continue
+			}
+			encoded, err := encodeAccount(account, encoding)
+			if err != nil {
+				return nil, err
+			}
+			accounts = append(accounts, encoded)
+		}
+	}
+
+	return s.withContext(map[string]any{
+		"err":           s.simulation.Err,
+		"logs":          s.simulation.Logs,
+		"accounts":      accounts,
+		"unitsConsumed": s.simulation.UnitsConsumed,
+		"returnData":    nil,
+	}), nil
+}
@@ -0,0 +1,244 @@
+package mock
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/program/token"
+	commonx "github.com/EntySquare/solana/common"
+	"github.com/EntySquare/solana/utils"
+)
+
+// SetMint stores the SPL token mint account.
+func (s *RPCServer) SetMint(address string, mint token.MintAccount) {
+	s.SetAccount(address, RPCAccount{
+		Lamports: RentExemptBalance(token.MintAccountSize),
+		Owner:    common.TokenProgramID,
+		Data:     MintAccountData(mint),
+	})
+}
+
+// SetTokenAccount stores the SPL token account.
+func (s *RPCServer) SetTokenAccount(address string, account token.TokenAccount) {
+	s.SetAccount(address, RPCAccount{
+		Lamports: RentExemptBalance(token.TokenAccountSize),
+		Owner:    common.TokenProgramID,
+		Data:     TokenAccountData(account),
+	})
+}
+
+// MintAccountData encodes the mint in the SPL token mint account layout.
+func MintAccountData(mint token.MintAccount) []byte {
+	data := make([]byte, token.MintAccountSize)
+	putOptionalKey(data[0:36], mint.MintAuthority)
+	binary.LittleEndian.PutUint64(data[36:44], mint.Supply)
+	data[44] = mint.Decimals
+	if mint.IsInitialized {
+		data[45] = 1
+	}
+	putOptionalKey(data[46:82], mint.FreezeAuthority)
+
+	return data
+}
+
+// TokenAccountData encodes the token account in the SPL token account layout.
+func TokenAccountData(account token.TokenAccount) []byte {
+	data := make([]byte, token.TokenAccountSize)
+	copy(data[0:32], account.Mint.Bytes())
+	copy(data[32:64], account.Owner.Bytes())
+	binary.LittleEndian.PutUint64(data[64:72], account.Amount)
+	putOptionalKey(data[72:108], account.Delegate)
+	data[108] = uint8(account.State)
+	if account.IsNative != nil {
+		copy(data[109:113], token.Some)
+		binary.LittleEndian.PutUint64(data[113:121], *account.IsNative)
+	}
+	binary.LittleEndian.PutUint64(data[121:129], account.DelegatedAmount)
+	putOptionalKey(data[129:165], account.CloseAuthority)
+
+	return data
+}
+
+// deserializeTokenAccount decodes the token account of the SPL Token or the Token-2022 program.
+func deserializeTokenAccount(account RPCAccount) (token.TokenAccount, error) {
+	if !commonx.IsTokenProgram(account.Owner) {
+		return token.TokenAccount{}, token.ErrInvalidAccountOwner
+	}
+	data := account.Data
+	if len(data) > token.TokenAccountSize {
+		data = data[:token.TokenAccountSize] // the Token-2022 extensions follow the token account
+	}
+	return token.TokenAccountFromData(data)
+}
+
+// putOptionalKey encodes the optional public key as COption<Pubkey>.
+func putOptionalKey(data []byte, key *common.PublicKey) {
+	if key == nil {
+		return
+	}
+	copy(data[0:4], token.Some)
+	copy(data[4:36], key.Bytes())
+}
+
+func (s *RPCServer) getTokenAccountsByOwner(params []json.RawMessage) (any, error) {
+	var owner string
+	var filter struct {
+		Mint      string `json:"mint"`
+		ProgramID string `json:"programId"`
+	}
+	if err := decodeParam(params, 0, &owner); err != nil {
+		return nil, err
+	}
+	if err := decodeParam(params, 1, &filter); err != nil {
+		return nil, err
+	}
+	if filter.Mint == "" && filter.ProgramID == "" {
+		return nil, &RPCError{Code: RPCErrorInvalidParams, Message: "mint or programId filter is required"}
+	}
+	encoding := paramEncoding(params, 2)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	addresses := make([]string, 0, len(s.accounts))
+	for address := range s.accounts {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	result := make([]any, 0)
+	for _, address := range addresses {
+		account := s.accounts[address]
+		if filter.ProgramID != "" && account.Owner.ToBase58() != filter.ProgramID {
+			continue
+		}
+		tokenAccount, err := deserializeTokenAccount(account)
+		if err != nil || tokenAccount.Owner.ToBase58() != owner {
+			continue
+		}
+		if filter.Mint != "" && tokenAccount.Mint.ToBase58() != filter.Mint {
+			continue
+		}
+
+		var encoded map[string]any
+		if encoding == "jsonParsed" {
+			encoded = s.parsedTokenAccount(account, tokenAccount)
+		} else if encoded, err = encodeAccount(account, encoding); err != nil {
+			return nil, err
+		}
+		result = append(result, map[string]any{"pubkey": address, "account": encoded})
+	}
+
+	return s.withContext(result), nil
+}
+
+func (s *RPCServer) getTokenAccountBalance(params []json.RawMessage) (any, error) {
+	var address string
+	if err := decodeParam(params, 0, &address); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	account, ok := s.accounts[address]
+	if !ok {
+		return nil, &RPCError{Code: RPCErrorInvalidParams, Message: "Invalid param: could not find account"}
+	}
+	tokenAccount, err := deserializeTokenAccount(account)
+	if err != nil {
+		return nil, &RPCError{Code: RPCErrorInvalidParams, Message: "Invalid param: not a Token account"}
+	}
+
+	return s.withContext(tokenAmount(tokenAccount.Amount, s.decimals(tokenAccount.Mint))), nil
+}
+
+func (s *RPCServer) getTokenSupply(params []json.RawMessage) (any, error) {
+	var address string
+	if err := decodeParam(params, 0, &address); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	mint, err := token.MintAccountFromData(s.accounts[address].Data)
+	if err != nil {
+		return nil, &RPCError{Code: RPCErrorInvalidParams, Message: "Invalid param: not a Token mint"}
+	}
+
+	return s.withContext(tokenAmount(mint.Supply, mint.Decimals)), nil
+}
+
+// parsedTokenAccount encodes the token account in the jsonParsed encoding; the caller holds the lock.
+func (s *RPCServer) parsedTokenAccount(account RPCAccount, tokenAccount token.TokenAccount) map[string]any {
+	decimals := s.decimals(tokenAccount.Mint)
+
+	state := "initialized"
+	switch tokenAccount.State {
+	case token.TokenAccountStateUninitialized:
+		state = "uninitialized"
+	case token.TokenAccountFrozen:
+		state = "frozen"
+	}
+
+	info := map[string]any{
+		"isNative":    tokenAccount.IsNative != nil,
+		"mint":        tokenAccount.Mint.ToBase58(),
+		"owner":       tokenAccount.Owner.ToBase58(),
+		"state":       state,
+		"tokenAmount": tokenAmount(tokenAccount.Amount, decimals),
+	}
+	if tokenAccount.Delegate != nil {
+		info["delegate"] = tokenAccount.Delegate.ToBase58()
+		info["delegatedAmount"] = tokenAmount(tokenAccount.DelegatedAmount, decimals)
+	}
+
+	return map[string]any{
+		"lamports":   account.Lamports,
+		"owner":      account.Owner.ToBase58(),
+		"executable": account.Executable,
+		"rentEpoch":  account.RentEpoch,
+		"space":      len(account.Data),
+		"data": map[string]any{
+			"program": parsedTokenProgram(account.Owner),
+			"space":   len(account.Data),
+			"parsed":  map[string]any{"type": "account", "info": info},
+		},
+	}
+}
+
+// decimals returns the decimals of the stored mint or 0; the caller holds the lock.
+func (s *RPCServer) decimals(mint common.PublicKey) uint8 {
+	data := s.accounts[mint.ToBase58()].Data
+	if len(data) > token.MintAccountSize {
+		data = data[:token.MintAccountSize] // the Token-2022 extensions follow the mint
+	}
+	mintAccount, err := token.MintAccountFromData(data)
+	if err != nil {
+		return 0
+	}
+	return mintAccount.Decimals
+}
+
+// tokenAmount encodes the amount of the token like the RPC node.
+func tokenAmount(amount uint64, decimals uint8) map[string]any {
+	uiAmount := utils.AmountToFloat64(amount, decimals)
+	return map[string]any{
+		"amount":         strconv.FormatUint(amount, 10),
+		"decimals":       decimals,
+		"uiAmount":       uiAmount,
+		"uiAmountString": utils.Float64ToString(uiAmount),
+	}
+}
+
+// parsedTokenProgram returns the program name of the jsonParsed token account.
+func parsedTokenProgram(owner common.PublicKey) string {
+	if owner == commonx.Token2022ProgramID {
+		return "spl-token-2022"
+	}
+	return "spl-token"
+}
@@ -10,18 +10,29 @@ import (
 	"github.com/EntySquare/solana/client"
 	"github.com/EntySquare/solana/instructions"
 	"github.com/EntySquare/solana/token_metadata"
+	typesx "github.com/EntySquare/solana/types"
+	"github.com/EntySquare/solana/utils"
 )
 
 type (
 	// TransactionBuilder is a builder for transactions.
 	TransactionBuilder struct {
-		client           solanaClient                   // solana client wrapper
-		feePayer         *common.PublicKey              // transaction fee payer
-		signers          []types.Account                // additional transaction signers
-		instructions     []instructions.InstructionFunc // transaction instructions
-		isDurrableTx     bool                           // is durable transaction
-		durableNonce     *common.PublicKey              // durable nonce account
-		durableNonceAuth *common.PublicKey              // durable nonce auth account
+		client           solanaClient                      // solana client wrapper
+		feePayer         *common.PublicKey                 // transaction fee payer
+		signers          []types.Account                   // additional transaction signers
+		instructions     []instructions.InstructionFunc    // transaction instructions
+		isDurrableTx     bool                              // is durable transaction
+		durableNonce     *common.PublicKey                 // durable nonce account
+		durableNonceAuth *common.PublicKey                 // durable nonce auth account
+		lookupTables     []types.AddressLookupTableAccount // address lookup tables for v0 transaction
+		lookupTableAddrs []common.PublicKey                // address lookup tables to fetch on build
+		computeUnitLimit *uint32                           // max compute units the transaction can consume
+		computeUnitPrice *uint64                           // compute unit price in micro-lamports
+		autoUnitPrice    bool                              // estimate compute unit price from recent fees
+		maxUnitPrice     uint64                            // max estimated compute unit price; 0 means no limit
+		autoUnitLimit    bool                              // set compute unit limit from the simulation
+		unitLimitMargin  uint32                            // margin added to the simulated compute units, in percent
+		preflight        bool                              // simulate the transaction before returning it
 	}
 
 	// solanaClient is a wrapper for the solana client.
@@ -29,9 +40,15 @@ type (
 		DefaultDecimals() uint8
 		GetMinimumBalanceForRentExemption(ctx context.Context, size uint64) (uint64, error)
 		GetTokenAccountInfo(ctx context.Context, base58AtaAddr string) (token.TokenAccount, error)
+		GetMintInfo(ctx context.Context, base58MintAddr string) (token.MintAccount, error)
+		GetTokenProgramID(ctx context.Context, base58Addr string) (common.PublicKey, error)
 		GetTokenMetadata(ctx context.Context, base58MintAddr string) (*token_metadata.Metadata, error)
 		GetMasterEditionSupply(ctx context.Context, masterMint common.PublicKey) (current, max uint64, err error)
 		GetEditionInfo(ctx context.Context, base58MintAddr string) (*token_metadata.Edition, error)
+		GetSlot(ctx context.Context) (uint64, error)
+		GetLookupTableAddresses(ctx context.Context, base58Addr string) ([]common.PublicKey, error)
+		EstimateComputeUnitPrice(ctx context.Context, base58Addrs ...string) (uint64, error)
+		SimulateTransaction(ctx context.Context, txSource string, opts client.SimulateTransactionOptions) (client.SimulateTransactionResult, error)
 		NewTransaction(ctx context.Context, params client.NewTransactionParams) (string, error)
 		NewDurableTransaction(ctx context.Context, params client.NewDurableTransactionParams) (string, error)
 	}
@@ -68,9 +85,76 @@ func (tb *TransactionBuilder) AddSigner(signer types.Account) *TransactionBuilde
 	return tb
 }
 
+// AddLookupTable adds an address lookup table to the transaction.
+// If at least one lookup table is added, the transaction is built as a v0 transaction
+// and the accounts found in the table are resolved through it.
+func (tb *TransactionBuilder) AddLookupTable(table types.AddressLookupTableAccount) *TransactionBuilder {
+	tb.lookupTables = append(tb.lookupTables, table)
+	return tb
+}
+
+// AddLookupTableAddress adds an address lookup table by its address to the transaction.
+// The table addresses are fetched from the chain when the transaction is built.
+func (tb *TransactionBuilder) AddLookupTableAddress(table common.PublicKey) *TransactionBuilder {
+	tb.lookupTableAddrs = append(tb.lookupTableAddrs, table)
+	return tb
+}
+
+// SetComputeUnitLimit sets the max compute units the transaction is allowed to consume.
+// Overrides the automatic compute unit limit.
+func (tb *TransactionBuilder) SetComputeUnitLimit(units uint32) *TransactionBuilder {
+	tb.computeUnitLimit = &units
+	tb.autoUnitLimit = false
+	return tb
+}
+
+// SetAutoComputeUnitLimit sets the compute unit limit from the simulation of the draft transaction.
+// The limit is the consumed compute units plus marginPercent percent of them,
+// e.g. 10 sets the limit to 110% of the consumed units.
+// The transaction is rebuilt and re-signed with the estimated limit.
+// Overrides the fixed compute unit limit.
+func (tb *TransactionBuilder) SetAutoComputeUnitLimit(marginPercent uint32) *TransactionBuilder {
+	tb.computeUnitLimit = nil
+	tb.autoUnitLimit = true
+	tb.unitLimitMargin = marginPercent
+	return tb
+}
+
+// SetComputeUnitPrice sets the compute unit price in micro-lamports to prioritize the transaction.
+// Overrides the automatic compute unit price.
+func (tb *TransactionBuilder) SetComputeUnitPrice(microLamports uint64) *TransactionBuilder {
+	tb.computeUnitPrice = &microLamports
+	tb.autoUnitPrice = false
+	return tb
+}
+
+// SetAutoComputeUnitPrice sets the compute unit price from the recent prioritization fees
+// paid for the writable accounts of the transaction.
+// maxMicroLamports caps the estimated price; 0 means no cap.
+// The price instruction is skipped if the estimated price is 0.
+// Overrides the fixed compute unit price.
+func (tb *TransactionBuilder) SetAutoComputeUnitPrice(maxMicroLamports uint64) *TransactionBuilder {
+	tb.computeUnitPrice = nil
+	tb.autoUnitPrice = true
+	tb.maxUnitPrice = maxMicroLamports
+	return tb
+}
+
+// SetPreflight enables the transaction simulation before the built transaction is returned.
+// If the simulation fails, Build returns client.ErrSimulationFailed together with the decoded transaction error.
+func (tb *TransactionBuilder) SetPreflight(enabled bool) *TransactionBuilder {
+	tb.preflight = enabled
+	return tb
+}
+
 // Build builds the transaction.
 // Returns the base64 encoded transaction or an error.
 func (tb *TransactionBuilder) Build(ctx context.Context) (string, error) {
+	lookupTables, err := tb.resolveLookupTables(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to build transaction: %w", err)
+	}
+
 	instructions := make([]types.Instruction, 0, len(tb.instructions))
 	for _, instruction := range tb.instructions {
 		subInstructions, err := instruction(ctx, tb.client)
@@ -82,33 +166,221 @@ func (tb *TransactionBuilder) Build(ctx context.Context) (string, error) {
 		}
 	}
 
+	if tb.hasComputeBudget() {
+		for _, instruction := range instructions {
+			if instruction.ProgramID == common.ComputeBudgetProgramID {
+				return "", fmt.Errorf("failed to build transaction: compute budget is set both in the builder and in the instructions")
+			}
+		}
+	}
+
+	unitPrice, err := tb.resolveComputeUnitPrice(ctx, instructions)
+	if err != nil {
+		return "", fmt.Errorf("failed to build transaction: %w", err)
+	}
+
+	unitLimit := tb.computeUnitLimit
+	if tb.autoUnitLimit {
+		unitLimit, err = tb.estimateComputeUnitLimit(ctx, instructions, lookupTables, unitPrice)
+		if err != nil {
+			return "", fmt.Errorf("failed to build transaction: %w", err)
+		}
+	}
+
+	budget, err := tb.computeBudgetInstructions(ctx, unitLimit, unitPrice)
+	if err != nil {
+		return "", fmt.Errorf("failed to build transaction: %w", err)
+	}
+
+	txSource, err := tb.compile(ctx, append(budget, instructions...), lookupTables)
+	if err != nil {
+		return "", fmt.Errorf("failed to build transaction: %w", err)
+	}
+
+	// the auto compute unit limit already simulated the transaction
+	if tb.preflight && !tb.autoUnitLimit {
+		if _, err := tb.simulate(ctx, txSource); err != nil {
+			return "", fmt.Errorf("failed to build transaction: %w", err)
+		}
+	}
+
+	return txSource, nil
+}
+
+// compile compiles the instructions into the base64 encoded signed transaction.
+func (tb *TransactionBuilder) compile(ctx context.Context, instructions []types.Instruction, lookupTables []types.AddressLookupTableAccount) (string, error) {
 	if tb.isDurrableTx {
 		if tb.durableNonce == nil || *tb.durableNonce == (common.PublicKey{}) {
-			return "", fmt.Errorf("failed to build transaction: missing or invalid durable nonce public key")
+			return "", fmt.Errorf("missing or invalid durable nonce public key")
 		}
 		if tb.durableNonceAuth == nil || *tb.durableNonceAuth == (common.PublicKey{}) {
-			return "", fmt.Errorf("failed to build transaction: missing or invalid durable nonce auth public key")
+			return "", fmt.Errorf("missing or invalid durable nonce auth public key")
 		}
 		if tb.feePayer == nil || *tb.feePayer == (common.PublicKey{}) {
 			tb.feePayer = tb.durableNonceAuth
 		}
 
 		return tb.client.NewDurableTransaction(ctx, client.NewDurableTransactionParams{
-			FeePayer:     tb.feePayer,
-			Instructions: instructions,
-			Signers:      tb.signers,
-			DurableNonce: *tb.durableNonce,
-			NonceAuth:    *tb.durableNonceAuth,
+			FeePayer:            tb.feePayer,
+			Instructions:        instructions,
+			Signers:             tb.signers,
+			DurableNonce:        *tb.durableNonce,
+			NonceAuth:           *tb.durableNonceAuth,
+			AddressLookupTables: lookupTables,
 		})
 	}
 
 	if tb.feePayer == nil || *tb.feePayer == (common.PublicKey{}) {
-		return "", fmt.Errorf("failed to build transaction: missing or invalid fee payer public key")
+		return "", fmt.Errorf("missing or invalid fee payer public key")
 	}
 
 	return tb.client.NewTransaction(ctx, client.NewTransactionParams{
-		FeePayer:     *tb.feePayer,
-		Instructions: instructions,
-		Signers:      tb.signers,
+		FeePayer:            *tb.feePayer,
+		Instructions:        instructions,
+		Signers:             tb.signers,
+		AddressLookupTables: lookupTables,
 	})
 }
+
+// simulate simulates the transaction and returns an error if it fails.
+// Returns the compute units consumed by the transaction.
+func (tb *TransactionBuilder) simulate(ctx context.Context, txSource string) (uint64, error) {
+	result, err := tb.client.SimulateTransaction(ctx, txSource, client.SimulateTransactionOptions{})
+	if err != nil {
+		return 0, err
+	}
+	if result.Err != nil {
+		return 0, utils.StackErrors(client.ErrSimulationFailed, result.Err)
+	}
+
+	return result.UnitsConsumed, nil
+}
+
+// resolveLookupTables fetches the lookup tables added by address
+// and merges them with the lookup tables added directly.
+func (tb *TransactionBuilder) resolveLookupTables(ctx context.Context) ([]types.AddressLookupTableAccount, error) {
+	if len(tb.lookupTableAddrs) == 0 {
+		return tb.lookupTables, nil
+	}
+
+	tables := make([]types.AddressLookupTableAccount, 0, len(tb.lookupTables)+len(tb.lookupTableAddrs))
+	tables = append(tables, tb.lookupTables...)
+	for _, addr := range tb.lookupTableAddrs {
+		addresses, err := tb.client.GetLookupTableAddresses(ctx, addr.ToBase58())
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve lookup table %s: %w", addr.ToBase58(), err)
+		}
+		tables = append(tables, types.AddressLookupTableAccount{
+			Key:       addr,
+			Addresses: addresses,
+		})
+	}
+
+	return tables, nil
+}
+
+// hasComputeBudget returns true if any compute budget option is set.
+func (tb *TransactionBuilder) hasComputeBudget() bool {
+	return tb.computeUnitLimit != nil || tb.autoUnitLimit || tb.computeUnitPrice != nil || tb.autoUnitPrice
+}
+
+// resolveComputeUnitPrice returns the fixed compute unit price or estimates it from the recent fees
+// paid for the writable accounts of the instructions.
+// Returns nil if the compute unit price is not set or the estimated price is 0.
+func (tb *TransactionBuilder) resolveComputeUnitPrice(ctx context.Context, txInstructions []types.Instruction) (*uint64, error) {
+	if !tb.autoUnitPrice {
+		return tb.computeUnitPrice, nil
+	}
+
+	price, err := tb.client.EstimateComputeUnitPrice(ctx, writableAccounts(txInstructions)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate compute unit price: %w", err)
+	}
+	if price == 0 {
+		return nil, nil
+	}
+	if tb.maxUnitPrice > 0 && price > tb.maxUnitPrice {
+		price = tb.maxUnitPrice
+	}
+
+	return &price, nil
+}
+
+// estimateComputeUnitLimit simulates the draft transaction with the max compute unit limit
+// and returns the consumed compute units plus the configured margin.
+func (tb *TransactionBuilder) estimateComputeUnitLimit(
+	ctx context.Context,
+	txInstructions []types.Instruction,
+	lookupTables []types.AddressLookupTableAccount,
+	unitPrice *uint64,
+) (*uint32, error) {
+	maxLimit := typesx.MaxComputeUnitLimit
+	budget, err := tb.computeBudgetInstructions(ctx, &maxLimit, unitPrice)
+	if err != nil {
+		return nil, err
+	}
+
+	draft, err := tb.compile(ctx, append(budget, txInstructions...), lookupTables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build draft transaction: %w", err)
+	}
+
+	consumed, err := tb.simulate(ctx, draft)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate compute unit limit: %w", err)
+	}
+
+	units := consumed + consumed*uint64(tb.unitLimitMargin)/100
+	if units == 0 {
+		units = 1
+	}
+	if units > uint64(typesx.MaxComputeUnitLimit) {
+		units = uint64(typesx.MaxComputeUnitLimit)
+	}
+
+	limit := uint32(units)
+	return &limit, nil
+}
+
+// computeBudgetInstructions returns the compute budget instructions for the given limit and price.
+// They must be put at the front of the transaction instructions.
+func (tb *TransactionBuilder) computeBudgetInstructions(ctx context.Context, unitLimit *uint32, unitPrice *uint64) ([]types.Instruction, error) {
+	budget := make([]instructions.InstructionFunc, 0, 2)
+	if unitLimit != nil {
+		budget = append(budget, instructions.SetComputeUnitLimit(*unitLimit))
+	}
+	if unitPrice != nil {
+		budget = append(budget, instructions.SetComputeUnitPrice(*unitPrice))
+	}
+
+	result := make([]types.Instruction, 0, len(budget))
+	for _, instruction := range budget {
+		ix, err := instruction(ctx, tb.client)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, ix...)
+	}
+
+	return result, nil
+}
+
+// writableAccounts returns the unique base58 encoded writable accounts of the instructions.
+func writableAccounts(txInstructions []types.Instruction) []string {
+	seen := make(map[common.PublicKey]struct{})
+	result := make([]string, 0)
+	for _, instruction := range txInstructions {
+		for _, account := range instruction.Accounts {
+			if !account.IsWritable {
+				continue
+			}
+			if _, ok := seen[account.PubKey]; ok {
+				continue
+			}
+			seen[account.PubKey] = struct{}{}
+			result = append(result, account.PubKey.ToBase58())
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,212 @@
+package transaction_test
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/program/compute_budget"
+	"github.com/EntySquare/solana-go-sdk/program/token"
+	"github.com/EntySquare/solana-go-sdk/types"
+	"github.com/EntySquare/solana/client"
+	"github.com/EntySquare/solana/instructions"
+	"github.com/EntySquare/solana/token_metadata"
+	"github.com/EntySquare/solana/transaction"
+	"github.com/EntySquare/solana/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient builds transactions locally and simulates them with a fixed compute usage.
+type fakeClient struct {
+	unitsConsumed uint64
+	unitPrice     uint64
+	simulated     []string
+	simulationErr *client.TransactionError
+}
+
+func (c *fakeClient) DefaultDecimals() uint8 { return 9 }
+
+func (c *fakeClient) GetMinimumBalanceForRentExemption(context.Context, uint64) (uint64, error) {
+	return 0, nil
+}
+
+func (c *fakeClient) GetTokenAccountInfo(context.Context, string) (token.TokenAccount, error) {
+	return token.TokenAccount{}, nil
+}
+
+func (c *fakeClient) GetMintInfo(context.Context, string) (token.MintAccount, error) {
+	return token.MintAccount{}, nil
+}
+
+func (c *fakeClient) GetTokenProgramID(context.Context, string) (common.PublicKey, error) {
+	return common.TokenProgramID, nil
+}
+
+func (c *fakeClient) GetTokenMetadata(context.Context, string) (*token_metadata.Metadata, error) {
+	return nil, nil
+}
+
+func (c *fakeClient) GetMasterEditionSupply(context.Context, common.PublicKey) (uint64, uint64, error) {
+	return 0, 0, nil
+}
+
+func (c *fakeClient) GetEditionInfo(context.Context, string) (*token_metadata.Edition, error) {
+	return nil, nil
+}
+
+func (c *fakeClient) GetSlot(context.Context) (uint64, error) { return 1, nil }
+
+func (c *fakeClient) GetLookupTableAddresses(context.Context, string) ([]common.PublicKey, error) {
+	return nil, nil
+}
+
+func (c *fakeClient) EstimateComputeUnitPrice(context.Context, ...string) (uint64, error) {
+	return c.unitPrice, nil
+}
+
+func (c *fakeClient) SimulateTransaction(_ context.Context, txSource string, _ client.SimulateTransactionOptions) (client.SimulateTransactionResult, error) {
+	c.simulated = append(c.simulated, txSource)
+	return client.SimulateTransactionResult{UnitsConsumed: c.unitsConsumed, Err: c.simulationErr}, nil
+}
+
+func (c *fakeClient) NewTransaction(_ context.Context, params client.NewTransactionParams) (string, error) {
+	tx, err := types.NewTransaction(types.NewTransactionParam{
+		Message: types.NewMessage(types.NewMessageParam{
+			FeePayer:        params.FeePayer,
+			RecentBlockhash: "9rAtxuhtKn8qagc3UtZFyhLrw5zgh6Fk6ExQsV5xKzto",
+			Instructions:    params.Instructions,
+		}),
+		Signers: params.Signers,
+	})
+	if err != nil {
+		return "", err
+	}
+	return utils.EncodeTransaction(tx)
+}
+
+func (c *fakeClient) NewDurableTransaction(context.Context, client.NewDurableTransactionParams) (string, error) {
+	return "", nil
+}
+
+func TestTransactionBuilder_ComputeBudget(t *testing.T) {
+	feePayer := types.NewAccount()
+	recipient := types.NewAccount().PublicKey
+
+	tests := []struct {
+		name          string
+		configure     func(tb *transaction.TransactionBuilder)
+		unitPrice     uint64 // estimated compute unit price
+		wantLimit     *uint32
+		wantPrice     *uint64
+		wantSimulated int
+	}{
+		{
+			name:      "no compute budget",
+			configure: func(tb *transaction.TransactionBuilder) {},
+		},
+		{
+			name: "fixed limit and price",
+			configure: func(tb *transaction.TransactionBuilder) {
+				tb.SetComputeUnitLimit(100_000).SetComputeUnitPrice(5_000)
+			},
+			wantLimit: utils.Pointer[uint32](100_000),
+			wantPrice: utils.Pointer[uint64](5_000),
+		},
+		{
+			name: "auto price capped",
+			configure: func(tb *transaction.TransactionBuilder) {
+				tb.SetAutoComputeUnitPrice(1_000)
+			},
+			unitPrice: 2_000,
+			wantPrice: utils.Pointer[uint64](1_000),
+		},
+		{
+			name: "auto price not capped",
+			configure: func(tb *transaction.TransactionBuilder) {
+				tb.SetAutoComputeUnitPrice(0)
+			},
+			unitPrice: 2_000,
+			wantPrice: utils.Pointer[uint64](2_000),
+		},
+		{
+			name: "auto price zero is skipped",
+			configure: func(tb *transaction.TransactionBuilder) {
+				tb.SetAutoComputeUnitPrice(1_000)
+			},
+		},
+		{
+			name: "auto price zero with fixed limit",
+			configure: func(tb *transaction.TransactionBuilder) {
+				tb.SetComputeUnitLimit(100_000).SetAutoComputeUnitPrice(0)
+			},
+			wantLimit: utils.Pointer[uint32](100_000),
+		},
+		{
+			name: "auto limit with margin",
+			configure: func(tb *transaction.TransactionBuilder) {
+				tb.SetAutoComputeUnitLimit(10).SetPreflight(true)
+			},
+			wantLimit:     utils.Pointer[uint32](55_000),
+			wantSimulated: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &fakeClient{unitsConsumed: 50_000, unitPrice: tt.unitPrice}
+			tb := transaction.NewTransactionBuilder(c).
+				SetFeePayer(feePayer.PublicKey).
+				AddSigner(feePayer).
+				AddInstruction(instructions.TransferSOL(instructions.TransferSOLParams{
+					Sender:    feePayer.PublicKey,
+					Recipient: recipient,
+					Amount:    1,
+				}))
+			tt.configure(tb)
+
+			txSource, err := tb.Build(context.Background())
+			require.NoError(t, err)
+			require.Len(t, c.simulated, tt.wantSimulated)
+
+			tx, err := utils.DecodeTransaction(txSource)
+			require.NoError(t, err)
+			ixs := tx.Message.DecompileInstructions()
+
+			var gotLimit *uint32
+			var gotPrice *uint64
+			for i, ix := range ixs {
+				if ix.ProgramID != common.ComputeBudgetProgramID {
+					continue
+				}
+				require.Less(t, i, 2, "compute budget instructions must be at the front")
+				switch compute_budget.Instruction(ix.Data[0]) {
+				case compute_budget.InstructionSetComputeUnitLimit:
+					gotLimit = utils.Pointer[uint32](binary.LittleEndian.Uint32(ix.Data[1:]))
+				case compute_budget.InstructionSetComputeUnitPrice:
+					gotPrice = utils.Pointer[uint64](binary.LittleEndian.Uint64(ix.Data[1:]))
+				}
+			}
+			require.Equal(t, tt.wantLimit, gotLimit)
+			require.Equal(t, tt.wantPrice, gotPrice)
+			require.Equal(t, common.SystemProgramID, ixs[len(ixs)-1].ProgramID)
+		})
+	}
+}
+
+func TestTransactionBuilder_PreflightFailure(t *testing.T) {
+	feePayer := types.NewAccount()
+	c := &fakeClient{simulationErr: &client.TransactionError{Name: "InstructionError", InstructionError: "Custom"}}
+
+	_, err := transaction.NewTransactionBuilder(c).
+		SetFeePayer(feePayer.PublicKey).
+		AddSigner(feePayer).
+		AddInstruction(instructions.TransferSOL(instructions.TransferSOLParams{
+			Sender:    feePayer.PublicKey,
+			Recipient: types.NewAccount().PublicKey,
+			Amount:    1,
+		})).
+		SetPreflight(true).
+		Build(context.Background())
+	require.ErrorIs(t, err, client.ErrSimulationFailed)
+	require.Len(t, c.simulated, 1)
+}
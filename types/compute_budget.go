@@ -0,0 +1,8 @@
+package types
+
+// Compute budget limits
+const (
+	MaxComputeUnitLimit      uint32 = 1_400_000 // max compute units per transaction
+	MaxPrioritizationFeeKeys int    = 128       // max accounts accepted by getRecentPrioritizationFees
+	PriorityFeePercentile    uint8  = 75        // percentile of recent fees used to estimate the compute unit price
+)
@@ -0,0 +1,56 @@
+package types
+
+import "github.com/EntySquare/solana-go-sdk/common"
+
+// HistoryType is the classification of a wallet transaction.
+type HistoryType string
+
+// HistoryType enum.
+const (
+	HistoryTypeSOLIn          HistoryType = "sol_in"
+	HistoryTypeSOLOut         HistoryType = "sol_out"
+	HistoryTypeTokenIn        HistoryType = "token_in"
+	HistoryTypeTokenOut       HistoryType = "token_out"
+	HistoryTypeNFTMint        HistoryType = "nft_mint"
+	HistoryTypeNFTBurn        HistoryType = "nft_burn"
+	HistoryTypeNFTTransferIn  HistoryType = "nft_transfer_in"
+	HistoryTypeNFTTransferOut HistoryType = "nft_transfer_out"
+	HistoryTypeFee            HistoryType = "fee" // the wallet only paid the fee, e.g. of a failed transaction
+	HistoryTypeOther          HistoryType = "other"
+)
+
+// Limits of the wallet history page.
+const (
+	DefaultHistoryLimit = 20
+	MaxHistoryLimit     = 100
+)
+
+type (
+	// HistoryCursor selects the page of the wallet history; the history is ordered from the newest transaction.
+	// Before pages back from the signature, Until fetches incrementally the transactions newer than the signature.
+	HistoryCursor struct {
+		Before string `json:"before,omitempty"` // returns the transactions older than the signature
+		Until  string `json:"until,omitempty"`  // returns the transactions newer than the signature
+	}
+
+	// WalletHistory is a page of the wallet history.
+	WalletHistory struct {
+		Transactions []WalletTransaction `json:"transactions"`
+		Next         HistoryCursor       `json:"next"`     // cursor of the next older page
+		HasMore      bool                `json:"has_more"` // there are more transactions for the next cursor
+	}
+
+	// WalletTransaction is the transaction of the wallet history classified from the wallet point of view.
+	WalletTransaction struct {
+		Signature    string            `json:"signature"`
+		Slot         uint64            `json:"slot"`
+		BlockTime    *int64            `json:"block_time,omitempty"` // unix time, nil if unknown
+		Type         HistoryType       `json:"type"`
+		Amount       TokenAmount       `json:"amount"`                 // amount of SOL or the token moved; zero for the fee and other types
+		Mint         *common.PublicKey `json:"mint,omitempty"`         // nil for SOL
+		Counterparty *common.PublicKey `json:"counterparty,omitempty"` // the other wallet of the transfer, nil if unknown
+		Fee          uint64            `json:"fee"`                    // lamports paid by the wallet, zero if another account paid the fee
+		Memo         string            `json:"memo,omitempty"`
+		Failed       bool              `json:"failed"`
+	}
+)
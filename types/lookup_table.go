@@ -5,3 +5,9 @@ const (
 	LookupTableMetaSize     uint64 = 56  // 56 bytes
 	LookupTableMaxAddresses uint   = 256 // 256 addresses
 )
+
+// Transaction size limits
+const (
+	// MaxTransactionSize is the maximum size of a serialized transaction (IPv6 MTU minus headers).
+	MaxTransactionSize int = 1232 // 1232 bytes
+)
@@ -0,0 +1,92 @@
+package types
+
+import (
+	"time"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+)
+
+// PaymentStatus is the verdict of the payment validation.
+type PaymentStatus string
+
+// PaymentStatus enum.
+const (
+	PaymentStatusNotFound  PaymentStatus = "not_found" // no transaction references the payment yet
+	PaymentStatusValid     PaymentStatus = "valid"     // exactly one transaction pays the request
+	PaymentStatusInvalid   PaymentStatus = "invalid"   // the reference is used, but no transaction pays the request
+	PaymentStatusDuplicate PaymentStatus = "duplicate" // several transactions pay the request
+)
+
+// PaymentEventType is the type of the event of the watched payment.
+type PaymentEventType string
+
+// PaymentEventType enum.
+const (
+	PaymentEventPending   PaymentEventType = "pending"   // a transaction of the reference waits for the commitment
+	PaymentEventConfirmed PaymentEventType = "confirmed" // the transaction paid the exact amount; final
+	PaymentEventUnderpaid PaymentEventType = "underpaid" // the transaction paid less than the amount; the watch goes on
+	PaymentEventOverpaid  PaymentEventType = "overpaid"  // the transaction paid more than the amount; final
+	PaymentEventExpired   PaymentEventType = "expired"   // the deadline passed without the payment; final
+	PaymentEventFailed    PaymentEventType = "failed"    // the transaction failed or does not pay the request; the watch goes on
+	PaymentEventDuplicate PaymentEventType = "duplicate" // the transaction paid the payment after its end; to refund
+)
+
+// Final reports whether the event ends the watch of the payment.
+func (t PaymentEventType) Final() bool {
+	return t == PaymentEventConfirmed || t == PaymentEventOverpaid || t == PaymentEventExpired
+}
+
+// MaxPaymentCandidates is the max number of the transactions of a payment reference that are fetched at once.
+const MaxPaymentCandidates = 100
+
+type (
+	// PaymentRequest is the payment expected for the reference, e.g. the Solana Pay transfer request.
+	PaymentRequest struct {
+		Recipient common.PublicKey  // wallet of the merchant; the tokens are expected in its associated token account
+		Amount    uint64            // lamports or the token base units
+		SPLToken  *common.PublicKey // mint of the token, nil for SOL
+		Reference common.PublicKey  // the unique account added to the payment transaction
+		Memo      *string           // expected memo, nil to skip the check
+	}
+
+	// ExpectedPayment is the payment of the order watched by the PaymentWatcher.
+	ExpectedPayment struct {
+		PaymentRequest
+		ID       string    // optional; the order id, default is the base58 encoded reference
+		Deadline time.Time // optional; the payment expires after it, zero never expires
+	}
+
+	// PaymentEvent is the event of the watched payment.
+	PaymentEvent struct {
+		Type      PaymentEventType
+		Payment   ExpectedPayment
+		Signature string           // the transaction of the event, empty for the expired one
+		Payer     common.PublicKey // the payer of the transaction, if any
+		Amount    uint64           // lamports or the token base units transferred to the recipient
+		Err       error            // why the transaction failed, for the failed and the duplicate events
+	}
+
+	// PaymentCandidate is a transaction of the payment reference with its validation result.
+	PaymentCandidate struct {
+		Signature string           `json:"signature"`
+		Slot      uint64           `json:"slot"`
+		BlockTime *int64           `json:"block_time,omitempty"` // unix time, nil if unknown
+		Payer     common.PublicKey `json:"payer"`                // the authority of the first transfer to the recipient, or the fee payer
+		Amount    uint64           `json:"amount"`               // lamports or the token base units transferred to the recipient
+		Reason    string           `json:"reason,omitempty"`     // why the transaction is not a valid payment
+		Err       error            `json:"-"`                    // nil if the transaction is a valid payment
+	}
+
+	// PaymentVerdict is the result of the payment validation.
+	PaymentVerdict struct {
+		Status     PaymentStatus      `json:"status"`
+		Signature  string             `json:"signature,omitempty"`  // the oldest valid payment, empty if none
+		Duplicates []string           `json:"duplicates,omitempty"` // the valid payments after the first one, to refund
+		Candidates []PaymentCandidate `json:"candidates"`           // all the transactions of the reference, from the oldest one
+	}
+)
+
+// Valid reports whether the candidate is a valid payment.
+func (c PaymentCandidate) Valid() bool {
+	return c.Err == nil
+}
@@ -54,16 +54,13 @@ func (e *StakedError) Error() string {
 
 // Unwrap returns the underlying error.
 // Implements the errors.Unwrap interface.
+// Returns nil for multiple errors, they are matched by Is and As.
 func (e *StakedError) Unwrap() error {
-	if len(e.errors) == 0 {
-		return nil
-	}
-
 	if len(e.errors) == 1 {
 		return e.errors[0]
 	}
 
-	return e
+	return nil
 }
 
 // Is returns true if the error is equal to target.
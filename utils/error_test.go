@@ -0,0 +1,25 @@
+package utils_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/EntySquare/solana/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStackErrors(t *testing.T) {
+	errA := errors.New("a")
+	errB := errors.New("b")
+	errC := errors.New("c")
+
+	err := utils.StackErrors(errA, errB)
+	require.Equal(t, "a: b", err.Error())
+	require.ErrorIs(t, err, errA)
+	require.ErrorIs(t, err, errB)
+	require.NotErrorIs(t, err, errC)
+
+	nested := utils.StackErrors(errC, err)
+	require.ErrorIs(t, nested, errB)
+	require.NotErrorIs(t, utils.StackErrors(errC), errA)
+}
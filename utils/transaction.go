@@ -16,13 +16,21 @@ func EncodeTransaction(tx types.Transaction) (string, error) {
 }
 
 // DecodeTransaction returns a transaction from a base64 encoded transaction.
-func DecodeTransaction(base64Tx string) (types.Transaction, error) {
+// Supports both legacy and versioned (v0) messages.
+func DecodeTransaction(base64Tx string) (tx types.Transaction, err error) {
 	txb, err := Base64ToBytes(base64Tx)
 	if err != nil {
 		return types.Transaction{}, errors.Wrap(err, "failed to deserialize transaction: base64 to bytes")
 	}
 
-	tx, err := types.TransactionDeserialize(txb)
+	// the sdk deserializer does not check bounds of the address lookup tables section of v0 messages
+	defer func() {
+		if r := recover(); r != nil {
+			tx, err = types.Transaction{}, errors.Errorf("failed to deserialize transaction: malformed message: %v", r)
+		}
+	}()
+
+	tx, err = types.TransactionDeserialize(txb)
 	if err != nil {
 		return types.Transaction{}, errors.Wrap(err, "failed to deserialize transaction: deserialize")
 	}
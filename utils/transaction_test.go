@@ -0,0 +1,114 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/EntySquare/solana-go-sdk/common"
+	"github.com/EntySquare/solana-go-sdk/program/system"
+	"github.com/EntySquare/solana-go-sdk/types"
+	"github.com/EntySquare/solana/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionRoundTrip(t *testing.T) {
+	feePayer := types.NewAccount()
+	recipient := types.NewAccount().PublicKey
+	table := types.AddressLookupTableAccount{
+		Key:       types.NewAccount().PublicKey,
+		Addresses: []common.PublicKey{recipient},
+	}
+	blockhash := "9rAtxuhtKn8qagc3UtZFyhLrw5zgh6Fk6ExQsV5xKzto"
+
+	tests := []struct {
+		name    string
+		tables  []types.AddressLookupTableAccount
+		version types.MessageVersion
+	}{
+		{
+			name:    "legacy",
+			version: types.MessageVersionLegacy,
+		},
+		{
+			name:    "v0 with lookup table",
+			tables:  []types.AddressLookupTableAccount{table},
+			version: types.MessageVersionV0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := types.NewTransaction(types.NewTransactionParam{
+				Message: types.NewMessage(types.NewMessageParam{
+					FeePayer:        feePayer.PublicKey,
+					RecentBlockhash: blockhash,
+					Instructions: []types.Instruction{
+						system.Transfer(system.TransferParam{
+							From:   feePayer.PublicKey,
+							To:     recipient,
+							Amount: 1,
+						}),
+					},
+					AddressLookupTableAccounts: tt.tables,
+				}),
+			})
+			require.NoError(t, err)
+
+			encoded, err := utils.EncodeTransaction(tx)
+			require.NoError(t, err)
+
+			decoded, err := utils.DecodeTransaction(encoded)
+			require.NoError(t, err)
+			require.EqualValues(t, tt.version, decoded.Message.Version)
+			require.Equal(t, blockhash, decoded.Message.RecentBlockHash)
+
+			if len(tt.tables) > 0 {
+				require.Len(t, decoded.Message.AddressLookupTables, 1)
+				require.Equal(t, table.Key, decoded.Message.AddressLookupTables[0].AccountKey)
+				require.NotContains(t, decoded.Message.Accounts, recipient)
+			}
+
+			// sign the decoded transaction and make sure it survives another round trip
+			msg, err := decoded.Message.Serialize()
+			require.NoError(t, err)
+			require.NoError(t, decoded.AddSignature(feePayer.Sign(msg)))
+
+			signed, err := utils.EncodeTransaction(decoded)
+			require.NoError(t, err)
+
+			redecoded, err := utils.DecodeTransaction(signed)
+			require.NoError(t, err)
+			require.EqualValues(t, tt.version, redecoded.Message.Version)
+			require.EqualValues(t, feePayer.Sign(msg), redecoded.Signatures[0])
+		})
+	}
+}
+
+func TestDecodeTransaction_Malformed(t *testing.T) {
+	// a v0 message which is cut in the middle of the lookup table key
+	feePayer := types.NewAccount()
+	recipient := types.NewAccount().PublicKey
+	tx, err := types.NewTransaction(types.NewTransactionParam{
+		Message: types.NewMessage(types.NewMessageParam{
+			FeePayer:        feePayer.PublicKey,
+			RecentBlockhash: "9rAtxuhtKn8qagc3UtZFyhLrw5zgh6Fk6ExQsV5xKzto",
+			Instructions: []types.Instruction{
+				system.Transfer(system.TransferParam{
+					From:   feePayer.PublicKey,
+					To:     recipient,
+					Amount: 1,
+				}),
+			},
+			AddressLookupTableAccounts: []types.AddressLookupTableAccount{{
+				Key:       types.NewAccount().PublicKey,
+				Addresses: []common.PublicKey{recipient},
+			}},
+		}),
+	})
+	require.NoError(t, err)
+
+	txb, err := tx.Serialize()
+	require.NoError(t, err)
+	txb = txb[:len(txb)-20]
+
+	_, err = utils.DecodeTransaction(utils.BytesToBase64(txb))
+	require.Error(t, err)
+}